@@ -0,0 +1,90 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconvert
+
+import (
+	"fmt"
+
+	celpv "buf.build/go/protovalidate/cel"
+	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// MessageFilter decides which messages of a stream to keep.
+type MessageFilter interface {
+	// Matches returns true if the message matches the filter.
+	Matches(message proto.Message) (bool, error)
+}
+
+// NewMessageFilter returns a new MessageFilter for the CEL expression.
+//
+// The message is bound to the variable "this" when evaluating the expression,
+// which must evaluate to a bool. The functions that protovalidate adds to CEL,
+// such as isEmail, are available.
+func NewMessageFilter(
+	messageDescriptor protoreflect.MessageDescriptor,
+	expression string,
+) (MessageFilter, error) {
+	return newMessageFilter(messageDescriptor, expression)
+}
+
+// *** PRIVATE ***
+
+type messageFilter struct {
+	program cel.Program
+}
+
+func newMessageFilter(
+	messageDescriptor protoreflect.MessageDescriptor,
+	expression string,
+) (*messageFilter, error) {
+	celEnv, err := cel.NewEnv(
+		cel.Lib(celpv.NewLibrary()),
+		cel.Types(dynamicpb.NewMessage(messageDescriptor)),
+		cel.Variable("this", cel.ObjectType(string(messageDescriptor.FullName()))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := celEnv.Compile(expression)
+	if err := issues.Err(); err != nil {
+		return nil, fmt.Errorf("invalid filter expression %q: %w", expression, err)
+	}
+	if !ast.OutputType().IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf("filter expression %q must evaluate to a bool, not %s", expression, ast.OutputType())
+	}
+	program, err := celEnv.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &messageFilter{
+		program: program,
+	}, nil
+}
+
+func (m *messageFilter) Matches(message proto.Message) (bool, error) {
+	value, _, err := m.program.Eval(map[string]any{"this": message})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate filter expression: %w", err)
+	}
+	matches, ok := value.Value().(bool)
+	if !ok {
+		// This should not happen as the output type is checked when compiling.
+		return false, fmt.Errorf("filter expression evaluated to %v, not a bool", value)
+	}
+	return matches, nil
+}
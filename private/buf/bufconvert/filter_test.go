@@ -0,0 +1,51 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconvert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestMessageFilter(t *testing.T) {
+	t.Parallel()
+	messageDescriptor := (&descriptorpb.FileDescriptorProto{}).ProtoReflect().Descriptor()
+	messageFilter, err := NewMessageFilter(messageDescriptor, `this.name.endsWith(".proto") && size(this.dependency) > 0`)
+	require.NoError(t, err)
+	matches, err := messageFilter.Matches(
+		&descriptorpb.FileDescriptorProto{
+			Name:       proto.String("foo.proto"),
+			Dependency: []string{"bar.proto"},
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, matches)
+	matches, err = messageFilter.Matches(
+		&descriptorpb.FileDescriptorProto{
+			Name: proto.String("foo.proto"),
+		},
+	)
+	require.NoError(t, err)
+	assert.False(t, matches)
+
+	_, err = NewMessageFilter(messageDescriptor, `this.name`)
+	require.ErrorContains(t, err, "must evaluate to a bool")
+	_, err = NewMessageFilter(messageDescriptor, `this.unknown == 1`)
+	require.ErrorContains(t, err, "invalid filter expression")
+}
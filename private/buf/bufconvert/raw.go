@@ -0,0 +1,346 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconvert
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxRawDepth is the maximum depth of embedded messages and groups that are decoded.
+//
+// Length-delimited values that are nested deeper than this are not decoded as messages.
+const maxRawDepth = 64
+
+// RawField is a field decoded from the binary wire format without a schema.
+type RawField struct {
+	// Number is the field number.
+	Number protowire.Number
+	// WireType is the wire type of the field.
+	WireType protowire.Type
+	// Uint is the value of varint, fixed32 and fixed64 fields.
+	Uint uint64
+	// Bytes is the value of length-delimited fields.
+	Bytes []byte
+	// Fields are the fields of groups, and of length-delimited fields whose
+	// value could be decoded as a message.
+	//
+	// This is nil for length-delimited fields whose value is not a message.
+	Fields []*RawField
+}
+
+// DecodeRaw decodes the fields of a message in the binary wire format without a schema,
+// in the same way as "protoc --decode_raw".
+//
+// Length-delimited values that can be decoded as a message are assumed to be embedded
+// messages, and their fields are decoded recursively.
+func DecodeRaw(data []byte) ([]*RawField, error) {
+	return decodeRaw(data, 0)
+}
+
+// PrintRaw prints the fields decoded by DecodeRaw, one field per line, with the field
+// number, the wire type and the value.
+func PrintRaw(writer io.Writer, fields []*RawField) error {
+	var builder strings.Builder
+	printRaw(&builder, fields, "")
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// MessageTypeMatch is a message type that fields decoded by DecodeRaw were matched against.
+type MessageTypeMatch struct {
+	// MessageDescriptor is the message type.
+	MessageDescriptor protoreflect.MessageDescriptor
+	// MatchedFields is the number of fields that fit the message type, including
+	// the fields of embedded messages.
+	MatchedFields int
+	// TotalFields is the number of fields that were matched against the message type,
+	// including the fields of embedded messages.
+	TotalFields int
+}
+
+// MatchMessageTypes matches the fields decoded by DecodeRaw against the message types
+// of the non-import files of the image.
+//
+// A field fits a message type if the message type declares a field with the same number
+// and a type that is compatible with the wire type. Repeated occurrences of a field count
+// as a single field.
+//
+// The returned matches are sorted from the best to the worst fit. Message types that none
+// of the fields fit are not returned.
+func MatchMessageTypes(image bufimage.Image, fields []*RawField) ([]MessageTypeMatch, error) {
+	var messageDescriptors []protoreflect.MessageDescriptor
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileDescriptor, err := image.Resolver().FindFileByPath(imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		messageDescriptors = appendMessageDescriptors(messageDescriptors, fileDescriptor.Messages())
+	}
+	var messageTypeMatches []MessageTypeMatch
+	for _, messageDescriptor := range messageDescriptors {
+		matchedFields, totalFields := matchRawFields(messageDescriptor, fields, 0)
+		if matchedFields == 0 {
+			continue
+		}
+		messageTypeMatches = append(
+			messageTypeMatches,
+			MessageTypeMatch{
+				MessageDescriptor: messageDescriptor,
+				MatchedFields:     matchedFields,
+				TotalFields:       totalFields,
+			},
+		)
+	}
+	slices.SortFunc(
+		messageTypeMatches,
+		func(one MessageTypeMatch, two MessageTypeMatch) int {
+			// Compare the ratios of matched fields without dividing.
+			return cmp.Or(
+				cmp.Compare(two.MatchedFields*one.TotalFields, one.MatchedFields*two.TotalFields),
+				cmp.Compare(two.MatchedFields, one.MatchedFields),
+				// Prefer the message types with fewer fields that were not used.
+				cmp.Compare(one.MessageDescriptor.Fields().Len(), two.MessageDescriptor.Fields().Len()),
+				cmp.Compare(one.MessageDescriptor.FullName(), two.MessageDescriptor.FullName()),
+			)
+		},
+	)
+	return messageTypeMatches, nil
+}
+
+// *** PRIVATE ***
+
+func decodeRaw(data []byte, depth int) ([]*RawField, error) {
+	fields := []*RawField{}
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		field := &RawField{
+			Number:   number,
+			WireType: wireType,
+		}
+		switch wireType {
+		case protowire.VarintType:
+			field.Uint, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var value uint32
+			value, n = protowire.ConsumeFixed32(data)
+			field.Uint = uint64(value)
+		case protowire.Fixed64Type:
+			field.Uint, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			field.Bytes, n = protowire.ConsumeBytes(data)
+			if n >= 0 && len(field.Bytes) > 0 && depth < maxRawDepth {
+				if embeddedFields, err := decodeRaw(field.Bytes, depth+1); err == nil {
+					field.Fields = embeddedFields
+				}
+			}
+		case protowire.StartGroupType:
+			if depth >= maxRawDepth {
+				return nil, errors.New("groups are nested too deeply")
+			}
+			var value []byte
+			value, n = protowire.ConsumeGroup(number, data)
+			if n >= 0 {
+				groupFields, err := decodeRaw(value, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				field.Fields = groupFields
+			}
+		default:
+			return nil, fmt.Errorf("unexpected wire type %d for field %d", wireType, number)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func printRaw(builder *strings.Builder, fields []*RawField, indent string) {
+	for _, field := range fields {
+		_, _ = fmt.Fprintf(builder, "%s%d (%s)", indent, field.Number, wireTypeString(field.WireType))
+		switch {
+		case field.Fields != nil:
+			builder.WriteString(" {\n")
+			printRaw(builder, field.Fields, indent+"  ")
+			builder.WriteString(indent + "}\n")
+		case field.WireType == protowire.BytesType:
+			builder.WriteString(": " + strconv.Quote(string(field.Bytes)) + "\n")
+		case field.WireType == protowire.Fixed32Type:
+			_, _ = fmt.Fprintf(builder, ": 0x%08x\n", field.Uint)
+		case field.WireType == protowire.Fixed64Type:
+			_, _ = fmt.Fprintf(builder, ": 0x%016x\n", field.Uint)
+		default:
+			builder.WriteString(": " + strconv.FormatUint(field.Uint, 10) + "\n")
+		}
+	}
+}
+
+func wireTypeString(wireType protowire.Type) string {
+	switch wireType {
+	case protowire.VarintType:
+		return "varint"
+	case protowire.Fixed32Type:
+		return "i32"
+	case protowire.Fixed64Type:
+		return "i64"
+	case protowire.BytesType:
+		return "len"
+	case protowire.StartGroupType:
+		return "group"
+	default:
+		return strconv.Itoa(int(wireType))
+	}
+}
+
+func appendMessageDescriptors(
+	messageDescriptors []protoreflect.MessageDescriptor,
+	messages protoreflect.MessageDescriptors,
+) []protoreflect.MessageDescriptor {
+	for i := range messages.Len() {
+		messageDescriptor := messages.Get(i)
+		if messageDescriptor.IsMapEntry() {
+			continue
+		}
+		messageDescriptors = append(messageDescriptors, messageDescriptor)
+		messageDescriptors = appendMessageDescriptors(messageDescriptors, messageDescriptor.Messages())
+	}
+	return messageDescriptors
+}
+
+// matchRawFields returns the number of fields that fit the message type, and the
+// number of fields that were matched, including the fields of embedded messages.
+func matchRawFields(
+	messageDescriptor protoreflect.MessageDescriptor,
+	fields []*RawField,
+	depth int,
+) (int, int) {
+	var matchedFields, totalFields int
+	fieldsByNumber := make(map[protowire.Number][]*RawField)
+	var numbers []protowire.Number
+	for _, field := range fields {
+		if _, ok := fieldsByNumber[field.Number]; !ok {
+			numbers = append(numbers, field.Number)
+		}
+		fieldsByNumber[field.Number] = append(fieldsByNumber[field.Number], field)
+	}
+	for _, number := range numbers {
+		totalFields++
+		fieldDescriptor := messageDescriptor.Fields().ByNumber(number)
+		if fieldDescriptor == nil {
+			continue
+		}
+		fits := true
+		var embeddedMatchedFields, embeddedTotalFields int
+		for _, field := range fieldsByNumber[number] {
+			if !rawFieldFitsWireType(fieldDescriptor, field) {
+				fits = false
+				break
+			}
+			if fieldDescriptor.Message() != nil && depth < maxRawDepth {
+				matched, total := matchRawFields(fieldDescriptor.Message(), field.Fields, depth+1)
+				embeddedMatchedFields += matched
+				embeddedTotalFields += total
+			}
+		}
+		totalFields += embeddedTotalFields
+		if fits {
+			matchedFields += 1 + embeddedMatchedFields
+		}
+	}
+	return matchedFields, totalFields
+}
+
+func rawFieldFitsWireType(fieldDescriptor protoreflect.FieldDescriptor, field *RawField) bool {
+	switch kind := fieldDescriptor.Kind(); field.WireType {
+	case protowire.VarintType:
+		return isVarintKind(kind)
+	case protowire.Fixed32Type:
+		return isFixed32Kind(kind)
+	case protowire.Fixed64Type:
+		return isFixed64Kind(kind)
+	case protowire.BytesType:
+		switch kind {
+		case protoreflect.StringKind:
+			return utf8.Valid(field.Bytes)
+		case protoreflect.BytesKind:
+			return true
+		case protoreflect.MessageKind:
+			// An empty embedded message has no fields.
+			return field.Fields != nil || len(field.Bytes) == 0
+		default:
+			// Repeated scalar fields may be packed.
+			return fieldDescriptor.IsList() && (isVarintKind(kind) || isFixed32Kind(kind) || isFixed64Kind(kind))
+		}
+	case protowire.StartGroupType:
+		return kind == protoreflect.GroupKind
+	default:
+		return false
+	}
+}
+
+func isVarintKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.BoolKind,
+		protoreflect.EnumKind,
+		protoreflect.Int32Kind,
+		protoreflect.Sint32Kind,
+		protoreflect.Uint32Kind,
+		protoreflect.Int64Kind,
+		protoreflect.Sint64Kind,
+		protoreflect.Uint64Kind:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFixed32Kind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFixed64Kind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return true
+	default:
+		return false
+	}
+}
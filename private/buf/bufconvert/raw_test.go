@@ -0,0 +1,115 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconvert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestDecodeRaw(t *testing.T) {
+	t.Parallel()
+	var embedded []byte
+	embedded = protowire.AppendTag(embedded, 1, protowire.Fixed64Type)
+	embedded = protowire.AppendFixed64(embedded, 0x3ff0000000000000)
+	var data []byte
+	data = protowire.AppendTag(data, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, 150)
+	data = protowire.AppendTag(data, 2, protowire.BytesType)
+	data = protowire.AppendString(data, "testing")
+	data = protowire.AppendTag(data, 3, protowire.BytesType)
+	data = protowire.AppendBytes(data, embedded)
+	data = protowire.AppendTag(data, 4, protowire.Fixed32Type)
+	data = protowire.AppendFixed32(data, 1)
+	data = protowire.AppendTag(data, 5, protowire.StartGroupType)
+	data = protowire.AppendTag(data, 1, protowire.BytesType)
+	data = protowire.AppendBytes(data, nil)
+	data = protowire.AppendTag(data, 5, protowire.EndGroupType)
+	fields, err := DecodeRaw(data)
+	require.NoError(t, err)
+	var builder strings.Builder
+	require.NoError(t, PrintRaw(&builder, fields))
+	assert.Equal(
+		t,
+		`1 (varint): 150
+2 (len): "testing"
+3 (len) {
+  1 (i64): 0x3ff0000000000000
+}
+4 (i32): 0x00000001
+5 (group) {
+  1 (len): ""
+}
+`,
+		builder.String(),
+	)
+
+	_, err = DecodeRaw(data[:len(data)-1])
+	require.Error(t, err)
+	_, err = DecodeRaw([]byte{0})
+	require.Error(t, err)
+}
+
+func TestMatchMessageTypes(t *testing.T) {
+	t.Parallel()
+	file := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+	imageFile, err := bufimage.NewImageFile(
+		file,
+		nil,
+		uuid.UUID{},
+		file.GetName(),
+		file.GetName(),
+		false,
+		false,
+		nil,
+	)
+	require.NoError(t, err)
+	image, err := bufimage.NewImage([]bufimage.ImageFile{imageFile})
+	require.NoError(t, err)
+
+	data, err := proto.Marshal(
+		&descriptorpb.FieldDescriptorProto{
+			Name:     proto.String("foo"),
+			Number:   proto.Int32(1),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".foo.Bar"),
+			Options: &descriptorpb.FieldOptions{
+				Deprecated: proto.Bool(true),
+			},
+		},
+	)
+	require.NoError(t, err)
+	fields, err := DecodeRaw(data)
+	require.NoError(t, err)
+	messageTypeMatches, err := MatchMessageTypes(image, fields)
+	require.NoError(t, err)
+	require.NotEmpty(t, messageTypeMatches)
+	assert.Equal(t, "google.protobuf.FieldDescriptorProto", string(messageTypeMatches[0].MessageDescriptor.FullName()))
+	assert.Equal(t, 7, messageTypeMatches[0].MatchedFields)
+	assert.Equal(t, 7, messageTypeMatches[0].TotalFields)
+	for _, messageTypeMatch := range messageTypeMatches[1:] {
+		assert.Less(t, messageTypeMatch.MatchedFields, messageTypeMatch.TotalFields)
+	}
+}
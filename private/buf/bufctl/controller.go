@@ -142,6 +142,29 @@ type Controller interface {
 		defaultMessageEncoding buffetch.MessageEncoding,
 		options ...FunctionOption,
 	) error
+	// GetMessageReader returns a MessageReader that reads a stream of messages of the given
+	// type from the messageInput, one at a time.
+	//
+	// See MessageReader for how the messages in the stream are delimited.
+	GetMessageReader(
+		ctx context.Context,
+		schemaImage bufimage.Image,
+		messageInput string,
+		typeName string,
+		defaultMessageEncoding buffetch.MessageEncoding,
+		options ...FunctionOption,
+	) (MessageReader, error)
+	// GetMessageWriter returns a MessageWriter that writes a stream of messages to the
+	// messageOutput, one at a time.
+	//
+	// See MessageWriter for how the messages in the stream are delimited.
+	GetMessageWriter(
+		ctx context.Context,
+		schemaImage bufimage.Image,
+		messageOutput string,
+		defaultMessageEncoding buffetch.MessageEncoding,
+		options ...FunctionOption,
+	) (MessageWriter, error)
 	// GetCheckClientForWorkspace returns a new bufcheck Client for the given Workspace.
 	//
 	// Clients are bound to a specific Workspace to ensure that the correct
@@ -702,25 +725,9 @@ func (c *controller) GetMessage(
 		}
 		validator = yamlValidator{protovalidateValidator}
 	}
-	var unmarshaler protoencoding.Unmarshaler
-	switch messageEncoding {
-	case buffetch.MessageEncodingBinpb:
-		unmarshaler = protoencoding.NewWireUnmarshaler(schemaImage.Resolver())
-	case buffetch.MessageEncodingJSON:
-		unmarshaler = protoencoding.NewJSONUnmarshaler(schemaImage.Resolver())
-	case buffetch.MessageEncodingTxtpb:
-		unmarshaler = protoencoding.NewTxtpbUnmarshaler(schemaImage.Resolver())
-	case buffetch.MessageEncodingYAML:
-		unmarshaler = protoencoding.NewYAMLUnmarshaler(
-			schemaImage.Resolver(),
-			protoencoding.YAMLUnmarshalerWithPath(messageRef.Path()),
-			// This will pretty print validation errors.
-			protoencoding.YAMLUnmarshalerWithValidator(validator),
-		)
-		validator = nil // Validation errors are handled by the unmarshaler.
-	default:
-		// This is a system error.
-		return nil, 0, syserror.Newf("unknown MessageEncoding: %v", messageEncoding)
+	unmarshaler, validator, err := newProtoencodingUnmarshaler(schemaImage, messageRef, validator)
+	if err != nil {
+		return nil, 0, err
 	}
 	readCloser, err := c.buffetchReader.GetMessageFile(ctx, c.container, messageRef)
 	if err != nil {
@@ -789,6 +796,115 @@ func (c *controller) PutMessage(
 	return errors.Join(err, writeCloser.Close())
 }
 
+func (c *controller) GetMessageReader(
+	ctx context.Context,
+	schemaImage bufimage.Image,
+	messageInput string,
+	typeName string,
+	defaultMessageEncoding buffetch.MessageEncoding,
+	options ...FunctionOption,
+) (_ MessageReader, retErr error) {
+	defer c.handleFileAnnotationSetRetError(&retErr)
+	functionOptions := newFunctionOptions(c)
+	for _, option := range options {
+		option(functionOptions)
+	}
+	// Must be messageRefParser NOT c.buffetchRefParser as a NewMessageRefParser
+	// defaults to a defaultMessageEncoding and not dir.
+	messageRefParser := buffetch.NewMessageRefParser(
+		c.logger,
+		buffetch.MessageRefParserWithDefaultMessageEncoding(
+			defaultMessageEncoding,
+		),
+	)
+	messageRef, err := messageRefParser.GetMessageRef(ctx, messageInput)
+	if err != nil {
+		return nil, err
+	}
+	message, err := bufreflect.NewMessage(ctx, schemaImage, typeName)
+	if err != nil {
+		return nil, err
+	}
+	var validator protoyaml.Validator
+	if functionOptions.messageValidation {
+		protovalidateValidator, err := protovalidate.New()
+		if err != nil {
+			return nil, err
+		}
+		validator = yamlValidator{protovalidateValidator}
+	}
+	unmarshaler, validator, err := newProtoencodingUnmarshaler(schemaImage, messageRef, validator)
+	if err != nil {
+		return nil, err
+	}
+	if messageRef.IsNull() {
+		return newMessageReader(
+			messageRef.MessageEncoding(),
+			xio.DiscardReadCloser,
+			message.ProtoReflect().Type(),
+			unmarshaler,
+			validator,
+		), nil
+	}
+	readCloser, err := c.buffetchReader.GetMessageFile(ctx, c.container, messageRef)
+	if err != nil {
+		return nil, err
+	}
+	return newMessageReader(
+		messageRef.MessageEncoding(),
+		readCloser,
+		message.ProtoReflect().Type(),
+		unmarshaler,
+		validator,
+	), nil
+}
+
+func (c *controller) GetMessageWriter(
+	ctx context.Context,
+	schemaImage bufimage.Image,
+	messageOutput string,
+	defaultMessageEncoding buffetch.MessageEncoding,
+	options ...FunctionOption,
+) (_ MessageWriter, retErr error) {
+	defer c.handleFileAnnotationSetRetError(&retErr)
+	functionOptions := newFunctionOptions(c)
+	for _, option := range options {
+		option(functionOptions)
+	}
+	// Must be messageRefParser NOT c.buffetchRefParser as a NewMessageRefParser
+	// defaults to a defaultMessageEncoding and not dir.
+	messageRefParser := buffetch.NewMessageRefParser(
+		c.logger,
+		buffetch.MessageRefParserWithDefaultMessageEncoding(
+			defaultMessageEncoding,
+		),
+	)
+	messageRef, err := messageRefParser.GetMessageRef(ctx, messageOutput)
+	if err != nil {
+		return nil, err
+	}
+	marshaler, err := newProtoencodingMarshaler(schemaImage, messageRef)
+	if err != nil {
+		return nil, err
+	}
+	if messageRef.IsNull() {
+		return newMessageWriter(
+			messageRef.MessageEncoding(),
+			xio.DiscardWriteCloser,
+			marshaler,
+		), nil
+	}
+	writeCloser, err := c.buffetchWriter.PutMessageFile(ctx, c.container, messageRef)
+	if err != nil {
+		return nil, err
+	}
+	return newMessageWriter(
+		messageRef.MessageEncoding(),
+		writeCloser,
+		marshaler,
+	), nil
+}
+
 func (c *controller) GetCheckClientForWorkspace(
 	ctx context.Context,
 	workspace bufworkspace.Workspace,
@@ -1412,6 +1528,36 @@ func newProtoencodingMarshaler(
 	}
 }
 
+// newProtoencodingUnmarshaler returns the Unmarshaler for the MessageRef, along with
+// the validator that still needs to be applied to unmarshaled messages, if any.
+//
+// The YAML Unmarshaler applies the validator itself so that it can pretty print
+// validation errors, in which case the returned validator is nil.
+func newProtoencodingUnmarshaler(
+	image bufimage.Image,
+	messageRef buffetch.MessageRef,
+	validator protoyaml.Validator,
+) (protoencoding.Unmarshaler, protoyaml.Validator, error) {
+	switch messageEncoding := messageRef.MessageEncoding(); messageEncoding {
+	case buffetch.MessageEncodingBinpb:
+		return protoencoding.NewWireUnmarshaler(image.Resolver()), validator, nil
+	case buffetch.MessageEncodingJSON:
+		return protoencoding.NewJSONUnmarshaler(image.Resolver()), validator, nil
+	case buffetch.MessageEncodingTxtpb:
+		return protoencoding.NewTxtpbUnmarshaler(image.Resolver()), validator, nil
+	case buffetch.MessageEncodingYAML:
+		return protoencoding.NewYAMLUnmarshaler(
+			image.Resolver(),
+			protoencoding.YAMLUnmarshalerWithPath(messageRef.Path()),
+			// This will pretty print validation errors.
+			protoencoding.YAMLUnmarshalerWithValidator(validator),
+		), nil, nil
+	default:
+		// This is a system error.
+		return nil, nil, syserror.Newf("unknown MessageEncoding: %v", messageEncoding)
+	}
+}
+
 func newJSONMarshaler(
 	resolver protoencoding.Resolver,
 	messageRef buffetch.MessageRef,
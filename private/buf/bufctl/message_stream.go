@@ -0,0 +1,278 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"buf.build/go/protoyaml"
+	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// messageSeparator is the line that separates YAML and text format messages in a stream.
+	messageSeparator = "---"
+	// maxSizeDelimitedMessageSize is the maximum size of a size-delimited binary message.
+	//
+	// This is the maximum size of a serialized Protobuf message.
+	maxSizeDelimitedMessageSize = math.MaxInt32
+)
+
+// MessageReader reads a stream of messages.
+//
+// Binary messages are each prefixed by their size as a varint. JSON messages follow
+// one another, typically one per line. YAML and text format messages are separated
+// by lines that consist of "---".
+type MessageReader interface {
+	io.Closer
+
+	// MessageEncoding returns the encoding of the messages being read.
+	MessageEncoding() buffetch.MessageEncoding
+	// Next reads the next message.
+	//
+	// Returns io.EOF when there are no more messages.
+	Next() (proto.Message, error)
+}
+
+// MessageWriter writes a stream of messages.
+//
+// Messages are delimited the same way that a MessageReader expects them to be.
+//
+// Close must be called to flush the written messages.
+type MessageWriter interface {
+	io.Closer
+
+	// MessageEncoding returns the encoding of the messages being written.
+	MessageEncoding() buffetch.MessageEncoding
+	// Write writes the message.
+	Write(message proto.Message) error
+}
+
+// *** PRIVATE ***
+
+type messageReader struct {
+	messageEncoding buffetch.MessageEncoding
+	readCloser      io.ReadCloser
+	reader          *bufio.Reader
+	jsonDecoder     *json.Decoder
+	messageType     protoreflect.MessageType
+	unmarshaler     protoencoding.Unmarshaler
+	validator       protoyaml.Validator
+	// The number of messages read so far.
+	count int
+}
+
+func newMessageReader(
+	messageEncoding buffetch.MessageEncoding,
+	readCloser io.ReadCloser,
+	messageType protoreflect.MessageType,
+	unmarshaler protoencoding.Unmarshaler,
+	validator protoyaml.Validator,
+) *messageReader {
+	reader := bufio.NewReader(readCloser)
+	messageReader := &messageReader{
+		messageEncoding: messageEncoding,
+		readCloser:      readCloser,
+		reader:          reader,
+		messageType:     messageType,
+		unmarshaler:     unmarshaler,
+		validator:       validator,
+	}
+	if messageEncoding == buffetch.MessageEncodingJSON {
+		messageReader.jsonDecoder = json.NewDecoder(reader)
+	}
+	return messageReader
+}
+
+func (r *messageReader) MessageEncoding() buffetch.MessageEncoding {
+	return r.messageEncoding
+}
+
+func (r *messageReader) Next() (proto.Message, error) {
+	data, err := r.nextData()
+	if err != nil {
+		return nil, err
+	}
+	r.count++
+	message := r.messageType.New().Interface()
+	if err := r.unmarshaler.Unmarshal(data, message); err != nil {
+		return nil, fmt.Errorf("message %d: %w", r.count, err)
+	}
+	if r.validator != nil {
+		if err := r.validator.Validate(message); err != nil {
+			return nil, fmt.Errorf("message %d: %w", r.count, err)
+		}
+	}
+	return message, nil
+}
+
+func (r *messageReader) Close() error {
+	return r.readCloser.Close()
+}
+
+func (r *messageReader) nextData() ([]byte, error) {
+	switch r.messageEncoding {
+	case buffetch.MessageEncodingBinpb:
+		return r.nextSizeDelimitedData()
+	case buffetch.MessageEncodingJSON:
+		var data json.RawMessage
+		if err := r.jsonDecoder.Decode(&data); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("message %d: %w", r.count+1, err)
+		}
+		return data, nil
+	case buffetch.MessageEncodingTxtpb, buffetch.MessageEncodingYAML:
+		return r.nextSeparatedData()
+	default:
+		// This is a system error.
+		return nil, syserror.Newf("unknown MessageEncoding: %v", r.messageEncoding)
+	}
+}
+
+func (r *messageReader) nextSizeDelimitedData() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("message %d: failed to read size: %w", r.count+1, err)
+	}
+	if size > maxSizeDelimitedMessageSize {
+		return nil, fmt.Errorf("message %d: invalid size %d", r.count+1, size)
+	}
+	// The size comes from the input and may be wrong, so the buffer only grows as
+	// the data is actually read instead of being allocated up front.
+	var data bytes.Buffer
+	n, err := io.Copy(&data, io.LimitReader(r.reader, int64(size)))
+	if err != nil {
+		return nil, fmt.Errorf("message %d: %w", r.count+1, err)
+	}
+	if uint64(n) < size {
+		return nil, fmt.Errorf("message %d: invalid size %d: only %d bytes remaining", r.count+1, size, n)
+	}
+	return data.Bytes(), nil
+}
+
+func (r *messageReader) nextSeparatedData() ([]byte, error) {
+	for {
+		var document bytes.Buffer
+		var sawSeparator bool
+		for {
+			line, err := r.reader.ReadString('\n')
+			if line != "" {
+				if strings.TrimRight(line, " \t\r\n") == messageSeparator {
+					sawSeparator = true
+					break
+				}
+				_, _ = document.WriteString(line)
+			}
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if len(bytes.TrimSpace(document.Bytes())) == 0 {
+			if !sawSeparator {
+				return nil, io.EOF
+			}
+			// Skip empty documents, such as the one before a leading separator.
+			continue
+		}
+		return document.Bytes(), nil
+	}
+}
+
+type messageWriter struct {
+	messageEncoding buffetch.MessageEncoding
+	writeCloser     io.WriteCloser
+	writer          *bufio.Writer
+	marshaler       protoencoding.Marshaler
+	// The number of messages written so far.
+	count int
+}
+
+func newMessageWriter(
+	messageEncoding buffetch.MessageEncoding,
+	writeCloser io.WriteCloser,
+	marshaler protoencoding.Marshaler,
+) *messageWriter {
+	return &messageWriter{
+		messageEncoding: messageEncoding,
+		writeCloser:     writeCloser,
+		writer:          bufio.NewWriter(writeCloser),
+		marshaler:       marshaler,
+	}
+}
+
+func (w *messageWriter) MessageEncoding() buffetch.MessageEncoding {
+	return w.messageEncoding
+}
+
+func (w *messageWriter) Write(message proto.Message) error {
+	data, err := w.marshaler.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("message %d: %w", w.count+1, err)
+	}
+	w.count++
+	switch w.messageEncoding {
+	case buffetch.MessageEncodingBinpb:
+		if _, err := w.writer.Write(binary.AppendUvarint(nil, uint64(len(data)))); err != nil {
+			return err
+		}
+		_, err := w.writer.Write(data)
+		return err
+	case buffetch.MessageEncodingJSON:
+		return w.writeWithTrailingNewline(data)
+	case buffetch.MessageEncodingTxtpb, buffetch.MessageEncodingYAML:
+		if w.count > 1 {
+			if _, err := w.writer.WriteString(messageSeparator + "\n"); err != nil {
+				return err
+			}
+		}
+		return w.writeWithTrailingNewline(data)
+	default:
+		// This is a system error.
+		return syserror.Newf("unknown MessageEncoding: %v", w.messageEncoding)
+	}
+}
+
+func (w *messageWriter) Close() error {
+	return errors.Join(w.writer.Flush(), w.writeCloser.Close())
+}
+
+func (w *messageWriter) writeWithTrailingNewline(data []byte) error {
+	if _, err := w.writer.Write(data); err != nil {
+		return err
+	}
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		return w.writer.WriteByte('\n')
+	}
+	return nil
+}
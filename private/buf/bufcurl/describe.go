@@ -0,0 +1,58 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/jhump/protoreflect/v2/protoprint"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// DescribeSymbol uses the given resolver to find the element with the given
+// fully-qualified name, and returns its definition in .proto syntax. A method
+// may also be named as in URLs, with a slash between the service and method
+// names, such as "foo.v1.FooService/Bar".
+func DescribeSymbol(res protoencoding.Resolver, symbol string) (string, error) {
+	name := protoreflect.FullName(strings.TrimPrefix(strings.Replace(symbol, "/", ".", 1), "."))
+	if !name.IsValid() {
+		return "", fmt.Errorf("%q is not a valid fully-qualified name", symbol)
+	}
+	descriptor, err := res.FindDescriptorByName(name)
+	if errors.Is(err, protoregistry.NotFound) {
+		return "", fmt.Errorf("failed to find element named %q in schema", name)
+	} else if err != nil {
+		return "", err
+	}
+	printer := &protoprint.Printer{
+		Compact: true,
+		Indent:  "  ",
+	}
+	definition, err := printer.PrintProtoToString(descriptor)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"// %s %s is defined in %s.\n%s",
+		descriptorKind(descriptor),
+		descriptor.FullName(),
+		descriptor.ParentFile().Path(),
+		definition,
+	), nil
+}
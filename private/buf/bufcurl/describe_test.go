@@ -0,0 +1,52 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeSymbol(t *testing.T) {
+	t.Parallel()
+	_, resolver := newTestLibraryService(t, nil)
+	testDescribeSymbol := func(t *testing.T, symbol string, expected string) {
+		definition, err := DescribeSymbol(resolver, symbol)
+		require.NoError(t, err)
+		assert.Equal(t, expected, definition)
+	}
+	testDescribeSymbol(t, "foo.bar.Filter", `// message foo.bar.Filter is defined in library.proto.
+message Filter {
+  string author = 1;
+  google.protobuf.Timestamp published_after = 2;
+}
+`)
+	testDescribeSymbol(t, "foo.bar.View", `// enum foo.bar.View is defined in library.proto.
+enum View {
+  VIEW_UNSPECIFIED = 0;
+  VIEW_BASIC = 1;
+  VIEW_FULL = 2;
+}
+`)
+	testDescribeSymbol(t, "foo.bar.LibraryService/WatchBooks", `// method foo.bar.LibraryService.WatchBooks is defined in library.proto.
+rpc WatchBooks ( ListBooksRequest ) returns ( stream Book );
+`)
+	_, err := DescribeSymbol(resolver, "foo.bar.Missing")
+	assert.EqualError(t, err, `failed to find element named "foo.bar.Missing" in schema`)
+	_, err = DescribeSymbol(resolver, "foo bar")
+	assert.EqualError(t, err, `"foo bar" is not a valid fully-qualified name`)
+}
@@ -0,0 +1,249 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// HealthStatusUnknown is the status of a service whose health is unknown.
+	HealthStatusUnknown HealthStatus = 0
+	// HealthStatusServing is the status of a service that is serving requests.
+	HealthStatusServing HealthStatus = 1
+	// HealthStatusNotServing is the status of a service that is not serving requests.
+	HealthStatusNotServing HealthStatus = 2
+	// HealthStatusServiceUnknown is the status reported by Watch for a service that
+	// the server does not know about.
+	HealthStatusServiceUnknown HealthStatus = 3
+)
+
+var (
+	healthStatusToString = map[HealthStatus]string{
+		HealthStatusUnknown:        "UNKNOWN",
+		HealthStatusServing:        "SERVING",
+		HealthStatusNotServing:     "NOT_SERVING",
+		HealthStatusServiceUnknown: "SERVICE_UNKNOWN",
+	}
+
+	// The descriptor of grpc.health.v1.Health, built here so as to not depend on
+	// grpc-go for its generated code.
+	healthServiceDescriptor = sync.OnceValues(newHealthServiceDescriptor)
+)
+
+// HealthStatus is the serving status of a service, as reported by the
+// grpc.health.v1.Health service.
+type HealthStatus int32
+
+// String implements fmt.Stringer.
+func (h HealthStatus) String() string {
+	s, ok := healthStatusToString[h]
+	if !ok {
+		return fmt.Sprintf("%d", h)
+	}
+	return s
+}
+
+// CheckHealth calls grpc.health.v1.Health/Check at the given base URL, and
+// returns the status of the given service. An empty service name checks the
+// health of the server as a whole.
+func CheckHealth(
+	ctx context.Context,
+	httpClient connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	baseURL string,
+	service string,
+	headers http.Header,
+) (HealthStatus, error) {
+	client, err := newHealthClient(httpClient, clientOptions, baseURL, "Check")
+	if err != nil {
+		return 0, err
+	}
+	req := connect.NewRequest(newHealthCheckRequest(client, service))
+	maps.Copy(req.Header(), headers)
+	resp, err := client.CallUnary(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return healthStatusOf(resp.Msg), nil
+}
+
+// WatchHealth calls grpc.health.v1.Health/Watch at the given base URL, and
+// calls the given function with every status of the given service the server
+// sends, until the function returns false or the server ends the stream, in
+// which case nil is returned. An empty service name watches the health of the
+// server as a whole.
+func WatchHealth(
+	ctx context.Context,
+	httpClient connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	baseURL string,
+	service string,
+	headers http.Header,
+	f func(HealthStatus) bool,
+) (retErr error) {
+	client, err := newHealthClient(httpClient, clientOptions, baseURL, "Watch")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req := connect.NewRequest(newHealthCheckRequest(client, service))
+	maps.Copy(req.Header(), headers)
+	stream, err := client.CallServerStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Closing a stream that is not drained returns a cancellation error.
+		if err := stream.Close(); err != nil && retErr == nil && !isCancelled(err) {
+			retErr = err
+		}
+	}()
+	for stream.Receive() {
+		if !f(healthStatusOf(stream.Msg())) {
+			cancel()
+			return nil
+		}
+	}
+	return stream.Err()
+}
+
+// *** PRIVATE ***
+
+type healthClient struct {
+	*connect.Client[dynamicpb.Message, dynamicpb.Message]
+	md protoreflect.MethodDescriptor
+}
+
+func newHealthClient(
+	httpClient connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	baseURL string,
+	methodName protoreflect.Name,
+) (*healthClient, error) {
+	serviceDescriptor, err := healthServiceDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	md := serviceDescriptor.Methods().ByName(methodName)
+	url := strings.TrimSuffix(baseURL, "/") + "/" + string(serviceDescriptor.FullName()) + "/" + string(md.Name())
+	client := connect.NewClient[dynamicpb.Message, dynamicpb.Message](
+		httpClient,
+		url,
+		append(
+			clientOptions,
+			connect.WithSchema(md),
+			connect.WithResponseInitializer(func(_ connect.Spec, msg any) error {
+				*msg.(*dynamicpb.Message) = *dynamicpb.NewMessage(md.Output())
+				return nil
+			}),
+		)...,
+	)
+	return &healthClient{Client: client, md: md}, nil
+}
+
+func newHealthCheckRequest(client *healthClient, service string) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(client.md.Input())
+	msg.Set(client.md.Input().Fields().ByName("service"), protoreflect.ValueOfString(service))
+	return msg
+}
+
+func healthStatusOf(msg *dynamicpb.Message) HealthStatus {
+	return HealthStatus(msg.Get(msg.Descriptor().Fields().ByName("status")).Enum())
+}
+
+func newHealthServiceDescriptor() (protoreflect.ServiceDescriptor, error) {
+	fileDescriptor, err := protodesc.NewFile(
+		&descriptorpb.FileDescriptorProto{
+			Name:    proto.String("grpc/health/v1/health.proto"),
+			Package: proto.String("grpc.health.v1"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("HealthCheckRequest"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{
+							Name:     proto.String("service"),
+							JsonName: proto.String("service"),
+							Number:   proto.Int32(1),
+							Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+						},
+					},
+				},
+				{
+					Name: proto.String("HealthCheckResponse"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{
+							Name:     proto.String("status"),
+							JsonName: proto.String("status"),
+							Number:   proto.Int32(1),
+							Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+							TypeName: proto.String(".grpc.health.v1.HealthCheckResponse.ServingStatus"),
+						},
+					},
+					EnumType: []*descriptorpb.EnumDescriptorProto{
+						{
+							Name: proto.String("ServingStatus"),
+							Value: []*descriptorpb.EnumValueDescriptorProto{
+								{Name: proto.String("UNKNOWN"), Number: proto.Int32(int32(HealthStatusUnknown))},
+								{Name: proto.String("SERVING"), Number: proto.Int32(int32(HealthStatusServing))},
+								{Name: proto.String("NOT_SERVING"), Number: proto.Int32(int32(HealthStatusNotServing))},
+								{Name: proto.String("SERVICE_UNKNOWN"), Number: proto.Int32(int32(HealthStatusServiceUnknown))},
+							},
+						},
+					},
+				},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{
+				{
+					Name: proto.String("Health"),
+					Method: []*descriptorpb.MethodDescriptorProto{
+						{
+							Name:       proto.String("Check"),
+							InputType:  proto.String(".grpc.health.v1.HealthCheckRequest"),
+							OutputType: proto.String(".grpc.health.v1.HealthCheckResponse"),
+						},
+						{
+							Name:            proto.String("Watch"),
+							InputType:       proto.String(".grpc.health.v1.HealthCheckRequest"),
+							OutputType:      proto.String(".grpc.health.v1.HealthCheckResponse"),
+							ServerStreaming: proto.Bool(true),
+						},
+					},
+				},
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return fileDescriptor.Services().Get(0), nil
+}
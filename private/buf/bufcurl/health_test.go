@@ -0,0 +1,121 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, err := healthServiceDescriptor()
+	require.NoError(t, err)
+	check := serviceDescriptor.Methods().ByName("Check")
+	watch := serviceDescriptor.Methods().ByName("Watch")
+	serviceToStatuses := map[string][]HealthStatus{
+		"":                {HealthStatusServing},
+		"foo.v1.Starting": {HealthStatusNotServing, HealthStatusNotServing, HealthStatusServing},
+		"foo.v1.Stopped":  {HealthStatusNotServing},
+	}
+	newResponse := func(status HealthStatus) *dynamicpb.Message {
+		msg := dynamicpb.NewMessage(check.Output())
+		msg.Set(check.Output().Fields().ByName("status"), protoreflect.ValueOfEnum(protoreflect.EnumNumber(status)))
+		return msg
+	}
+	getService := func(msg *dynamicpb.Message) string {
+		return msg.Get(check.Input().Fields().ByName("service")).String()
+	}
+	initializeRequest := connect.WithRequestInitializer(func(_ connect.Spec, msg any) error {
+		*msg.(*dynamicpb.Message) = *dynamicpb.NewMessage(check.Input())
+		return nil
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/grpc.health.v1.Health/Check", connect.NewUnaryHandler(
+		"/grpc.health.v1.Health/Check",
+		func(_ context.Context, req *connect.Request[dynamicpb.Message]) (*connect.Response[dynamicpb.Message], error) {
+			statuses, ok := serviceToStatuses[getService(req.Msg)]
+			if !ok {
+				return nil, connect.NewError(connect.CodeNotFound, errors.New("unknown service"))
+			}
+			return connect.NewResponse(newResponse(statuses[0])), nil
+		},
+		connect.WithSchema(check),
+		initializeRequest,
+	))
+	mux.Handle("/grpc.health.v1.Health/Watch", connect.NewServerStreamHandler(
+		"/grpc.health.v1.Health/Watch",
+		func(_ context.Context, req *connect.Request[dynamicpb.Message], stream *connect.ServerStream[dynamicpb.Message]) error {
+			statuses, ok := serviceToStatuses[getService(req.Msg)]
+			if !ok {
+				statuses = []HealthStatus{HealthStatusServiceUnknown}
+			}
+			for _, status := range statuses {
+				if err := stream.Send(newResponse(status)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		connect.WithSchema(watch),
+		initializeRequest,
+	))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	testCheck := func(t *testing.T, service string, expected HealthStatus) {
+		status, err := CheckHealth(context.Background(), server.Client(), nil, server.URL+"/", service, nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, status)
+	}
+	testWatch := func(t *testing.T, service string, expected []HealthStatus) {
+		var statuses []HealthStatus
+		err := WatchHealth(context.Background(), server.Client(), nil, server.URL, service, nil, func(status HealthStatus) bool {
+			statuses = append(statuses, status)
+			return status != HealthStatusServing
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, statuses)
+	}
+	t.Run("check", func(t *testing.T) {
+		t.Parallel()
+		testCheck(t, "", HealthStatusServing)
+		testCheck(t, "foo.v1.Starting", HealthStatusNotServing)
+		testCheck(t, "foo.v1.Stopped", HealthStatusNotServing)
+		_, err := CheckHealth(context.Background(), server.Client(), nil, server.URL, "foo.v1.Missing", nil)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+	t.Run("watch", func(t *testing.T) {
+		t.Parallel()
+		testWatch(t, "", []HealthStatus{HealthStatusServing})
+		testWatch(t, "foo.v1.Starting", []HealthStatus{HealthStatusNotServing, HealthStatusNotServing, HealthStatusServing})
+		testWatch(t, "foo.v1.Stopped", []HealthStatus{HealthStatusNotServing})
+		testWatch(t, "foo.v1.Missing", []HealthStatus{HealthStatusServiceUnknown})
+	})
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "NOT_SERVING", HealthStatusNotServing.String())
+		assert.Equal(t, "7", HealthStatus(7).String())
+	})
+}
@@ -15,8 +15,10 @@
 package bufcurl
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,13 +26,18 @@ import (
 	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"buf.build/go/app"
 	"buf.build/go/app/appext"
+	"buf.build/go/protovalidate"
 	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/buf/buffetch"
 	"github.com/bufbuild/buf/private/pkg/protoencoding"
 	"github.com/bufbuild/buf/private/pkg/verbose"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
@@ -74,31 +81,142 @@ type invoker struct {
 	md           protoreflect.MethodDescriptor
 	res          protoencoding.Resolver
 	emitDefaults bool
+	dataFormat   buffetch.MessageEncoding
+	outputFormat buffetch.MessageEncoding
+	rpcErrors    bool
 	client       *invokeClient
+	httpClient   connect.HTTPClient
 	output       io.Writer
 	errOutput    io.Writer
 	printer      verbose.Printer
+
+	// The base URL to send transcoded HTTP requests to, if HTTP
+	// transcoding is enabled.
+	transcodingBaseURL string
+	// The interactive session that request messages of streaming
+	// RPCs are read from, if any.
+	repl *REPL
+	// The validators of request and response messages, if validation
+	// is enabled.
+	requestValidator  protovalidate.Validator
+	responseValidator protovalidate.Validator
+
+	// The number of responses written to output so far.
+	responseCount int
+}
+
+// InvokerOption is an option for NewInvoker.
+type InvokerOption func(*invoker)
+
+// InvokerWithDataFormat returns a new InvokerOption that sets the format of the
+// request data.
+//
+// The default is buffetch.MessageEncodingJSON.
+func InvokerWithDataFormat(dataFormat buffetch.MessageEncoding) InvokerOption {
+	return func(invoker *invoker) {
+		invoker.dataFormat = dataFormat
+	}
+}
+
+// InvokerWithOutputFormat returns a new InvokerOption that sets the format the
+// response(s) are written in.
+//
+// The default is buffetch.MessageEncodingJSON.
+func InvokerWithOutputFormat(outputFormat buffetch.MessageEncoding) InvokerOption {
+	return func(invoker *invoker) {
+		invoker.outputFormat = outputFormat
+	}
+}
+
+// InvokerWithRPCErrors returns a new InvokerOption that makes Invoke return the
+// *connect.Error of a failed RPC as is.
+//
+// By default, the error is instead written to the error output, and Invoke returns
+// an error with an exit code derived from the RPC's status code.
+func InvokerWithRPCErrors() InvokerOption {
+	return func(invoker *invoker) {
+		invoker.rpcErrors = true
+	}
+}
+
+// InvokerWithHTTPTranscoding returns a new InvokerOption that invokes the method
+// as a REST endpoint, as specified by its google.api.http annotation, instead of
+// using an RPC protocol. Requests are sent to the path of the annotation, relative
+// to the given base URL.
+//
+// Only unary methods are supported.
+func InvokerWithHTTPTranscoding(baseURL string) InvokerOption {
+	return func(invoker *invoker) {
+		invoker.transcodingBaseURL = baseURL
+	}
+}
+
+// InvokerWithREPL returns a new InvokerOption that reads the request messages of
+// client-streaming and bidirectional-streaming RPCs from the given REPL instead
+// of the data passed to Invoke. Responses and errors are written to the REPL.
+func InvokerWithREPL(repl *REPL) InvokerOption {
+	return func(invoker *invoker) {
+		invoker.repl = repl
+		invoker.output = repl
+		invoker.errOutput = repl
+	}
+}
+
+// InvokerWithRequestValidation returns a new InvokerOption that validates every
+// request message with the given validator before it is sent. The RPC fails if
+// a request message is invalid.
+func InvokerWithRequestValidation(validator protovalidate.Validator) InvokerOption {
+	return func(invoker *invoker) {
+		invoker.requestValidator = validator
+	}
+}
+
+// InvokerWithResponseValidation returns a new InvokerOption that validates every
+// response message with the given validator when it is received. The invalid
+// response message is written to the output, and then Invoke returns an error.
+func InvokerWithResponseValidation(validator protovalidate.Validator) InvokerOption {
+	return func(invoker *invoker) {
+		invoker.responseValidator = validator
+	}
 }
 
 // NewInvoker creates a new invoker for invoking the method described by the
-// given descriptor. The given writer is used to write the output response(s)
-// in JSON format. The given resolver is used to resolve Any messages and
-// extensions that appear in the input or output. Other parameters are used
-// to create a Connect client, for issuing the RPC.
-func NewInvoker(container appext.Container, verbosePrinter verbose.Printer, md protoreflect.MethodDescriptor, res protoencoding.Resolver, emitDefaults bool, httpClient connect.HTTPClient, opts []connect.ClientOption, url string, out io.Writer) Invoker {
+// given descriptor. The given writer is used to write the output response(s),
+// in JSON format unless InvokerWithOutputFormat is used. The given resolver is
+// used to resolve Any messages and extensions that appear in the input or output.
+// Other parameters are used to create a Connect client, for issuing the RPC.
+func NewInvoker(
+	container appext.Container,
+	verbosePrinter verbose.Printer,
+	md protoreflect.MethodDescriptor,
+	res protoencoding.Resolver,
+	emitDefaults bool,
+	httpClient connect.HTTPClient,
+	opts []connect.ClientOption,
+	url string,
+	out io.Writer,
+	options ...InvokerOption,
+) Invoker {
 	opts = append(opts, connect.WithCodec(protoCodec{}))
 	// TODO: could also provide custom compressor implementations that could give us
 	//  optics into when request and response messages are compressed (which could be
 	//  useful to include in verbose output).
-	return &invoker{
+	invoker := &invoker{
 		md:           md,
 		res:          res,
 		emitDefaults: emitDefaults,
+		dataFormat:   buffetch.MessageEncodingJSON,
+		outputFormat: buffetch.MessageEncodingJSON,
 		output:       out,
 		printer:      verbosePrinter,
 		errOutput:    container.Stderr(),
 		client:       connect.NewClient[dynamicpb.Message, deferredMessage](httpClient, url, opts...),
+		httpClient:   httpClient,
+	}
+	for _, option := range options {
+		option(invoker)
 	}
+	return invoker
 }
 
 func (inv *invoker) Invoke(ctx context.Context, dataSource string, data io.Reader, headers http.Header) error {
@@ -106,6 +224,12 @@ func (inv *invoker) Invoke(ctx context.Context, dataSource string, data io.Reade
 	// request's user-agent header(s) get overwritten by protocol, so we stash them in the
 	// context so that underlying transport can restore them
 	ctx = withUserAgent(ctx, headers)
+	if inv.transcodingBaseURL != "" {
+		if inv.md.IsStreamingClient() || inv.md.IsStreamingServer() {
+			return fmt.Errorf("method %s is a streaming RPC, but HTTP transcoding only supports unary RPCs", inv.md.FullName())
+		}
+		return inv.handleTranscodedUnary(ctx, dataSource, data, headers)
+	}
 	switch {
 	case inv.md.IsStreamingServer() && inv.md.IsStreamingClient():
 		return inv.handleBidiStream(ctx, dataSource, data, headers)
@@ -119,17 +243,10 @@ func (inv *invoker) Invoke(ctx context.Context, dataSource string, data io.Reade
 }
 
 func (inv *invoker) handleUnary(ctx context.Context, dataSource string, data io.Reader, headers http.Header) error {
-	provider := newMessageProvider(dataSource, data, inv.res)
-	msg := dynamicpb.NewMessage(inv.md.Input())
-	if err := provider.next(msg); err != nil {
+	msg, err := inv.readUnaryRequest(dataSource, data)
+	if err != nil {
 		return err
 	}
-	// make sure input does not contain a second message
-	dummy := dynamicpb.NewMessage(inv.md.Input())
-	if err := provider.next(dummy); err != io.EOF {
-		return fmt.Errorf("method %s is a unary RPC, but input contained more than one request message", inv.md.Name())
-	}
-
 	req := connect.NewRequest(msg)
 	maps.Copy(req.Header(), headers)
 	resp, err := inv.client.CallUnary(ctx, req)
@@ -144,8 +261,70 @@ func (inv *invoker) handleUnary(ctx context.Context, dataSource string, data io.
 	return inv.handleResponse(resp.Msg.data, nil)
 }
 
+func (inv *invoker) handleTranscodedUnary(ctx context.Context, dataSource string, data io.Reader, headers http.Header) error {
+	rule, err := getHTTPRule(inv.md)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return fmt.Errorf("method %s has no google.api.http option, so it cannot be invoked with HTTP transcoding", inv.md.FullName())
+	}
+	msg, err := inv.readUnaryRequest(dataSource, data)
+	if err != nil {
+		return err
+	}
+	req, err := newTranscodedRequest(ctx, inv.transcodingBaseURL, rule, msg, inv.res)
+	if err != nil {
+		return err
+	}
+	for name, values := range headers {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	inv.printer.Printf("* Transcoding to %s %s\n", req.Method, req.URL)
+	resp, err := inv.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return inv.handleErrorResponse(newTranscodedResponseError(resp.StatusCode, body))
+	}
+	respMsg := dynamicpb.NewMessage(inv.md.Output())
+	if err := unmarshalTranscodedResponse(rule, body, respMsg, inv.res); err != nil {
+		return err
+	}
+	respData, err := protoencoding.NewWireMarshaler().Marshal(respMsg)
+	if err != nil {
+		return err
+	}
+	return inv.handleResponse(respData, nil)
+}
+
+// readUnaryRequest reads the request message of a unary RPC.
+func (inv *invoker) readUnaryRequest(dataSource string, data io.Reader) (*dynamicpb.Message, error) {
+	provider := inv.withRequestValidation(newMessageProvider(dataSource, data, inv.dataFormat, inv.res))
+	msg := dynamicpb.NewMessage(inv.md.Input())
+	if err := provider.next(msg); err != nil {
+		return nil, err
+	}
+	// make sure input does not contain a second message
+	dummy := dynamicpb.NewMessage(inv.md.Input())
+	if err := provider.next(dummy); err != io.EOF {
+		return nil, fmt.Errorf("method %s is a unary RPC, but input contained more than one request message", inv.md.Name())
+	}
+	return msg, nil
+}
+
 func (inv *invoker) handleClientStream(ctx context.Context, dataSource string, data io.Reader, headers http.Header) (retErr error) {
-	provider := newStreamMessageProvider(dataSource, data, inv.res)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	provider := inv.newStreamRequestProvider(dataSource, data)
 	msg := dynamicpb.NewMessage(inv.md.Input())
 	stream := inv.client.CallClientStream(ctx)
 	maps.Copy(stream.RequestHeader(), headers)
@@ -176,7 +355,7 @@ func (inv *invoker) handleClientStream(ctx context.Context, dataSource string, d
 }
 
 func (inv *invoker) handleServerStream(ctx context.Context, dataSource string, data io.Reader, headers http.Header) (retErr error) {
-	provider := newMessageProvider(dataSource, data, inv.res)
+	provider := inv.withRequestValidation(newMessageProvider(dataSource, data, inv.dataFormat, inv.res))
 	msg := dynamicpb.NewMessage(inv.md.Input())
 	if err := provider.next(msg); err != nil {
 		return err
@@ -207,7 +386,7 @@ func (inv *invoker) handleServerStream(ctx context.Context, dataSource string, d
 
 func (inv *invoker) handleBidiStream(ctx context.Context, dataSource string, data io.Reader, headers http.Header) (retErr error) {
 	ctx, cancel := context.WithCancel(ctx)
-	provider := newStreamMessageProvider(dataSource, data, inv.res)
+	provider := inv.newStreamRequestProvider(dataSource, data)
 	msg := dynamicpb.NewMessage(inv.md.Input())
 	stream := inv.client.CallBidiStream(ctx)
 	maps.Copy(stream.RequestHeader(), headers)
@@ -222,6 +401,7 @@ func (inv *invoker) handleBidiStream(ctx context.Context, dataSource string, dat
 	}()
 
 	var recvErr error
+	var requestsDone atomic.Bool
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -230,6 +410,9 @@ func (inv *invoker) handleBidiStream(ctx context.Context, dataSource string, dat
 		if err := inv.handleStreamResponse(stream); err != nil {
 			recvErr = err
 		}
+		if inv.repl != nil && !requestsDone.Load() {
+			inv.repl.printf("The server closed the response stream, press Ctrl-D to finish.\n")
+		}
 	}()
 	defer func() {
 		wg.Wait()
@@ -245,10 +428,14 @@ func (inv *invoker) handleBidiStream(ctx context.Context, dataSource string, dat
 	defer func() {
 		if shouldCancel {
 			cancel()
+			// The transport may not notice the cancellation while it waits
+			// for request data, so the request stream is closed too.
+			_ = stream.CloseRequest()
 		}
 	}()
 
 	err, isStreamError := inv.handleStreamRequest(provider, msg, stream)
+	requestsDone.Store(true)
 	shouldCancel = err != nil && !isStreamError
 	if err != nil {
 		return err
@@ -256,6 +443,27 @@ func (inv *invoker) handleBidiStream(ctx context.Context, dataSource string, dat
 	return stream.CloseRequest()
 }
 
+// newStreamRequestProvider returns the provider of the request messages of a
+// client-streaming or bidirectional-streaming RPC.
+func (inv *invoker) newStreamRequestProvider(dataSource string, data io.Reader) messageProvider {
+	if inv.repl != nil {
+		// The REPL validates messages itself, so that invalid messages
+		// can be typed again.
+		inv.repl.validator = inv.requestValidator
+		return inv.repl
+	}
+	return inv.withRequestValidation(newStreamMessageProvider(dataSource, data, inv.dataFormat, inv.res))
+}
+
+// withRequestValidation returns a provider that validates the messages of the
+// given provider, if request validation is enabled.
+func (inv *invoker) withRequestValidation(provider messageProvider) messageProvider {
+	if inv.requestValidator == nil {
+		return provider
+	}
+	return &validatingMessageProvider{provider: provider, validator: inv.requestValidator}
+}
+
 func isCancelled(err error) bool {
 	if errors.Is(err, context.Canceled) {
 		return true
@@ -274,26 +482,79 @@ func (inv *invoker) handleResponse(data []byte, msg *dynamicpb.Message) error {
 	if err := protoencoding.NewWireUnmarshaler(inv.res).Unmarshal(data, msg); err != nil {
 		return err
 	}
-	jsonMarshalerOptions := []protoencoding.JSONMarshalerOption{
-		protoencoding.JSONMarshalerWithIndent(),
-	}
-	if inv.emitDefaults {
-		jsonMarshalerOptions = append(
-			jsonMarshalerOptions,
-			protoencoding.JSONMarshalerWithEmitUnpopulated(),
-		)
-	}
 	unrecognized := countUnrecognized(msg.ProtoReflect())
 	if unrecognized > 0 {
 		inv.printer.Printf("Response message (%s) contained %d bytes of unrecognized fields.",
 			msg.ProtoReflect().Descriptor().FullName(), unrecognized)
 	}
-	outputBytes, err := protoencoding.NewJSONMarshaler(inv.res, jsonMarshalerOptions...).Marshal(msg)
-	if err != nil {
+	if err := inv.writeResponse(data, msg); err != nil {
+		return err
+	}
+	if inv.responseValidator != nil {
+		if err := inv.responseValidator.Validate(msg); err != nil {
+			return fmt.Errorf("invalid response message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (inv *invoker) writeResponse(data []byte, msg *dynamicpb.Message) error {
+	isFirstResponse := inv.responseCount == 0
+	inv.responseCount++
+	switch inv.outputFormat {
+	case buffetch.MessageEncodingBinpb:
+		// We write the response exactly as we received it.
+		if inv.md.IsStreamingServer() {
+			// Delimit the messages of a stream, so that they can be told apart.
+			data = append(protowire.AppendVarint(nil, uint64(len(data))), data...)
+		}
+		_, err := inv.output.Write(data)
+		return err
+	case buffetch.MessageEncodingYAML, buffetch.MessageEncodingTxtpb:
+		var marshaler protoencoding.Marshaler
+		if inv.outputFormat == buffetch.MessageEncodingYAML {
+			yamlMarshalerOptions := []protoencoding.YAMLMarshalerOption{
+				protoencoding.YAMLMarshalerWithIndent(),
+			}
+			if inv.emitDefaults {
+				yamlMarshalerOptions = append(
+					yamlMarshalerOptions,
+					protoencoding.YAMLMarshalerWithEmitUnpopulated(),
+				)
+			}
+			marshaler = protoencoding.NewYAMLMarshaler(inv.res, yamlMarshalerOptions...)
+		} else {
+			marshaler = protoencoding.NewTxtpbMarshaler(inv.res)
+		}
+		outputBytes, err := marshaler.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if len(outputBytes) > 0 && outputBytes[len(outputBytes)-1] != '\n' {
+			outputBytes = append(outputBytes, '\n')
+		}
+		if !isFirstResponse {
+			outputBytes = append([]byte(messageSeparator+"\n"), outputBytes...)
+		}
+		_, err = inv.output.Write(outputBytes)
+		return err
+	default:
+		jsonMarshalerOptions := []protoencoding.JSONMarshalerOption{
+			protoencoding.JSONMarshalerWithIndent(),
+		}
+		if inv.emitDefaults {
+			jsonMarshalerOptions = append(
+				jsonMarshalerOptions,
+				protoencoding.JSONMarshalerWithEmitUnpopulated(),
+			)
+		}
+		outputBytes, err := protoencoding.NewJSONMarshaler(inv.res, jsonMarshalerOptions...).Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(inv.output, "%s\n", outputBytes)
 		return err
 	}
-	_, err = fmt.Fprintf(inv.output, "%s\n", outputBytes)
-	return err
 }
 
 type clientStream interface {
@@ -360,6 +621,9 @@ func (inv *invoker) handleStreamResponse(stream serverStream) (retError error) {
 }
 
 func (inv *invoker) handleErrorResponse(connErr *connect.Error) error {
+	if inv.rpcErrors {
+		return connErr
+	}
 	// NB: This is a nasty hack: we create a fake request that looks
 	//     like a unary Connect request, so that the ErrorWriter will
 	//     print the error in the format we want, which is just the
@@ -383,32 +647,68 @@ func (inv *invoker) handleErrorResponse(connErr *connect.Error) error {
 	if err := json.Indent(&prettyPrinted, responseWriter.Body.Bytes(), "", "   "); err != nil {
 		return err
 	}
+	prettyPrinted.WriteByte('\n')
 	_, _ = inv.errOutput.Write(prettyPrinted.Bytes())
-	_, _ = inv.errOutput.Write([]byte("\n"))
 	return app.NewError(int(connErr.Code()*8), "")
 }
 
-func newStreamMessageProvider(dataSource string, data io.Reader, res protoencoding.Resolver) messageProvider {
+func newStreamMessageProvider(dataSource string, data io.Reader, format buffetch.MessageEncoding, res protoencoding.Resolver) messageProvider {
 	if data == nil {
 		// if no data provided, treat as empty input
 		data = bytes.NewBuffer(nil)
 	}
-	return &streamMessageProvider{name: dataSource, dec: json.NewDecoder(data), res: res}
+	switch format {
+	case buffetch.MessageEncodingYAML:
+		return &separatedMessageProvider{
+			name:        dataSource,
+			lines:       &lineReader{r: bufio.NewReader(data)},
+			unmarshaler: protoencoding.NewYAMLUnmarshaler(res),
+		}
+	case buffetch.MessageEncodingTxtpb:
+		return &separatedMessageProvider{
+			name:        dataSource,
+			lines:       &lineReader{r: bufio.NewReader(data)},
+			unmarshaler: protoencoding.NewTxtpbUnmarshaler(res),
+		}
+	case buffetch.MessageEncodingBinpb:
+		return &sizeDelimitedMessageProvider{name: dataSource, r: bufio.NewReader(data), res: res}
+	default:
+		return &streamMessageProvider{name: dataSource, dec: json.NewDecoder(data), res: res}
+	}
 }
 
-func newMessageProvider(dataSource string, data io.Reader, res protoencoding.Resolver) messageProvider {
+func newMessageProvider(dataSource string, data io.Reader, format buffetch.MessageEncoding, res protoencoding.Resolver) messageProvider {
 	if data == nil {
 		// if no data provider, treat as if single empty message
 		return &singleEmptyMessageProvider{}
-	} else {
-		return newStreamMessageProvider(dataSource, data, res)
 	}
+	if format == buffetch.MessageEncodingBinpb {
+		// A single binary message is not delimited, so that it can come straight
+		// from other tools.
+		return &singleBinaryMessageProvider{name: dataSource, data: data, res: res}
+	}
+	return newStreamMessageProvider(dataSource, data, format, res)
 }
 
 type messageProvider interface {
 	next(proto.Message) error
 }
 
+type validatingMessageProvider struct {
+	provider  messageProvider
+	validator protovalidate.Validator
+}
+
+func (v *validatingMessageProvider) next(msg proto.Message) error {
+	if err := v.provider.next(msg); err != nil {
+		return err
+	}
+	if err := v.validator.Validate(msg); err != nil {
+		return fmt.Errorf("invalid request message: %w", err)
+	}
+	return nil
+}
+
 type singleEmptyMessageProvider struct {
 	read bool
 }
@@ -440,6 +740,107 @@ func (s *streamMessageProvider) next(msg proto.Message) error {
 	).Unmarshal(jsonData, msg)
 }
 
+// messageSeparator separates messages in formats that cannot otherwise
+// tell where one message ends and the next begins.
+const messageSeparator = "---"
+
+// separatedMessageProvider provides messages in a text format, separated
+// by lines that consist of messageSeparator.
+type separatedMessageProvider struct {
+	name        string
+	lines       *lineReader
+	unmarshaler protoencoding.Unmarshaler
+	// The number of messages provided so far.
+	count int
+}
+
+func (s *separatedMessageProvider) next(msg proto.Message) error {
+	for {
+		var document bytes.Buffer
+		var sawSeparator bool
+		for {
+			line, err := s.lines.ReadLine()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return ErrorHasFilename(err, s.name)
+			}
+			if strings.TrimRight(line, " \t\r") == messageSeparator {
+				sawSeparator = true
+				break
+			}
+			_, _ = document.WriteString(line)
+			_ = document.WriteByte('\n')
+		}
+		if len(bytes.TrimSpace(document.Bytes())) == 0 {
+			if !sawSeparator {
+				return io.EOF
+			}
+			// Skip empty documents, such as the one before a leading separator.
+			continue
+		}
+		s.count++
+		proto.Reset(msg)
+		if err := s.unmarshaler.Unmarshal(document.Bytes(), msg); err != nil {
+			return fmt.Errorf("%s, message %d: %w", s.name, s.count, err)
+		}
+		return nil
+	}
+}
+
+// sizeDelimitedMessageProvider provides messages in the binary format, each
+// prefixed by its size as a varint.
+type sizeDelimitedMessageProvider struct {
+	name string
+	r    *bufio.Reader
+	res  protoencoding.Resolver
+	// The number of messages provided so far.
+	count int
+}
+
+func (s *sizeDelimitedMessageProvider) next(msg proto.Message) error {
+	size, err := binary.ReadUvarint(s.r)
+	if err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return fmt.Errorf("%s, message %d: failed to read size: %w", s.name, s.count+1, err)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(s.r, data); err != nil {
+		return fmt.Errorf("%s, message %d: %w", s.name, s.count+1, err)
+	}
+	s.count++
+	if err := protoencoding.NewWireUnmarshaler(s.res).Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("%s, message %d: %w", s.name, s.count, err)
+	}
+	return nil
+}
+
+// singleBinaryMessageProvider provides a single message in the binary format,
+// which is the entire data.
+type singleBinaryMessageProvider struct {
+	name string
+	data io.Reader
+	res  protoencoding.Resolver
+	read bool
+}
+
+func (s *singleBinaryMessageProvider) next(msg proto.Message) error {
+	if s.read {
+		return io.EOF
+	}
+	s.read = true
+	data, err := io.ReadAll(s.data)
+	if err != nil {
+		return ErrorHasFilename(err, s.name)
+	}
+	if err := protoencoding.NewWireUnmarshaler(s.res).Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("%s: %w", s.name, err)
+	}
+	return nil
+}
+
 func countUnrecognized(msg protoreflect.Message) int {
 	var count int
 	msg.Range(func(field protoreflect.FieldDescriptor, val protoreflect.Value) bool {
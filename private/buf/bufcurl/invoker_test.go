@@ -15,15 +15,33 @@
 package bufcurl
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"buf.build/go/app"
+	"buf.build/go/app/appext"
+	"buf.build/go/protovalidate"
+	"connectrpc.com/connect"
+
+	"github.com/bufbuild/buf/private/buf/buffetch"
 	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/verbose"
 	"github.com/bufbuild/protocompile"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func TestCountUnrecognized(t *testing.T) {
@@ -71,3 +89,177 @@ func TestCountUnrecognized(t *testing.T) {
 	unrecognized := countUnrecognized(msg)
 	assert.Equal(t, expectedUnrecognized, unrecognized)
 }
+
+func TestMessageProviders(t *testing.T) {
+	t.Parallel()
+	descriptors, err := (&protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			ImportPaths: []string{"./testdata"},
+		},
+	}).Compile(context.Background(), "test.proto")
+	require.NoError(t, err)
+	msgType, err := descriptors.AsResolver().FindMessageByName("foo.bar.Message")
+	require.NoError(t, err)
+	newMessage := func(s string) proto.Message {
+		msg := dynamicpb.NewMessage(msgType.Descriptor())
+		msg.Set(msgType.Descriptor().Fields().ByName("s"), protoreflect.ValueOfString(s))
+		return msg
+	}
+
+	var delimited bytes.Buffer
+	for _, s := range []string{"foo", "bar"} {
+		_, err := protodelim.MarshalTo(&delimited, newMessage(s))
+		require.NoError(t, err)
+	}
+	testCases := []struct {
+		name   string
+		format buffetch.MessageEncoding
+		data   string
+	}{
+		{
+			name:   "json",
+			format: buffetch.MessageEncodingJSON,
+			data:   `{"s": "foo"} {"s": "bar"}`,
+		},
+		{
+			name:   "yaml",
+			format: buffetch.MessageEncodingYAML,
+			data:   "---\ns: foo\n---\n\n---\ns: bar\n",
+		},
+		{
+			name:   "txtpb",
+			format: buffetch.MessageEncodingTxtpb,
+			data:   "s: \"foo\"\n---\ns: \"bar\"",
+		},
+		{
+			name:   "binpb",
+			format: buffetch.MessageEncodingBinpb,
+			data:   delimited.String(),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			provider := newStreamMessageProvider("test", strings.NewReader(testCase.data), testCase.format, nil)
+			for _, s := range []string{"foo", "bar"} {
+				msg := dynamicpb.NewMessage(msgType.Descriptor())
+				require.NoError(t, provider.next(msg))
+				assert.True(t, proto.Equal(newMessage(s), msg), "got %v, want %q", msg, s)
+			}
+			err := provider.next(dynamicpb.NewMessage(msgType.Descriptor()))
+			assert.True(t, errors.Is(err, io.EOF), "expected io.EOF, got %v", err)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+	descriptors, err := (&protocompile.Compiler{
+		Resolver: protocompile.CompositeResolver{
+			&protocompile.SourceResolver{
+				ImportPaths: []string{"./testdata"},
+			},
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				if path == validate.File_buf_validate_validate_proto.Path() {
+					return protocompile.SearchResult{Desc: validate.File_buf_validate_validate_proto}, nil
+				}
+				return protocompile.SearchResult{}, os.ErrNotExist
+			}),
+		},
+	}).Compile(context.Background(), "users.proto")
+	require.NoError(t, err)
+	resolver, err := protoencoding.NewResolver(protodesc.ToFileDescriptorProto(descriptors[0]))
+	require.NoError(t, err)
+	serviceDescriptor := descriptors[0].Services().ByName("UserService")
+	getUser := serviceDescriptor.Methods().ByName("GetUser")
+	listUsers := serviceDescriptor.Methods().ByName("ListUsers")
+	newUser := func(name string, email string) *dynamicpb.Message {
+		user := dynamicpb.NewMessage(getUser.Output())
+		user.Set(getUser.Output().Fields().ByName("name"), protoreflect.ValueOfString(name))
+		user.Set(getUser.Output().Fields().ByName("email"), protoreflect.ValueOfString(email))
+		return user
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/foo.bar.UserService/GetUser", connect.NewUnaryHandler(
+		"/foo.bar.UserService/GetUser",
+		func(_ context.Context, req *connect.Request[dynamicpb.Message]) (*connect.Response[dynamicpb.Message], error) {
+			name := req.Msg.Get(getUser.Input().Fields().ByName("name")).String()
+			return connect.NewResponse(newUser(name, "invalid")), nil
+		},
+		connect.WithSchema(getUser),
+		connect.WithRequestInitializer(func(_ connect.Spec, msg any) error {
+			*msg.(*dynamicpb.Message) = *dynamicpb.NewMessage(getUser.Input())
+			return nil
+		}),
+	))
+	mux.Handle("/foo.bar.UserService/ListUsers", connect.NewServerStreamHandler(
+		"/foo.bar.UserService/ListUsers",
+		func(_ context.Context, _ *connect.Request[dynamicpb.Message], stream *connect.ServerStream[dynamicpb.Message]) error {
+			for _, user := range []*dynamicpb.Message{
+				newUser("one", "one@example.com"),
+				newUser("two", "two"),
+				newUser("three", "three@example.com"),
+			} {
+				if err := stream.Send(user); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		connect.WithSchema(listUsers),
+		connect.WithRequestInitializer(func(_ connect.Spec, msg any) error {
+			*msg.(*dynamicpb.Message) = *dynamicpb.NewMessage(listUsers.Input())
+			return nil
+		}),
+	))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	nameContainer, err := appext.NewNameContainer(app.NewContainer(nil, nil, io.Discard, io.Discard), "buf")
+	require.NoError(t, err)
+	container := appext.NewContainer(nameContainer, nil)
+	validator, err := protovalidate.New(protovalidate.WithExtensionTypeResolver(resolver))
+	require.NoError(t, err)
+	invoke := func(md protoreflect.MethodDescriptor, data string, options ...InvokerOption) (string, error) {
+		output := &bytes.Buffer{}
+		invoker := NewInvoker(
+			container,
+			verbose.NopPrinter,
+			md,
+			resolver,
+			false,
+			server.Client(),
+			nil,
+			server.URL+"/foo.bar.UserService/"+string(md.Name()),
+			output,
+			options...,
+		)
+		err := invoker.Invoke(context.Background(), "(argument)", strings.NewReader(data), nil)
+		return normalizeJSONSpaces(output.String()), err
+	}
+
+	output, err := invoke(getUser, `{"name": "ab"}`)
+	require.NoError(t, err)
+	assert.Contains(t, output, `"email": "invalid"`)
+
+	_, err = invoke(getUser, `{"name": "ab"}`, InvokerWithRequestValidation(validator))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request message")
+	assert.Contains(t, err.Error(), "name: value length must be at least 3 characters [string.min_len]")
+
+	output, err = invoke(getUser, `{"name": "abc"}`, InvokerWithRequestValidation(validator))
+	require.NoError(t, err)
+	assert.Contains(t, output, `"name": "abc"`)
+
+	output, err = invoke(getUser, `{"name": "abc"}`, InvokerWithResponseValidation(validator))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid response message")
+	assert.Contains(t, err.Error(), "email: value must be a valid email address [string.email]")
+	// The invalid response is printed before the error is returned.
+	assert.Contains(t, output, `"email": "invalid"`)
+
+	output, err = invoke(listUsers, `{}`, InvokerWithResponseValidation(validator))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "email: value must be a valid email address [string.email]")
+	assert.Contains(t, output, `"name": "two"`)
+	assert.NotContains(t, output, `"name": "three"`)
+}
@@ -0,0 +1,410 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/standard/xslices"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// loadTestStatusOK is the status recorded for successful requests.
+	loadTestStatusOK = "ok"
+	// loadTestHistogramBuckets is the number of buckets in a latency histogram.
+	loadTestHistogramBuckets = 10
+	// loadTestHistogramWidth is the width of the longest bar of a printed latency histogram.
+	loadTestHistogramWidth = 40
+)
+
+var (
+	// AllLoadTestReportFormatStrings are all formats that a LoadTestReport can be printed in.
+	//
+	// Sorted in the order we want to display them.
+	AllLoadTestReportFormatStrings = []string{
+		"text",
+		"json",
+	}
+)
+
+// LoadTestReport is the result of a load test.
+type LoadTestReport struct {
+	// Requests is the number of requests that were sent.
+	Requests int
+	// Duration is how long it took to send all requests and receive their responses.
+	Duration time.Duration
+	// Latencies are the latencies of all requests, successful or not, sorted from
+	// fastest to slowest.
+	Latencies []time.Duration
+	// StatusCodes are the numbers of requests by the status code of their responses,
+	// such as "ok" or "unavailable".
+	StatusCodes map[string]int
+}
+
+// RequestsPerSecond returns the throughput of the load test.
+func (r *LoadTestReport) RequestsPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Duration.Seconds()
+}
+
+// Percentile returns the latency that p percent of requests were at least as fast as.
+//
+// Returns 0 if there were no requests.
+func (r *LoadTestReport) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	// Nearest-rank method.
+	rank := int(math.Ceil(p / 100 * float64(len(r.Latencies))))
+	return r.Latencies[min(max(rank-1, 0), len(r.Latencies)-1)]
+}
+
+// Average returns the average latency.
+//
+// Returns 0 if there were no requests.
+func (r *LoadTestReport) Average() time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, latency := range r.Latencies {
+		total += latency
+	}
+	return total / time.Duration(len(r.Latencies))
+}
+
+// LoadTestOption is an option for RunLoadTest.
+type LoadTestOption func(*loadTestOptions)
+
+// LoadTestWithConcurrency returns a new LoadTestOption that sets the number of
+// workers that send requests concurrently.
+//
+// The default is 1.
+func LoadTestWithConcurrency(concurrency int) LoadTestOption {
+	return func(loadTestOptions *loadTestOptions) {
+		loadTestOptions.concurrency = concurrency
+	}
+}
+
+// LoadTestWithRequests returns a new LoadTestOption that stops the load test
+// after the given number of requests have been sent.
+func LoadTestWithRequests(requests int) LoadTestOption {
+	return func(loadTestOptions *loadTestOptions) {
+		loadTestOptions.requests = requests
+	}
+}
+
+// LoadTestWithDuration returns a new LoadTestOption that stops sending requests
+// after the given duration. Requests that are in flight at that time are waited for.
+func LoadTestWithDuration(duration time.Duration) LoadTestOption {
+	return func(loadTestOptions *loadTestOptions) {
+		loadTestOptions.duration = duration
+	}
+}
+
+// LoadTestWithRate returns a new LoadTestOption that limits the rate at which
+// requests are sent, across all workers, to the given number of requests per second.
+//
+// The default is to not limit the rate.
+func LoadTestWithRate(rate float64) LoadTestOption {
+	return func(loadTestOptions *loadTestOptions) {
+		loadTestOptions.rate = rate
+	}
+}
+
+// RunLoadTest repeatedly invokes an RPC with the given request data and headers,
+// and reports on the latencies and statuses of the responses.
+//
+// newInvoker is called once per worker. The invokers should use InvokerWithRPCErrors,
+// since any other error fails the load test, and should usually discard their output.
+//
+// At least one of LoadTestWithRequests or LoadTestWithDuration must be given. If both
+// are given, the load test stops at whichever limit is reached first.
+func RunLoadTest(
+	ctx context.Context,
+	newInvoker func() Invoker,
+	dataSource string,
+	data []byte,
+	headers http.Header,
+	options ...LoadTestOption,
+) (*LoadTestReport, error) {
+	loadTestOptions := newLoadTestOptions()
+	for _, option := range options {
+		option(loadTestOptions)
+	}
+	if loadTestOptions.requests <= 0 && loadTestOptions.duration <= 0 {
+		return nil, errors.New("a load test must be limited by a number of requests or a duration")
+	}
+	if loadTestOptions.concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be positive, got %d", loadTestOptions.concurrency)
+	}
+
+	start := time.Now()
+	var deadline time.Time
+	if loadTestOptions.duration > 0 {
+		deadline = start.Add(loadTestOptions.duration)
+	}
+	var ticks <-chan time.Time
+	if loadTestOptions.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / loadTestOptions.rate))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	var sent atomic.Int64
+	workerReports := make([]*LoadTestReport, loadTestOptions.concurrency)
+	eg, ctx := errgroup.WithContext(ctx)
+	for i := range workerReports {
+		workerReport := &LoadTestReport{StatusCodes: make(map[string]int)}
+		workerReports[i] = workerReport
+		invoker := newInvoker()
+		eg.Go(func() error {
+			for {
+				if loadTestOptions.requests > 0 && sent.Add(1) > int64(loadTestOptions.requests) {
+					return nil
+				}
+				if ticks != nil {
+					select {
+					case <-ticks:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+				if !deadline.IsZero() && !time.Now().Before(deadline) {
+					return nil
+				}
+				var reader io.Reader
+				if data != nil {
+					reader = bytes.NewReader(data)
+				}
+				requestStart := time.Now()
+				err := invoker.Invoke(ctx, dataSource, reader, headers.Clone())
+				latency := time.Since(requestStart)
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				status := loadTestStatusOK
+				if err != nil {
+					var connErr *connect.Error
+					if !errors.As(err, &connErr) {
+						return err
+					}
+					status = connErr.Code().String()
+				}
+				workerReport.Requests++
+				workerReport.Latencies = append(workerReport.Latencies, latency)
+				workerReport.StatusCodes[status]++
+			}
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	report := &LoadTestReport{
+		Duration:    time.Since(start),
+		StatusCodes: make(map[string]int),
+	}
+	for _, workerReport := range workerReports {
+		report.Requests += workerReport.Requests
+		report.Latencies = append(report.Latencies, workerReport.Latencies...)
+		for status, count := range workerReport.StatusCodes {
+			report.StatusCodes[status] += count
+		}
+	}
+	slices.Sort(report.Latencies)
+	return report, nil
+}
+
+// PrintLoadTestReport prints the report in the given format, which must be one of
+// AllLoadTestReportFormatStrings.
+func PrintLoadTestReport(writer io.Writer, report *LoadTestReport, format string) error {
+	switch format {
+	case "text":
+		return printLoadTestReportAsText(writer, report)
+	case "json":
+		return printLoadTestReportAsJSON(writer, report)
+	default:
+		return fmt.Errorf("unknown load test report format: %q", format)
+	}
+}
+
+// *** PRIVATE ***
+
+type loadTestOptions struct {
+	concurrency int
+	requests    int
+	duration    time.Duration
+	rate        float64
+}
+
+func newLoadTestOptions() *loadTestOptions {
+	return &loadTestOptions{
+		concurrency: 1,
+	}
+}
+
+// loadTestHistogramBucket is a bucket of a latency histogram.
+type loadTestHistogramBucket struct {
+	// upperBound is the slowest latency in the bucket.
+	upperBound time.Duration
+	count      int
+}
+
+// loadTestHistogram divides the range of latencies of the report into equally
+// sized buckets.
+func loadTestHistogram(report *LoadTestReport) []loadTestHistogramBucket {
+	if len(report.Latencies) == 0 {
+		return nil
+	}
+	fastest, slowest := report.Latencies[0], report.Latencies[len(report.Latencies)-1]
+	numBuckets := loadTestHistogramBuckets
+	if fastest == slowest {
+		numBuckets = 1
+	}
+	buckets := make([]loadTestHistogramBucket, numBuckets)
+	for i := range buckets {
+		buckets[i].upperBound = fastest + (slowest-fastest)*time.Duration(i+1)/time.Duration(numBuckets)
+	}
+	var i int
+	for _, latency := range report.Latencies {
+		for latency > buckets[i].upperBound {
+			i++
+		}
+		buckets[i].count++
+	}
+	return buckets
+}
+
+func printLoadTestReportAsText(writer io.Writer, report *LoadTestReport) error {
+	var buffer bytes.Buffer
+	_, _ = fmt.Fprintf(&buffer, "Summary:\n")
+	_, _ = fmt.Fprintf(&buffer, "  Requests:    %d\n", report.Requests)
+	_, _ = fmt.Fprintf(&buffer, "  Duration:    %s\n", formatLatency(report.Duration))
+	_, _ = fmt.Fprintf(&buffer, "  Throughput:  %.2f requests/sec\n", report.RequestsPerSecond())
+	if len(report.Latencies) > 0 {
+		_, _ = fmt.Fprintf(&buffer, "\nLatency:\n")
+		_, _ = fmt.Fprintf(&buffer, "  Fastest:     %s\n", formatLatency(report.Latencies[0]))
+		_, _ = fmt.Fprintf(&buffer, "  Average:     %s\n", formatLatency(report.Average()))
+		_, _ = fmt.Fprintf(&buffer, "  Slowest:     %s\n", formatLatency(report.Latencies[len(report.Latencies)-1]))
+		_, _ = fmt.Fprintf(&buffer, "  p50:         %s\n", formatLatency(report.Percentile(50)))
+		_, _ = fmt.Fprintf(&buffer, "  p90:         %s\n", formatLatency(report.Percentile(90)))
+		_, _ = fmt.Fprintf(&buffer, "  p99:         %s\n", formatLatency(report.Percentile(99)))
+
+		histogram := loadTestHistogram(report)
+		var maxCount, upperBoundWidth, countWidth int
+		for _, bucket := range histogram {
+			maxCount = max(maxCount, bucket.count)
+			upperBoundWidth = max(upperBoundWidth, len(formatLatency(bucket.upperBound)))
+			countWidth = max(countWidth, len(fmt.Sprint(bucket.count)))
+		}
+		_, _ = fmt.Fprintf(&buffer, "\nLatency histogram:\n")
+		for _, bucket := range histogram {
+			line := fmt.Sprintf(
+				"  %*s [%*d] %s",
+				upperBoundWidth,
+				formatLatency(bucket.upperBound),
+				countWidth,
+				bucket.count,
+				strings.Repeat("■", bucket.count*loadTestHistogramWidth/maxCount),
+			)
+			_, _ = buffer.WriteString(strings.TrimRight(line, " "))
+			_ = buffer.WriteByte('\n')
+		}
+	}
+	_, _ = fmt.Fprintf(&buffer, "\nStatus codes:\n")
+	for _, status := range xslices.MapKeysToSortedSlice(report.StatusCodes) {
+		_, _ = fmt.Fprintf(&buffer, "  %-20s %d\n", status, report.StatusCodes[status])
+	}
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func printLoadTestReportAsJSON(writer io.Writer, report *LoadTestReport) error {
+	externalReport := externalLoadTestReport{
+		Requests:          report.Requests,
+		DurationMS:        durationToMilliseconds(report.Duration),
+		RequestsPerSecond: report.RequestsPerSecond(),
+		StatusCodes:       report.StatusCodes,
+	}
+	if len(report.Latencies) > 0 {
+		externalReport.LatencyMS = &externalLoadTestLatencies{
+			Fastest: durationToMilliseconds(report.Latencies[0]),
+			Average: durationToMilliseconds(report.Average()),
+			Slowest: durationToMilliseconds(report.Latencies[len(report.Latencies)-1]),
+			P50:     durationToMilliseconds(report.Percentile(50)),
+			P90:     durationToMilliseconds(report.Percentile(90)),
+			P99:     durationToMilliseconds(report.Percentile(99)),
+		}
+		for _, bucket := range loadTestHistogram(report) {
+			externalReport.Histogram = append(externalReport.Histogram, externalLoadTestHistogramBucket{
+				UpperBoundMS: durationToMilliseconds(bucket.upperBound),
+				Count:        bucket.count,
+			})
+		}
+	}
+	data, err := json.Marshal(externalReport)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(append(data, '\n'))
+	return err
+}
+
+func formatLatency(duration time.Duration) string {
+	return duration.Round(time.Microsecond).String()
+}
+
+func durationToMilliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+type externalLoadTestReport struct {
+	Requests          int                               `json:"requests"`
+	DurationMS        float64                           `json:"duration_ms"`
+	RequestsPerSecond float64                           `json:"requests_per_second"`
+	LatencyMS         *externalLoadTestLatencies        `json:"latency_ms,omitempty"`
+	Histogram         []externalLoadTestHistogramBucket `json:"histogram,omitempty"`
+	StatusCodes       map[string]int                    `json:"status_codes"`
+}
+
+type externalLoadTestLatencies struct {
+	Fastest float64 `json:"fastest"`
+	Average float64 `json:"average"`
+	Slowest float64 `json:"slowest"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P99     float64 `json:"p99"`
+}
+
+type externalLoadTestHistogramBucket struct {
+	UpperBoundMS float64 `json:"upper_bound_ms"`
+	Count        int     `json:"count"`
+}
@@ -0,0 +1,170 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"buf.build/go/app"
+	"buf.build/go/app/appext"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/verbose"
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestRunLoadTest(t *testing.T) {
+	t.Parallel()
+	descriptors, err := (&protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{"./testdata"},
+		}),
+	}).Compile(context.Background(), "ping.proto")
+	require.NoError(t, err)
+	methodDescriptor := descriptors[0].Services().ByName("PingService").Methods().ByName("Ping")
+	resolver, err := protoencoding.NewResolver(
+		protodesc.ToFileDescriptorProto(emptypb.File_google_protobuf_empty_proto),
+		protodesc.ToFileDescriptorProto(descriptors[0]),
+	)
+	require.NoError(t, err)
+
+	// Every fourth request fails.
+	var calls atomic.Int64
+	mux := http.NewServeMux()
+	mux.Handle("/foo.bar.PingService/Ping", connect.NewUnaryHandler(
+		"/foo.bar.PingService/Ping",
+		func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			if calls.Add(1)%4 == 0 {
+				return nil, connect.NewError(connect.CodeUnavailable, errors.New("try again"))
+			}
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+	))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	nameContainer, err := appext.NewNameContainer(app.NewContainer(nil, nil, io.Discard, io.Discard), "buf")
+	require.NoError(t, err)
+	container := appext.NewContainer(nameContainer, nil)
+	newInvoker := func() Invoker {
+		return NewInvoker(
+			container,
+			verbose.NopPrinter,
+			methodDescriptor,
+			resolver,
+			false,
+			server.Client(),
+			nil,
+			server.URL+"/foo.bar.PingService/Ping",
+			io.Discard,
+			InvokerWithRPCErrors(),
+		)
+	}
+
+	t.Run("requests", func(t *testing.T) {
+		report, err := RunLoadTest(
+			context.Background(),
+			newInvoker,
+			"(argument)",
+			[]byte("{}"),
+			http.Header{},
+			LoadTestWithRequests(40),
+			LoadTestWithConcurrency(4),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 40, report.Requests)
+		assert.Len(t, report.Latencies, 40)
+		assert.Equal(t, 40, report.StatusCodes["ok"]+report.StatusCodes[connect.CodeUnavailable.String()])
+		assert.Positive(t, report.StatusCodes[connect.CodeUnavailable.String()])
+		assert.LessOrEqual(t, report.Percentile(50), report.Percentile(99))
+
+		var buffer bytes.Buffer
+		require.NoError(t, PrintLoadTestReport(&buffer, report, "json"))
+		var external externalLoadTestReport
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &external))
+		assert.Equal(t, 40, external.Requests)
+		require.NotNil(t, external.LatencyMS)
+		var histogramCount int
+		for _, bucket := range external.Histogram {
+			histogramCount += bucket.Count
+		}
+		assert.Equal(t, 40, histogramCount)
+	})
+	t.Run("duration_and_rate", func(t *testing.T) {
+		report, err := RunLoadTest(
+			context.Background(),
+			newInvoker,
+			"(argument)",
+			nil,
+			http.Header{},
+			LoadTestWithDuration(200*time.Millisecond),
+			LoadTestWithRate(50),
+			LoadTestWithConcurrency(2),
+		)
+		require.NoError(t, err)
+		// 50 requests per second for 200ms is about 10 requests.
+		assert.Positive(t, report.Requests)
+		assert.LessOrEqual(t, report.Requests, 12)
+	})
+	t.Run("invalid_data", func(t *testing.T) {
+		_, err := RunLoadTest(
+			context.Background(),
+			newInvoker,
+			"(argument)",
+			[]byte("{"),
+			http.Header{},
+			LoadTestWithRequests(10),
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestLoadTestReport(t *testing.T) {
+	t.Parallel()
+	report := &LoadTestReport{
+		Requests: 10,
+		Duration: 2 * time.Second,
+	}
+	for i := 1; i <= 10; i++ {
+		report.Latencies = append(report.Latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 5.0, report.RequestsPerSecond())
+	assert.Equal(t, 5*time.Millisecond, report.Percentile(50))
+	assert.Equal(t, 9*time.Millisecond, report.Percentile(90))
+	assert.Equal(t, 10*time.Millisecond, report.Percentile(99))
+	assert.Equal(t, 5500*time.Microsecond, report.Average())
+	histogram := loadTestHistogram(report)
+	require.Len(t, histogram, loadTestHistogramBuckets)
+	assert.Equal(t, time.Duration(1900)*time.Microsecond, histogram[0].upperBound)
+	assert.Equal(t, 10*time.Millisecond, histogram[len(histogram)-1].upperBound)
+	var count int
+	for _, bucket := range histogram {
+		count += bucket.count
+	}
+	assert.Equal(t, 10, count)
+}
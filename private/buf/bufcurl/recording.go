@@ -0,0 +1,417 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/diff/diffmyers"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const recordingStatusOK = "ok"
+
+// unrecordedRequestHeaders are the request headers that are not recorded.
+//
+// Credentials are not written to recordings, and the other headers are set by
+// the RPC protocol or the transport.
+var unrecordedRequestHeaders = map[string]struct{}{
+	"Accept-Encoding":          {},
+	"Authorization":            {},
+	"Connect-Accept-Encoding":  {},
+	"Connect-Content-Encoding": {},
+	"Connect-Protocol-Version": {},
+	"Connect-Timeout-Ms":       {},
+	"Content-Encoding":         {},
+	"Content-Type":             {},
+	"Grpc-Accept-Encoding":     {},
+	"Grpc-Encoding":            {},
+	"Grpc-Timeout":             {},
+	"Te":                       {},
+	"User-Agent":               {},
+	"X-Grpc-Web":               {},
+	"X-User-Agent":             {},
+}
+
+// Recording is a recording of a call, as written by buf curl --record.
+type Recording struct {
+	// Method is the full name of the invoked method.
+	Method string `json:"method"`
+	// RequestHeaders are the request headers, without credentials and headers
+	// set by the RPC protocol.
+	RequestHeaders http.Header `json:"request_headers,omitempty"`
+	// Requests are the request messages, in JSON format.
+	Requests []json.RawMessage `json:"requests"`
+	// ResponseHeaders are the response headers.
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	// Responses are the response messages, in JSON format.
+	Responses []json.RawMessage `json:"responses"`
+	// ResponseTrailers are the response trailers.
+	ResponseTrailers http.Header `json:"response_trailers,omitempty"`
+	// Status is the status of the call.
+	Status RecordingStatus `json:"status"`
+}
+
+// RecordingStatus is the status of a recorded call.
+type RecordingStatus struct {
+	// Code is "ok" if the call succeeded, and the Connect code of the error
+	// otherwise.
+	Code string `json:"code"`
+	// Message is the message of the error, if any.
+	Message string `json:"message,omitempty"`
+}
+
+// ReadRecording reads a Recording in JSON format.
+func ReadRecording(reader io.Reader) (*Recording, error) {
+	recording := &Recording{}
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(recording); err != nil {
+		return nil, fmt.Errorf("invalid recording: %w", err)
+	}
+	return recording, nil
+}
+
+// WriteRecording writes a Recording in JSON format.
+func WriteRecording(writer io.Writer, recording *Recording) error {
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "%s\n", data)
+	return err
+}
+
+// Recorder records a call made by a Connect client.
+type Recorder struct {
+	md            protoreflect.MethodDescriptor
+	jsonMarshaler protoencoding.Marshaler
+	wireUnmarshal protoencoding.Unmarshaler
+
+	lock      sync.Mutex
+	recording Recording
+	conn      connect.StreamingClientConn
+	err       error
+}
+
+// NewRecorder returns a new Recorder for calls to the method described by the
+// given descriptor. The resolver is used to resolve Any messages and extensions
+// in the recorded messages.
+//
+// The Recorder must be added to the client with connect.WithInterceptors.
+func NewRecorder(md protoreflect.MethodDescriptor, res protoencoding.Resolver) *Recorder {
+	return &Recorder{
+		md:            md,
+		jsonMarshaler: protoencoding.NewJSONMarshaler(res),
+		wireUnmarshal: protoencoding.NewWireUnmarshaler(res),
+		recording: Recording{
+			Method:    string(md.FullName()),
+			Requests:  []json.RawMessage{},
+			Responses: []json.RawMessage{},
+			Status:    RecordingStatus{Code: recordingStatusOK},
+		},
+	}
+}
+
+var _ connect.Interceptor = (*Recorder)(nil)
+
+// WrapUnary implements connect.Interceptor.
+func (r *Recorder) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		r.recordRequestHeaders(req.Header())
+		if err := r.recordRequest(req.Any()); err != nil {
+			return nil, err
+		}
+		resp, err := next(ctx, req)
+		if err != nil {
+			r.recordError(err)
+			return nil, err
+		}
+		r.lock.Lock()
+		r.recording.ResponseHeaders = resp.Header().Clone()
+		r.recording.ResponseTrailers = resp.Trailer().Clone()
+		r.lock.Unlock()
+		if err := r.recordResponse(resp.Any()); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (r *Recorder) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		r.lock.Lock()
+		r.conn = conn
+		r.lock.Unlock()
+		return &recordingStreamingClientConn{
+			StreamingClientConn: conn,
+			recorder:            r,
+		}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (r *Recorder) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// Recording returns the recording of the call.
+//
+// This must only be called once the call has completed.
+func (r *Recorder) Recording() *Recording {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	recording := r.recording
+	if r.conn != nil {
+		recording.RequestHeaders = filterRecordedRequestHeaders(r.conn.RequestHeader())
+		recording.ResponseHeaders = r.conn.ResponseHeader().Clone()
+		recording.ResponseTrailers = r.conn.ResponseTrailer().Clone()
+	}
+	if len(recording.ResponseHeaders) == 0 {
+		recording.ResponseHeaders = nil
+	}
+	if len(recording.ResponseTrailers) == 0 {
+		recording.ResponseTrailers = nil
+	}
+	return &recording
+}
+
+// DiffRecordings compares the status and responses of two recordings of the
+// same method, and returns the differences in the unified diff format.
+//
+// The fields at the given paths are cleared in all responses before comparing
+// them. A path is a sequence of field names separated by dots, such as
+// "book.create_time", and applies to all elements of repeated and map fields
+// it traverses.
+//
+// Returns nil if there are no differences.
+func DiffRecordings(
+	expected *Recording,
+	actual *Recording,
+	md protoreflect.MethodDescriptor,
+	res protoencoding.Resolver,
+	ignoreFieldPaths []string,
+) ([]byte, error) {
+	for _, ignoreFieldPath := range ignoreFieldPaths {
+		if err := validateFieldPath(md.Output(), ignoreFieldPath); err != nil {
+			return nil, err
+		}
+	}
+	expectedLines, err := recordingToComparableLines(expected, md, res, ignoreFieldPaths)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recording: %w", err)
+	}
+	actualLines, err := recordingToComparableLines(actual, md, res, ignoreFieldPaths)
+	if err != nil {
+		return nil, err
+	}
+	edits := diffmyers.Diff(expectedLines, actualLines)
+	if len(edits) == 0 {
+		return nil, nil
+	}
+	return diffmyers.Print(expectedLines, actualLines, edits)
+}
+
+// *** PRIVATE ***
+
+type recordingStreamingClientConn struct {
+	connect.StreamingClientConn
+
+	recorder *Recorder
+}
+
+func (c *recordingStreamingClientConn) Send(msg any) error {
+	if err := c.recorder.recordRequest(msg); err != nil {
+		return err
+	}
+	return c.StreamingClientConn.Send(msg)
+}
+
+func (c *recordingStreamingClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			c.recorder.recordError(err)
+		}
+		return err
+	}
+	return c.recorder.recordResponse(msg)
+}
+
+func (r *Recorder) recordRequestHeaders(headers http.Header) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.recording.RequestHeaders = filterRecordedRequestHeaders(headers)
+}
+
+func (r *Recorder) recordRequest(msg any) error {
+	protoMessage, ok := msg.(proto.Message)
+	if !ok {
+		return fmt.Errorf("unexpected request message type %T", msg)
+	}
+	// Messages are reused between sends, so they must be marshaled right away.
+	data, err := r.jsonMarshaler.Marshal(protoMessage)
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.recording.Requests = append(r.recording.Requests, data)
+	return nil
+}
+
+func (r *Recorder) recordResponse(msg any) error {
+	deferred, ok := msg.(*deferredMessage)
+	if !ok {
+		return fmt.Errorf("unexpected response message type %T", msg)
+	}
+	responseMsg := dynamicpb.NewMessage(r.md.Output())
+	if err := r.wireUnmarshal.Unmarshal(deferred.data, responseMsg); err != nil {
+		return err
+	}
+	data, err := r.jsonMarshaler.Marshal(responseMsg)
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.recording.Responses = append(r.recording.Responses, data)
+	return nil
+}
+
+func (r *Recorder) recordError(err error) {
+	var connErr *connect.Error
+	if !errors.As(err, &connErr) {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.recording.Status = RecordingStatus{
+		Code:    connErr.Code().String(),
+		Message: connErr.Message(),
+	}
+}
+
+func filterRecordedRequestHeaders(headers http.Header) http.Header {
+	filtered := make(http.Header)
+	for name, values := range headers {
+		if _, ok := unrecordedRequestHeaders[http.CanonicalHeaderKey(name)]; ok {
+			continue
+		}
+		filtered[name] = append([]string(nil), values...)
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// recordingToComparableLines returns the status and responses of the recording
+// as lines of text, with the ignored fields cleared.
+func recordingToComparableLines(
+	recording *Recording,
+	md protoreflect.MethodDescriptor,
+	res protoencoding.Resolver,
+	ignoreFieldPaths []string,
+) ([][]byte, error) {
+	unmarshaler := protoencoding.NewJSONUnmarshaler(res)
+	marshaler := protoencoding.NewJSONMarshaler(res, protoencoding.JSONMarshalerWithIndent())
+	var buffer bytes.Buffer
+	status := recording.Status.Code
+	if recording.Status.Message != "" {
+		status += ": " + recording.Status.Message
+	}
+	fmt.Fprintf(&buffer, "status: %s\n", status)
+	for i, response := range recording.Responses {
+		msg := dynamicpb.NewMessage(md.Output())
+		if err := unmarshaler.Unmarshal(response, msg); err != nil {
+			return nil, err
+		}
+		for _, ignoreFieldPath := range ignoreFieldPaths {
+			clearFieldPathInAll(msg, strings.Split(ignoreFieldPath, "."))
+		}
+		data, err := marshaler.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buffer, "response %d:\n%s\n", i+1, data)
+	}
+	lines := bytes.SplitAfter(buffer.Bytes(), []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
+func validateFieldPath(md protoreflect.MessageDescriptor, fieldPath string) error {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return fmt.Errorf("field path %q does not exist in %s", fieldPath, md.FullName())
+		}
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if i < len(names)-1 {
+			if fd.Message() == nil {
+				return fmt.Errorf("field path %q does not exist in %s", fieldPath, md.FullName())
+			}
+			md = fd.Message()
+		}
+	}
+	return nil
+}
+
+// clearFieldPathInAll clears the field at the path, in every element of the
+// repeated and map fields the path traverses.
+func clearFieldPathInAll(msg protoreflect.Message, names []string) {
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(names[0]))
+	if fd == nil || !msg.Has(fd) {
+		return
+	}
+	if len(names) == 1 {
+		msg.Clear(fd)
+		return
+	}
+	switch {
+	case fd.IsList():
+		list := msg.Mutable(fd).List()
+		for i := range list.Len() {
+			clearFieldPathInAll(list.Get(i).Message(), names[1:])
+		}
+	case fd.IsMap():
+		msg.Mutable(fd).Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+			clearFieldPathInAll(value.Message(), names[1:])
+			return true
+		})
+	default:
+		clearFieldPathInAll(msg.Mutable(fd).Message(), names[1:])
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"buf.build/go/app"
+	"buf.build/go/app/appext"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/verbose"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestRecording(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, resolver := newTestLibraryService(t, nil)
+	getBook := serviceDescriptor.Methods().ByName("GetBook")
+	watchBooks := serviceDescriptor.Methods().ByName("WatchBooks")
+	newBook := func(name string, pages int64) *dynamicpb.Message {
+		book := dynamicpb.NewMessage(getBook.Output())
+		book.Set(book.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString(name))
+		book.Set(book.Descriptor().Fields().ByName("pages"), protoreflect.ValueOfInt32(int32(pages)))
+		return book
+	}
+	initializeRequest := connect.WithRequestInitializer(func(spec connect.Spec, msg any) error {
+		*msg.(*dynamicpb.Message) = *dynamicpb.NewMessage(spec.Schema.(protoreflect.MethodDescriptor).Input())
+		return nil
+	})
+	// The number of pages changes on every call, like a timestamp would.
+	var calls atomic.Int64
+	mux := http.NewServeMux()
+	mux.Handle("/foo.bar.LibraryService/GetBook", connect.NewUnaryHandler(
+		"/foo.bar.LibraryService/GetBook",
+		func(_ context.Context, req *connect.Request[dynamicpb.Message]) (*connect.Response[dynamicpb.Message], error) {
+			name := req.Msg.Get(req.Msg.Descriptor().Fields().ByName("name")).String()
+			if name == "missing" {
+				return nil, connect.NewError(connect.CodeNotFound, errors.New("book not found"))
+			}
+			resp := connect.NewResponse(newBook(name, calls.Add(1)))
+			resp.Header().Set("X-Served-By", "test")
+			return resp, nil
+		},
+		connect.WithSchema(getBook),
+		initializeRequest,
+	))
+	mux.Handle("/foo.bar.LibraryService/WatchBooks", connect.NewServerStreamHandler(
+		"/foo.bar.LibraryService/WatchBooks",
+		func(_ context.Context, _ *connect.Request[dynamicpb.Message], stream *connect.ServerStream[dynamicpb.Message]) error {
+			for _, name := range []string{"one", "two"} {
+				if err := stream.Send(newBook(name, 1)); err != nil {
+					return err
+				}
+			}
+			stream.ResponseTrailer().Set("X-Count", "2")
+			return nil
+		},
+		connect.WithSchema(watchBooks),
+		initializeRequest,
+	))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	nameContainer, err := appext.NewNameContainer(app.NewContainer(nil, nil, io.Discard, io.Discard), "buf")
+	require.NoError(t, err)
+	container := appext.NewContainer(nameContainer, nil)
+	record := func(md protoreflect.MethodDescriptor, data string) *Recording {
+		recorder := NewRecorder(md, resolver)
+		invoker := NewInvoker(
+			container,
+			verbose.NopPrinter,
+			md,
+			resolver,
+			false,
+			server.Client(),
+			[]connect.ClientOption{connect.WithInterceptors(recorder)},
+			server.URL+"/foo.bar.LibraryService/"+string(md.Name()),
+			io.Discard,
+			InvokerWithRPCErrors(),
+		)
+		headers := http.Header{}
+		headers.Set("X-Test", "a")
+		headers.Set("Authorization", "secret")
+		err := invoker.Invoke(context.Background(), "(argument)", strings.NewReader(data), headers)
+		connectErr := &connect.Error{}
+		if err != nil && !errors.As(err, &connectErr) {
+			require.NoError(t, err)
+		}
+		return recorder.Recording()
+	}
+
+	recording := record(getBook, `{"name": "dune"}`)
+	assert.Equal(t, "foo.bar.LibraryService.GetBook", recording.Method)
+	assert.Equal(t, http.Header{"X-Test": {"a"}}, recording.RequestHeaders)
+	assert.Equal(t, "test", recording.ResponseHeaders.Get("X-Served-By"))
+	assert.Equal(t, RecordingStatus{Code: "ok"}, recording.Status)
+	require.Len(t, recording.Requests, 1)
+	assert.JSONEq(t, `{"name": "dune"}`, string(recording.Requests[0]))
+	require.Len(t, recording.Responses, 1)
+	assert.JSONEq(t, `{"name": "dune", "pages": 1}`, string(recording.Responses[0]))
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, WriteRecording(buffer, recording))
+	readRecording, err := ReadRecording(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, recording.Status, readRecording.Status)
+	assert.Equal(t, recording.RequestHeaders, readRecording.RequestHeaders)
+
+	replayed := record(getBook, `{"name": "dune"}`)
+	diff, err := DiffRecordings(readRecording, replayed, getBook, resolver, nil)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		` status: ok
+ response 1:
+ {
+   "name": "dune",
+@@ -5,1 +5,1 @@
+-  "pages": 1
++  "pages": 2
+ }
+`,
+		normalizeJSONSpaces(string(diff)),
+	)
+	diff, err = DiffRecordings(readRecording, replayed, getBook, resolver, []string{"pages"})
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+	_, err = DiffRecordings(readRecording, replayed, getBook, resolver, []string{"pages.value"})
+	require.EqualError(t, err, `field path "pages.value" does not exist in foo.bar.Book`)
+
+	missing := record(getBook, `{"name": "missing"}`)
+	assert.Equal(t, RecordingStatus{Code: "not_found", Message: "book not found"}, missing.Status)
+	assert.Empty(t, missing.Responses)
+	diff, err = DiffRecordings(readRecording, missing, getBook, resolver, []string{"pages"})
+	require.NoError(t, err)
+	assert.Contains(t, string(diff), "+status: not_found: book not found")
+
+	stream := record(watchBooks, `{}`)
+	assert.Equal(t, RecordingStatus{Code: "ok"}, stream.Status)
+	assert.Equal(t, http.Header{"X-Test": {"a"}}, stream.RequestHeaders)
+	assert.Equal(t, "2", stream.ResponseTrailers.Get("X-Count"))
+	require.Len(t, stream.Responses, 2)
+	assert.JSONEq(t, `{"name": "two", "pages": 1}`, string(stream.Responses[1]))
+}
+
+func TestClearFieldPathInAll(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, resolver := newTestLibraryService(t, nil)
+	listBooks := serviceDescriptor.Methods().ByName("ListBooks")
+	msg := dynamicpb.NewMessage(listBooks.Output())
+	require.NoError(t, protoencoding.NewJSONUnmarshaler(resolver).Unmarshal(
+		[]byte(`{"books": [{"name": "one", "pages": 1}, {"name": "two", "pages": 2}]}`),
+		msg,
+	))
+	clearFieldPathInAll(msg, []string{"books", "pages"})
+	data, err := protoencoding.NewJSONMarshaler(resolver).Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"books": [{"name": "one"}, {"name": "two"}]}`, string(data))
+}
+
+// normalizeJSONSpaces removes the random extra space that protojson may add
+// after a colon, so that its output can be compared.
+func normalizeJSONSpaces(s string) string {
+	return strings.ReplaceAll(s, ":  ", ": ")
+}
@@ -0,0 +1,375 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"buf.build/go/protovalidate"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"golang.org/x/term"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	replPrompt             = "> "
+	replContinuationPrompt = "... "
+
+	replCloseCommand  = "/close"
+	replCancelCommand = "/cancel"
+	replHelpCommand   = "/help"
+
+	keyCtrlC = 3
+)
+
+var (
+	replCommands = []string{
+		replCancelCommand,
+		replCloseCommand,
+		replHelpCommand,
+	}
+
+	// REPLHelp describes how to use a REPL.
+	REPLHelp = `Type request messages in JSON, over one or more lines. Press Tab to complete field names.
+Press Ctrl-D or type /close to close the request stream, and wait for the server to finish.
+Press Ctrl-C or type /cancel to cancel the RPC.
+`
+)
+
+// REPL is an interactive session in a terminal, in which the request messages
+// of a client-streaming or bidirectional-streaming RPC are typed, and the
+// response messages are printed as they arrive.
+//
+// The terminal must be in raw mode.
+type REPL struct {
+	md          protoreflect.MethodDescriptor
+	unmarshaler protoencoding.Unmarshaler
+	terminal    *term.Terminal
+	// Validates request messages, if request validation is enabled.
+	validator protovalidate.Validator
+	// Set when Ctrl-C is read, which term.Terminal reports as io.EOF
+	// just like Ctrl-D.
+	cancelRequested atomic.Bool
+	// The lines of a message that spans multiple lines.
+	pending strings.Builder
+}
+
+// NewREPL returns a new REPL for the method described by the given descriptor,
+// that reads from the given terminal input and writes to the given terminal
+// output. The given resolver is used to resolve Any messages and extensions
+// in request messages.
+//
+// The REPL is used with InvokerWithREPL.
+func NewREPL(
+	in io.Reader,
+	out io.Writer,
+	md protoreflect.MethodDescriptor,
+	res protoencoding.Resolver,
+) *REPL {
+	repl := &REPL{
+		md:          md,
+		unmarshaler: protoencoding.NewJSONUnmarshaler(res, protoencoding.JSONUnmarshalerWithDisallowUnknown()),
+	}
+	repl.terminal = term.NewTerminal(
+		struct {
+			io.Reader
+			io.Writer
+		}{
+			Reader: &replReader{reader: in, cancelRequested: &repl.cancelRequested},
+			Writer: out,
+		},
+		replPrompt,
+	)
+	repl.terminal.AutoCompleteCallback = repl.complete
+	return repl
+}
+
+// SetSize sets the size of the terminal.
+func (r *REPL) SetSize(width int, height int) error {
+	return r.terminal.SetSize(width, height)
+}
+
+// Write writes to the terminal, above the line being edited.
+func (r *REPL) Write(data []byte) (int, error) {
+	return r.terminal.Write(data)
+}
+
+// *** PRIVATE ***
+
+// next implements messageProvider.
+//
+// It returns io.EOF when the request stream should be closed, and a
+// *connect.Error with connect.CodeCanceled when the RPC should be canceled.
+func (r *REPL) next(msg proto.Message) error {
+	for {
+		line, err := r.terminal.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) && r.cancelRequested.Swap(false) {
+				return newREPLCanceledError()
+			}
+			return err
+		}
+		if r.pending.Len() == 0 {
+			switch command := strings.TrimSpace(line); {
+			case command == "":
+				continue
+			case command == replCloseCommand:
+				return io.EOF
+			case command == replCancelCommand:
+				return newREPLCanceledError()
+			case command == replHelpCommand:
+				r.printf("%s", REPLHelp)
+				continue
+			case strings.HasPrefix(command, "/"):
+				r.printf("Unknown command %q, type %s for help.\n", command, replHelpCommand)
+				continue
+			}
+		}
+		r.pending.WriteString(line)
+		r.pending.WriteString("\n")
+		data := []byte(r.pending.String())
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				// The message continues on the next line.
+				r.terminal.SetPrompt(replContinuationPrompt)
+				continue
+			}
+			r.resetPending()
+			r.printf("Invalid JSON: %v\n", err)
+			continue
+		}
+		if decoder.More() {
+			r.resetPending()
+			r.printf("Invalid JSON: expected a single message\n")
+			continue
+		}
+		r.resetPending()
+		proto.Reset(msg)
+		if err := r.unmarshaler.Unmarshal(data, msg); err != nil {
+			r.printf("Invalid %s: %v\n", r.md.Input().FullName(), err)
+			continue
+		}
+		if r.validator != nil {
+			if err := r.validator.Validate(msg); err != nil {
+				r.printf("Invalid %s: %v\n", r.md.Input().FullName(), err)
+				continue
+			}
+		}
+		return nil
+	}
+}
+
+func (r *REPL) resetPending() {
+	r.pending.Reset()
+	r.terminal.SetPrompt(replPrompt)
+}
+
+func (r *REPL) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(r.terminal, format, args...)
+}
+
+// complete completes field names and commands on Tab.
+func (r *REPL) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	start := pos
+	for start > 0 && isIdentifierByte(line[start-1]) {
+		start--
+	}
+	prefix := line[start:pos]
+	var candidates []string
+	if r.pending.Len() == 0 && strings.HasPrefix(line, "/") {
+		start, prefix = 0, line[:pos]
+		candidates = replCommands
+	} else {
+		md := messageDescriptorAtEnd(r.md.Input(), r.pending.String()+line[:start])
+		if md == nil {
+			return "", 0, false
+		}
+		fields := md.Fields()
+		for i := range fields.Len() {
+			candidates = append(candidates, fields.Get(i).JSONName())
+		}
+	}
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", 0, false
+	case 1:
+		completion := matches[0]
+		// Close the quoted field name.
+		if start > 0 && line[start-1] == '"' && !strings.HasPrefix(line[pos:], `"`) {
+			completion += `": `
+		}
+		return line[:start] + completion + line[pos:], start + len(completion), true
+	default:
+		commonPrefix := longestCommonPrefix(matches)
+		if len(commonPrefix) > len(prefix) {
+			return line[:start] + commonPrefix + line[pos:], start + len(commonPrefix), true
+		}
+		slices.Sort(matches)
+		r.printf("%s\n", strings.Join(matches, "  "))
+		return line, pos, true
+	}
+}
+
+// messageDescriptorAtEnd returns the descriptor of the message whose JSON
+// object is open at the end of the given partial JSON text, or nil if the end
+// is not in the object of a message.
+func messageDescriptorAtEnd(root protoreflect.MessageDescriptor, text string) protoreflect.MessageDescriptor {
+	type frame struct {
+		// The message of an object, or nil.
+		md protoreflect.MessageDescriptor
+		// The map field of an object, or the repeated field of an array.
+		fd protoreflect.FieldDescriptor
+	}
+	var stack []frame
+	var lastString, lastKey string
+	var current strings.Builder
+	inString, escaped := false, false
+	fieldOf := func(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+		if md == nil {
+			return nil
+		}
+		if fd := md.Fields().ByJSONName(name); fd != nil {
+			return fd
+		}
+		return md.Fields().ByName(protoreflect.Name(name))
+	}
+	for _, c := range text {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				lastString = current.String()
+			default:
+				current.WriteRune(c)
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+			current.Reset()
+		case ':':
+			lastKey = lastString
+		case '{':
+			var next frame
+			switch {
+			case len(stack) == 0:
+				next.md = root
+			case stack[len(stack)-1].fd != nil && stack[len(stack)-1].md == nil:
+				// An element of a repeated field, or a value of a map field.
+				fd := stack[len(stack)-1].fd
+				if fd.IsMap() {
+					fd = fd.MapValue()
+				}
+				next.md = fd.Message()
+			default:
+				if fd := fieldOf(stack[len(stack)-1].md, lastKey); fd != nil {
+					if fd.IsMap() {
+						next.fd = fd
+					} else {
+						next.md = fd.Message()
+					}
+				}
+			}
+			if next.md != nil && isWellKnownJSONMessage(next.md) {
+				next.md = nil
+			}
+			stack = append(stack, next)
+		case '[':
+			var next frame
+			if len(stack) > 0 {
+				next.fd = fieldOf(stack[len(stack)-1].md, lastKey)
+			}
+			stack = append(stack, next)
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if inString && len(stack) > 0 {
+		return stack[len(stack)-1].md
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1].md
+}
+
+// isWellKnownJSONMessage returns true for the well-known types whose JSON
+// representation is not an object with their fields.
+func isWellKnownJSONMessage(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case "google.protobuf.Any", "google.protobuf.Struct", "google.protobuf.Value":
+		return true
+	}
+	return isWellKnownScalarMessage(md)
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func longestCommonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func newREPLCanceledError() error {
+	return connect.NewError(connect.CodeCanceled, errors.New("canceled from the terminal"))
+}
+
+// replReader records when Ctrl-C is read.
+type replReader struct {
+	reader          io.Reader
+	cancelRequested *atomic.Bool
+}
+
+func (r *replReader) Read(data []byte) (int, error) {
+	n, err := r.reader.Read(data)
+	if bytes.IndexByte(data[:n], keyCtrlC) >= 0 {
+		r.cancelRequested.Store(true)
+	}
+	return n, err
+}
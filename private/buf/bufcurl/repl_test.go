@@ -0,0 +1,111 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestREPL(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, resolver := newTestLibraryService(t, nil)
+	getBook := serviceDescriptor.Methods().ByName("GetBook")
+	testREPL := func(t *testing.T, keystrokes []string, expectedNames []string, expectedErr func(*testing.T, error)) string {
+		readers := make([]io.Reader, len(keystrokes))
+		for i, keystroke := range keystrokes {
+			readers[i] = strings.NewReader(keystroke)
+		}
+		var output bytes.Buffer
+		repl := NewREPL(io.MultiReader(readers...), &output, getBook, resolver)
+		var names []string
+		for {
+			msg := dynamicpb.NewMessage(getBook.Input())
+			if err := repl.next(msg); err != nil {
+				expectedErr(t, err)
+				break
+			}
+			names = append(names, msg.Get(getBook.Input().Fields().ByName("name")).String())
+		}
+		assert.Equal(t, expectedNames, names)
+		return output.String()
+	}
+	isEOF := func(t *testing.T, err error) {
+		assert.ErrorIs(t, err, io.EOF)
+	}
+	isCanceled := func(t *testing.T, err error) {
+		assert.Equal(t, connect.CodeCanceled, connect.CodeOf(err))
+	}
+	t.Run("close", func(t *testing.T) {
+		t.Parallel()
+		testREPL(t, []string{`{"name": "one"}` + "\r", "\r", `{"name": "two"}` + "\r", "\x04"}, []string{"one", "two"}, isEOF)
+		testREPL(t, []string{`{"name": "one"}` + "\r", "/close\r"}, []string{"one"}, isEOF)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		t.Parallel()
+		testREPL(t, []string{`{"name": "one"}` + "\r", "\x03"}, []string{"one"}, isCanceled)
+		testREPL(t, []string{`{"name": "one"}` + "\r", "/cancel\r"}, []string{"one"}, isCanceled)
+	})
+	t.Run("multiple_lines", func(t *testing.T) {
+		t.Parallel()
+		output := testREPL(t, []string{"{\r", `  "name": "one",` + "\r", `  "view": "VIEW_FULL"` + "\r", "}\r", "\x04"}, []string{"one"}, isEOF)
+		assert.Contains(t, output, replContinuationPrompt)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		output := testREPL(
+			t,
+			[]string{`{"name": }` + "\r", `{"title": "one"}` + "\r", "/unknown\r", `{"name": "one"}` + "\r", "\x04"},
+			[]string{"one"},
+			isEOF,
+		)
+		assert.Contains(t, output, "Invalid JSON")
+		assert.Contains(t, output, "Invalid foo.bar.GetBookRequest")
+		assert.Contains(t, output, `Unknown command "/unknown"`)
+	})
+}
+
+func TestREPLComplete(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, resolver := newTestLibraryService(t, nil)
+	var output bytes.Buffer
+	repl := NewREPL(strings.NewReader(""), &output, serviceDescriptor.Methods().ByName("GetBook"), resolver)
+	testComplete := func(t *testing.T, line string, expectedLine string) {
+		newLine, newPos, ok := repl.complete(line, len(line), '\t')
+		require.True(t, ok)
+		assert.Equal(t, expectedLine, newLine)
+		assert.Equal(t, len(expectedLine), newPos)
+	}
+	testComplete(t, `{"na`, `{"name": `)
+	testComplete(t, `{"view": "VIEW_FULL", "filter": {"au`, `{"view": "VIEW_FULL", "filter": {"author": `)
+	testComplete(t, `{"filter": {"author": "a", "pub`, `{"filter": {"author": "a", "publishedAfter": `)
+	testComplete(t, `{"fields": ["a", "b"], "v`, `{"fields": ["a", "b"], "view": `)
+	testComplete(t, `/cl`, `/close`)
+	// Both fields and filter match, so they are printed.
+	testComplete(t, `{"fi`, `{"fi`)
+	assert.Contains(t, output.String(), "fields  filter")
+	// Timestamps are strings in JSON, so there is nothing to complete.
+	_, _, ok := repl.complete(`{"filter": {"publishedAfter": {"s`, 33, '\t')
+	assert.False(t, ok)
+	_, _, ok = repl.complete(`{"na`, 4, 'a')
+	assert.False(t, ok)
+}
@@ -0,0 +1,443 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// wellKnownScalarMessageNames are the well-known types whose JSON representation
+// is a scalar, and so can be bound to a path variable or query parameter.
+var wellKnownScalarMessageNames = map[protoreflect.FullName]struct{}{
+	"google.protobuf.Timestamp":   {},
+	"google.protobuf.Duration":    {},
+	"google.protobuf.FieldMask":   {},
+	"google.protobuf.DoubleValue": {},
+	"google.protobuf.FloatValue":  {},
+	"google.protobuf.Int64Value":  {},
+	"google.protobuf.UInt64Value": {},
+	"google.protobuf.Int32Value":  {},
+	"google.protobuf.UInt32Value": {},
+	"google.protobuf.BoolValue":   {},
+	"google.protobuf.StringValue": {},
+	"google.protobuf.BytesValue":  {},
+}
+
+// getHTTPRule returns the google.api.http annotation of the method, or nil if
+// the method has none.
+func getHTTPRule(md protoreflect.MethodDescriptor) (*annotations.HttpRule, error) {
+	options, ok := md.Options().(*descriptorpb.MethodOptions)
+	if !ok || options == nil {
+		return nil, nil
+	}
+	// The extension may have been parsed as unknown fields or as a dynamic
+	// extension, depending on where the descriptor came from. Round-tripping
+	// the options through the wire format makes it a *annotations.HttpRule.
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	options = &descriptorpb.MethodOptions{}
+	if err := proto.Unmarshal(data, options); err != nil {
+		return nil, err
+	}
+	if !proto.HasExtension(options, annotations.E_Http) {
+		return nil, nil
+	}
+	rule, ok := proto.GetExtension(options, annotations.E_Http).(*annotations.HttpRule)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for google.api.http option of method %s", md.FullName())
+	}
+	return rule, nil
+}
+
+// newTranscodedRequest returns the HTTP request for the given request message,
+// as specified by the HttpRule.
+//
+// Fields bound to variables of the path template are sent in the path, the
+// field named by the body of the rule is sent as a JSON request body, and all
+// other populated fields are sent as query parameters.
+func newTranscodedRequest(
+	ctx context.Context,
+	baseURL string,
+	rule *annotations.HttpRule,
+	msg *dynamicpb.Message,
+	res protoencoding.Resolver,
+) (*http.Request, error) {
+	method, template, err := getHTTPRuleMethodAndTemplate(rule)
+	if err != nil {
+		return nil, err
+	}
+	path, boundFieldPaths, err := expandPathTemplate(template, msg)
+	if err != nil {
+		return nil, err
+	}
+	// Everything not bound to the path and not sent in the body goes in the query.
+	unbound, ok := proto.Clone(msg).(*dynamicpb.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for clone of %s", msg.Descriptor().FullName())
+	}
+	for _, fieldPath := range boundFieldPaths {
+		if err := clearFieldPath(unbound, fieldPath); err != nil {
+			return nil, err
+		}
+	}
+	var body []byte
+	switch rule.GetBody() {
+	case "":
+	case "*":
+		if body, err = protoencoding.NewJSONMarshaler(res).Marshal(unbound); err != nil {
+			return nil, err
+		}
+		unbound = dynamicpb.NewMessage(msg.Descriptor())
+	default:
+		bodyField := msg.Descriptor().Fields().ByName(protoreflect.Name(rule.GetBody()))
+		if bodyField == nil {
+			return nil, fmt.Errorf("body field %q of the google.api.http option does not exist in %s", rule.GetBody(), msg.Descriptor().FullName())
+		}
+		if body, err = marshalFieldJSON(msg, bodyField, res); err != nil {
+			return nil, err
+		}
+		unbound.Clear(bodyField)
+	}
+	query := url.Values{}
+	if err := appendQueryParams(query, "", unbound); err != nil {
+		return nil, err
+	}
+	requestURL := strings.TrimSuffix(baseURL, "/") + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.Header.Set("Accept", "application/json")
+	return request, nil
+}
+
+// unmarshalTranscodedResponse unmarshals the JSON response body into msg, as
+// specified by the HttpRule.
+func unmarshalTranscodedResponse(
+	rule *annotations.HttpRule,
+	body []byte,
+	msg *dynamicpb.Message,
+	res protoencoding.Resolver,
+) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if responseBody := rule.GetResponseBody(); responseBody != "" {
+		responseBodyField := msg.Descriptor().Fields().ByName(protoreflect.Name(responseBody))
+		if responseBodyField == nil {
+			return fmt.Errorf("response_body field %q of the google.api.http option does not exist in %s", responseBody, msg.Descriptor().FullName())
+		}
+		wrapped, err := json.Marshal(map[string]json.RawMessage{responseBodyField.JSONName(): body})
+		if err != nil {
+			return err
+		}
+		body = wrapped
+	}
+	return protoencoding.NewJSONUnmarshaler(res).Unmarshal(body, msg)
+}
+
+// newTranscodedResponseError returns the error for a response with a non-2xx
+// status code.
+//
+// The body is expected to be a JSON google.rpc.Status, as returned by gRPC-Gateway
+// and Envoy. If it is not, the code is derived from the HTTP status code.
+func newTranscodedResponseError(statusCode int, body []byte) *connect.Error {
+	var status struct {
+		Code    int32  `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &status); err == nil && status.Code > 0 {
+		return connect.NewError(connect.Code(status.Code), errors.New(status.Message))
+	}
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return connect.NewError(httpStatusCodeToCode(statusCode), errors.New(message))
+}
+
+// *** PRIVATE ***
+
+func getHTTPRuleMethodAndTemplate(rule *annotations.HttpRule) (string, string, error) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, pattern.Get, nil
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, pattern.Put, nil
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, pattern.Post, nil
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, pattern.Delete, nil
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, pattern.Patch, nil
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetKind(), pattern.Custom.GetPath(), nil
+	default:
+		return "", "", errors.New("google.api.http option has no pattern")
+	}
+}
+
+// expandPathTemplate fills the variables of the path template with the values
+// of the fields of msg they are bound to. It returns the resulting path and the
+// paths of the bound fields.
+func expandPathTemplate(template string, msg protoreflect.Message) (string, []string, error) {
+	if !strings.HasPrefix(template, "/") {
+		return "", nil, fmt.Errorf("path template %q must start with a slash", template)
+	}
+	var path strings.Builder
+	var boundFieldPaths []string
+	rest := template
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			start = len(rest)
+		}
+		literal := rest[:start]
+		if strings.Contains(literal, "*") {
+			return "", nil, fmt.Errorf("path template %q contains wildcards outside of variables", template)
+		}
+		path.WriteString(literal)
+		if start == len(rest) {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("path template %q has an unterminated variable", template)
+		}
+		variable := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+		fieldPath, segments, _ := strings.Cut(variable, "=")
+		value, err := getPathVariableValue(msg, fieldPath)
+		if err != nil {
+			return "", nil, err
+		}
+		if value == "" {
+			return "", nil, fmt.Errorf("field %q bound to the path must be set", fieldPath)
+		}
+		if segments == "" || segments == "*" {
+			// Single segment variables escape everything, including slashes.
+			path.WriteString(url.PathEscape(value))
+		} else {
+			parts := strings.Split(value, "/")
+			for i, part := range parts {
+				parts[i] = url.PathEscape(part)
+			}
+			path.WriteString(strings.Join(parts, "/"))
+		}
+		boundFieldPaths = append(boundFieldPaths, fieldPath)
+	}
+	return path.String(), boundFieldPaths, nil
+}
+
+func getPathVariableValue(msg protoreflect.Message, fieldPath string) (string, error) {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return "", fmt.Errorf("field %q bound to the path does not exist in %s", fieldPath, msg.Descriptor().FullName())
+		}
+		if fd.IsList() || fd.IsMap() {
+			return "", fmt.Errorf("field %q bound to the path must not be repeated", fieldPath)
+		}
+		if i < len(names)-1 {
+			if fd.Message() == nil {
+				return "", fmt.Errorf("field %q bound to the path does not exist in %s", fieldPath, msg.Descriptor().FullName())
+			}
+			msg = msg.Get(fd).Message()
+			continue
+		}
+		return formatQueryValue(fd, msg.Get(fd))
+	}
+	return "", fmt.Errorf("invalid field path %q", fieldPath)
+}
+
+func clearFieldPath(msg protoreflect.Message, fieldPath string) error {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return fmt.Errorf("field %q does not exist in %s", fieldPath, msg.Descriptor().FullName())
+		}
+		if i == len(names)-1 {
+			msg.Clear(fd)
+			return nil
+		}
+		if !msg.Has(fd) {
+			return nil
+		}
+		msg = msg.Mutable(fd).Message()
+	}
+	return nil
+}
+
+// marshalFieldJSON returns the JSON representation of the value of the field.
+func marshalFieldJSON(msg protoreflect.Message, fd protoreflect.FieldDescriptor, res protoencoding.Resolver) ([]byte, error) {
+	if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+		if !msg.Has(fd) {
+			return []byte("{}"), nil
+		}
+		return protoencoding.NewJSONMarshaler(res).Marshal(msg.Get(fd).Message().Interface())
+	}
+	wrapper := dynamicpb.NewMessage(msg.Descriptor())
+	wrapper.Set(fd, msg.Get(fd))
+	data, err := protoencoding.NewJSONMarshaler(res, protoencoding.JSONMarshalerWithEmitUnpopulated()).Marshal(wrapper)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+func appendQueryParams(query url.Values, prefix string, msg protoreflect.Message) error {
+	var err error
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if fd.IsExtension() {
+			return true
+		}
+		name := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap():
+			err = fmt.Errorf("map field %q cannot be sent as a query parameter", name)
+		case fd.IsList():
+			list := value.List()
+			for i := range list.Len() {
+				var element string
+				if element, err = formatQueryValue(fd, list.Get(i)); err != nil {
+					return false
+				}
+				query.Add(name, element)
+			}
+		case fd.Message() != nil && !isWellKnownScalarMessage(fd.Message()):
+			err = appendQueryParams(query, name+".", value.Message())
+		default:
+			var element string
+			if element, err = formatQueryValue(fd, value); err != nil {
+				return false
+			}
+			query.Add(name, element)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// formatQueryValue formats a singular value as a path variable or query parameter.
+func formatQueryValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) (string, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return value.String(), nil
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(value.Bool()), nil
+	case protoreflect.EnumKind:
+		if enumValue := fd.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name()), nil
+		}
+		return strconv.Itoa(int(value.Enum())), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32), nil
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(value.Bytes()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if !isWellKnownScalarMessage(fd.Message()) {
+			return "", fmt.Errorf("message field %q cannot be sent as a path variable or repeated query parameter", fd.FullName())
+		}
+		data, err := protojson.Marshal(value.Message().Interface())
+		if err != nil {
+			return "", err
+		}
+		var s string
+		if err := json.Unmarshal(data, &s); err == nil {
+			return s, nil
+		}
+		// Wrappers of numbers and booleans are not JSON strings.
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown kind %v for field %q", fd.Kind(), fd.FullName())
+	}
+}
+
+func isWellKnownScalarMessage(md protoreflect.MessageDescriptor) bool {
+	_, ok := wellKnownScalarMessageNames[md.FullName()]
+	return ok
+}
+
+// httpStatusCodeToCode is the inverse of the HTTP mapping documented in
+// google/rpc/code.proto.
+func httpStatusCodeToCode(statusCode int) connect.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return connect.CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		return connect.CodePermissionDenied
+	case http.StatusNotFound:
+		return connect.CodeNotFound
+	case http.StatusConflict:
+		return connect.CodeAborted
+	case http.StatusPreconditionFailed:
+		return connect.CodeFailedPrecondition
+	case http.StatusTooManyRequests:
+		return connect.CodeResourceExhausted
+	case 499:
+		return connect.CodeCanceled
+	case http.StatusNotImplemented:
+		return connect.CodeUnimplemented
+	case http.StatusServiceUnavailable:
+		return connect.CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return connect.CodeDeadlineExceeded
+	case http.StatusInternalServerError:
+		return connect.CodeInternal
+	default:
+		return connect.CodeUnknown
+	}
+}
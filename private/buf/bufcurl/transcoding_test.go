@@ -0,0 +1,218 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"buf.build/go/app"
+	"buf.build/go/app/appext"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/verbose"
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestHTTPTranscoding(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, resolver := newTestLibraryService(
+		t,
+		map[string]*annotations.HttpRule{
+			"GetBook": {
+				Pattern: &annotations.HttpRule_Get{Get: "/v1/{name=shelves/*/books/*}"},
+			},
+			"CreateBook": {
+				Pattern: &annotations.HttpRule_Post{Post: "/v1/{parent=shelves/*}/books"},
+				Body:    "book",
+			},
+			"UpdateBook": {
+				Pattern: &annotations.HttpRule_Patch{Patch: "/v1/{book.name=shelves/*/books/*}"},
+				Body:    "*",
+			},
+			"ListBooks": {
+				Pattern:      &annotations.HttpRule_Get{Get: "/v1/{parent}/books"},
+				ResponseBody: "books",
+			},
+		},
+	)
+	type recordedRequest struct {
+		method      string
+		url         string
+		contentType string
+		body        string
+	}
+	var recorded recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		recorded = recordedRequest{
+			method:      r.Method,
+			url:         r.URL.String(),
+			contentType: r.Header.Get("Content-Type"),
+			body:        string(body),
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 5, "message": "book not found"}`))
+		case strings.HasSuffix(r.URL.Path, "/teapot"):
+			w.WriteHeader(http.StatusTeapot)
+		case r.URL.Path == "/v1/shelves/1/books" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[{"name": "shelves/1/books/1"}, {"name": "shelves/1/books/2"}]`))
+		default:
+			_, _ = w.Write([]byte(`{"name": "shelves/1/books/1", "title": "Dune", "pages": 412}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	nameContainer, err := appext.NewNameContainer(app.NewContainer(nil, nil, io.Discard, io.Discard), "buf")
+	require.NoError(t, err)
+	container := appext.NewContainer(nameContainer, nil)
+	invoke := func(methodName string, data string) (string, error) {
+		output := &bytes.Buffer{}
+		invoker := NewInvoker(
+			container,
+			verbose.NopPrinter,
+			serviceDescriptor.Methods().ByName(protoreflect.Name(methodName)),
+			resolver,
+			false,
+			server.Client(),
+			nil,
+			server.URL+"/foo.bar.LibraryService/"+methodName,
+			output,
+			InvokerWithHTTPTranscoding(server.URL),
+			InvokerWithRPCErrors(),
+		)
+		err := invoker.Invoke(context.Background(), "(argument)", strings.NewReader(data), http.Header{})
+		return output.String(), err
+	}
+
+	output, err := invoke(
+		"GetBook",
+		`{"name": "shelves/1/books/1", "view": "VIEW_FULL", "fields": ["title", "pages"], "filter": {"author": "Frank Herbert", "publishedAfter": "1965-08-01T00:00:00Z"}}`,
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		recordedRequest{
+			method: http.MethodGet,
+			url:    "/v1/shelves/1/books/1?fields=title&fields=pages&filter.author=Frank+Herbert&filter.published_after=1965-08-01T00%3A00%3A00Z&view=VIEW_FULL",
+		},
+		recorded,
+	)
+	assert.JSONEq(t, `{"name": "shelves/1/books/1", "title": "Dune", "pages": 412}`, output)
+
+	_, err = invoke("CreateBook", `{"parent": "shelves/1", "book": {"title": "Dune"}, "requestId": "abc"}`)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		recordedRequest{
+			method:      http.MethodPost,
+			url:         "/v1/shelves/1/books?request_id=abc",
+			contentType: "application/json",
+			body:        `{"title":"Dune"}`,
+		},
+		recorded,
+	)
+
+	_, err = invoke("UpdateBook", `{"book": {"name": "shelves/1/books/1", "pages": 412}, "validateOnly": true}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, recorded.method)
+	assert.Equal(t, "/v1/shelves/1/books/1", recorded.url)
+	assert.JSONEq(t, `{"book": {"pages": 412}, "validateOnly": true}`, recorded.body)
+
+	// A single segment variable escapes slashes.
+	output, err = invoke("ListBooks", `{"parent": "shelves/1", "pageSize": 2}`)
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/shelves%2F1/books?page_size=2", recorded.url)
+	assert.JSONEq(t, `{"books": [{"name": "shelves/1/books/1"}, {"name": "shelves/1/books/2"}]}`, output)
+
+	_, err = invoke("GetBook", `{"name": "shelves/1/books/missing"}`)
+	connectErr := &connect.Error{}
+	require.True(t, errors.As(err, &connectErr))
+	assert.Equal(t, connect.CodeNotFound, connectErr.Code())
+	assert.Equal(t, "book not found", connectErr.Message())
+
+	_, err = invoke("GetBook", `{"name": "shelves/1/books/teapot"}`)
+	require.True(t, errors.As(err, &connectErr))
+	assert.Equal(t, connect.CodeUnknown, connectErr.Code())
+
+	_, err = invoke("GetBook", `{}`)
+	require.EqualError(t, err, `field "name" bound to the path must be set`)
+}
+
+func TestHTTPTranscodingNoRule(t *testing.T) {
+	t.Parallel()
+	serviceDescriptor, resolver := newTestLibraryService(t, nil)
+	nameContainer, err := appext.NewNameContainer(app.NewContainer(nil, nil, io.Discard, io.Discard), "buf")
+	require.NoError(t, err)
+	invoker := NewInvoker(
+		appext.NewContainer(nameContainer, nil),
+		verbose.NopPrinter,
+		serviceDescriptor.Methods().ByName("GetBook"),
+		resolver,
+		false,
+		http.DefaultClient,
+		nil,
+		"http://localhost/foo.bar.LibraryService/GetBook",
+		io.Discard,
+		InvokerWithHTTPTranscoding("http://localhost"),
+	)
+	err = invoker.Invoke(context.Background(), "(argument)", nil, http.Header{})
+	require.EqualError(t, err, "method foo.bar.LibraryService.GetBook has no google.api.http option, so it cannot be invoked with HTTP transcoding")
+}
+
+// newTestLibraryService returns the LibraryService of testdata/library.proto,
+// with the given google.api.http options set on its methods.
+func newTestLibraryService(
+	t *testing.T,
+	methodNameToHTTPRule map[string]*annotations.HttpRule,
+) (protoreflect.ServiceDescriptor, protoencoding.Resolver) {
+	descriptors, err := (&protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{"./testdata"},
+		}),
+	}).Compile(context.Background(), "library.proto")
+	require.NoError(t, err)
+	fileDescriptorProto := protodesc.ToFileDescriptorProto(descriptors[0])
+	for _, methodDescriptorProto := range fileDescriptorProto.GetService()[0].GetMethod() {
+		if rule, ok := methodNameToHTTPRule[methodDescriptorProto.GetName()]; ok {
+			methodDescriptorProto.Options = &descriptorpb.MethodOptions{}
+			proto.SetExtension(methodDescriptorProto.Options, annotations.E_Http, rule)
+		}
+	}
+	fileDescriptor, err := protodesc.NewFile(fileDescriptorProto, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	resolver, err := protoencoding.NewResolver(
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		fileDescriptorProto,
+	)
+	require.NoError(t, err)
+	return fileDescriptor.Services().ByName("LibraryService"), resolver
+}
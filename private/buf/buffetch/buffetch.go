@@ -21,6 +21,8 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 
 	"buf.build/go/app"
 	"github.com/bufbuild/buf/private/buf/buffetch/internal"
@@ -89,6 +91,17 @@ var (
 // MessageEncoding is the encoding of the message.
 type MessageEncoding int
 
+// String implements fmt.Stringer.
+//
+// This returns the format of the MessageEncoding, such as "binpb" or "json".
+func (m MessageEncoding) String() string {
+	format, ok := messageEncodingToFormat[m]
+	if !ok {
+		return strconv.Itoa(int(m))
+	}
+	return format
+}
+
 // Ref is an message file or source bucket reference.
 type Ref interface {
 	internalRef() internal.Ref
@@ -240,6 +253,17 @@ type RefParser interface {
 	GetRefForInputConfig(ctx context.Context, inputConfig bufconfig.InputConfig) (Ref, error)
 }
 
+// ParseMessageEncoding parses the MessageEncoding from a message format, such as
+// "binpb" or "json".
+//
+// This does not accept deprecated formats.
+func ParseMessageEncoding(format string) (MessageEncoding, error) {
+	if !slices.Contains(messageFormatsNotDeprecated, format) {
+		return 0, fmt.Errorf("invalid format for message: %q", format)
+	}
+	return parseMessageEncoding(format)
+}
+
 // NewRefParser returns a new RefParser.
 //
 // This defaults to dir or module.
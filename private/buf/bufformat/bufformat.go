@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/pkg/storage"
@@ -85,3 +87,81 @@ func FormatFileNode(dest io.Writer, fileNode *ast.FileNode) error {
 	formatter := newFormatter(dest, fileNode)
 	return formatter.Run()
 }
+
+// Edit is an edit to the source of a file: it replaces the bytes in [Start, End)
+// with NewText.
+type Edit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// FormatFileNodeRange formats the parts of the given file node that overlap the range
+// [start, end] of byte offsets into its source, and returns the edits that apply that
+// formatting to the source, in order.
+//
+// Formatting is done at the granularity of top-level declarations: each message, enum,
+// extend and service that overlaps the range is formatted in its entirety. The file header
+// (syntax, package, imports and options) is formatted as a single unit, since formatting
+// sorts it; if it is interleaved with other declarations, it is not formatted at all.
+// Everything else, including the whitespace between declarations, is left untouched.
+//
+// Edits that do not change anything are not filtered out.
+func FormatFileNodeRange(fileNode *ast.FileNode, start int, end int) ([]Edit, error) {
+	var out strings.Builder
+	formatter := newFormatter(&out, fileNode)
+	if err := formatter.Run(); err != nil {
+		return nil, err
+	}
+	formatted := out.String()
+
+	// The header may only be edited if it is contiguous in the source.
+	headerIsContiguous := true
+	var seenType bool
+	for _, decl := range fileNode.Decls {
+		switch decl.(type) {
+		case *ast.PackageNode, *ast.ImportNode, *ast.OptionNode:
+			headerIsContiguous = headerIsContiguous && !seenType
+		case *ast.EmptyDeclNode:
+		default:
+			seenType = true
+		}
+	}
+
+	var edits []Edit
+	for _, span := range formatter.spans {
+		if span.isHeader && !headerIsContiguous {
+			continue
+		}
+
+		// Find the span of the source these nodes came from, including their comments.
+		sourceStart, sourceEnd := -1, -1
+		for _, node := range span.nodes {
+			// NOTE: The offsets of end positions are those of the last character,
+			// even though their columns are exclusive.
+			info := fileNode.NodeInfo(node)
+			nodeStart, nodeEnd := info.Start().Offset, info.End().Offset+1
+			if comments := info.LeadingComments(); comments.Len() > 0 {
+				nodeStart = min(nodeStart, comments.Index(0).Start().Offset)
+			}
+			if comments := info.TrailingComments(); comments.Len() > 0 {
+				nodeEnd = max(nodeEnd, comments.Index(comments.Len()-1).End().Offset+1)
+			}
+			if sourceStart < 0 || nodeStart < sourceStart {
+				sourceStart = nodeStart
+			}
+			sourceEnd = max(sourceEnd, nodeEnd)
+		}
+		if sourceStart < 0 || sourceStart > end || sourceEnd < start {
+			continue
+		}
+
+		edits = append(edits, Edit{
+			Start:   sourceStart,
+			End:     sourceEnd,
+			NewText: strings.TrimSpace(formatted[span.start:span.end]),
+		})
+	}
+	slices.SortFunc(edits, func(a, b Edit) int { return a.Start - b.Start })
+	return edits, nil
+}
@@ -68,6 +68,21 @@ type formatter struct {
 	// Records all errors that occur during the formatting process. Nearly any
 	// non-nil error represents a bug in the implementation.
 	err error
+
+	// The number of bytes written to writer so far.
+	written int
+	// The spans of the output that the file's header and each of its top-level
+	// types were written to, in order. These are used for range formatting.
+	spans []outputSpan
+}
+
+// outputSpan is a span of the formatter's output that some top-level nodes were
+// written to.
+type outputSpan struct {
+	nodes      []ast.Node
+	start, end int
+	// Whether this is the span of the file header.
+	isHeader bool
 }
 
 // newFormatter returns a new formatter for the given file.
@@ -182,6 +197,7 @@ func (f *formatter) WriteString(elem string) {
 				f.err = errors.Join(f.err, err)
 				return
 			}
+			f.written++
 		}
 	}
 	if len(elem) == 0 {
@@ -191,6 +207,7 @@ func (f *formatter) WriteString(elem string) {
 	if _, err := f.writer.Write([]byte(elem)); err != nil {
 		f.err = errors.Join(f.err, err)
 	}
+	f.written += len(elem)
 }
 
 // SetPreviousNode sets the previously written node. This should
@@ -234,6 +251,7 @@ func (f *formatter) writeFileHeader() {
 		packageNode *ast.PackageNode
 		importNodes []*ast.ImportNode
 		optionNodes []*ast.OptionNode
+		headerNodes []ast.Node
 	)
 	for _, fileElement := range f.fileNode.Decls {
 		switch node := fileElement.(type) {
@@ -246,12 +264,22 @@ func (f *formatter) writeFileHeader() {
 		default:
 			continue
 		}
+		headerNodes = append(headerNodes, fileElement)
 	}
 	if f.fileNode.Syntax == nil && f.fileNode.Edition == nil &&
 		packageNode == nil && importNodes == nil && optionNodes == nil {
 		// There aren't any header values, so we can return early.
 		return
 	}
+	if f.fileNode.Edition != nil {
+		headerNodes = append([]ast.Node{f.fileNode.Edition}, headerNodes...)
+	} else if f.fileNode.Syntax != nil {
+		headerNodes = append([]ast.Node{f.fileNode.Syntax}, headerNodes...)
+	}
+	start := f.written
+	defer func() {
+		f.spans = append(f.spans, outputSpan{nodes: headerNodes, start: start, end: f.written, isHeader: true})
+	}()
 	editionNode := f.fileNode.Edition
 	if editionNode != nil {
 		f.writeEdition(editionNode)
@@ -338,7 +366,9 @@ func (f *formatter) writeFileTypes() {
 			if wantNewline && !f.leadingCommentsContainBlankLine(node) {
 				f.P("")
 			}
+			start := f.written
 			f.writeNode(node)
+			f.spans = append(f.spans, outputSpan{nodes: []ast.Node{node}, start: start, end: f.written})
 		}
 	}
 }
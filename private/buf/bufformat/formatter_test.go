@@ -17,6 +17,9 @@ package bufformat
 import (
 	"context"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -25,6 +28,8 @@ import (
 	"github.com/bufbuild/buf/private/pkg/slogtestext"
 	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,3 +115,49 @@ func testFormatNoDiff(t *testing.T, path string) {
 		)
 	})
 }
+
+func TestFormatFileNodeRange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial", func(t *testing.T) {
+		t.Parallel()
+		const source = `syntax   =   "proto3";
+
+message Foo{string bar=1;}
+
+// Baz is hand-tuned.
+message   Baz{int32 qux=1;}
+`
+		fileNode, err := parser.Parse("test.proto", strings.NewReader(source), reporter.NewHandler(nil))
+		require.NoError(t, err)
+		start := strings.Index(source, "bar")
+		edits, err := FormatFileNodeRange(fileNode, start, start)
+		require.NoError(t, err)
+		require.Equal(t, []Edit{{
+			Start:   strings.Index(source, "message Foo"),
+			End:     strings.Index(source, "1;}") + len("1;}"),
+			NewText: "message Foo {\n  string bar = 1;\n}",
+		}}, edits)
+	})
+
+	t.Run("golden", func(t *testing.T) {
+		t.Parallel()
+		// Formatted files must not be changed by range formatting either.
+		require.NoError(t, filepath.WalkDir("testdata", func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || !strings.HasSuffix(path, ".golden.proto") {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			source := string(data)
+			fileNode, err := parser.Parse(path, strings.NewReader(source), reporter.NewHandler(nil))
+			require.NoError(t, err)
+			edits, err := FormatFileNodeRange(fileNode, 0, len(source))
+			require.NoError(t, err)
+			for _, edit := range edits {
+				require.Equal(t, source[edit.Start:edit.End], edit.NewText, path)
+			}
+			return nil
+		}))
+	})
+}
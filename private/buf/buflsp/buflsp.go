@@ -19,6 +19,7 @@ package buflsp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -131,7 +132,8 @@ func (l *lsp) init(_ context.Context, params *protocol.InitializeParams) error {
 // newHandler constructs an RPC handler that wraps the default one from jsonrpc2. This allows us
 // to inject debug logging, tracing, and timeouts to requests.
 func (l *lsp) newHandler() jsonrpc2.Handler {
-	actual := protocol.ServerHandler(newServer(l), nil)
+	s := newServer(l)
+	actual := handleDidChange(s, protocol.ServerHandler(s, nil))
 	return jsonrpc2.AsyncHandler(func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
 		l.logger.Debug(
 			"handling request",
@@ -167,3 +169,21 @@ func (l *lsp) newHandler() jsonrpc2.Handler {
 		return nil
 	})
 }
+
+// handleDidChange wraps a handler to decode the textDocument/didChange notifications
+// itself, and pass them to server.didChange.
+//
+// The protocol package decodes the changes into protocol.TextDocumentContentChangeEvent,
+// which loses whether a change has a range, see contentChangeEvent.
+func handleDidChange(s *server, handler jsonrpc2.Handler) jsonrpc2.Handler {
+	return func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		if req.Method() != protocol.MethodTextDocumentDidChange {
+			return handler(ctx, reply, req)
+		}
+		var params didChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params(), &params); err != nil {
+			return reply(ctx, nil, fmt.Errorf("%w: %w", jsonrpc2.ErrParse, err))
+		}
+		return reply(ctx, nil, s.didChange(ctx, &params))
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"buf.build/go/app"
+	"buf.build/go/app/appext"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/jsonrpc2"
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+	"go.uber.org/zap"
+)
+
+// newTestServer serves a language server for tests, and returns an initialized client
+// for it.
+func newTestServer(t *testing.T) protocol.Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cacheDirPath := t.TempDir()
+	nameContainer, err := appext.NewNameContainer(
+		app.NewContainer(
+			map[string]string{
+				"BUF_CACHE_DIR": cacheDirPath,
+				"HOME":          cacheDirPath,
+			},
+			nil,
+			nil,
+			nil,
+		),
+		"buf",
+	)
+	require.NoError(t, err)
+	container := appext.NewContainer(nameContainer, slogtestext.NewLogger(t))
+	controller, err := bufcli.NewController(container)
+	require.NoError(t, err)
+	wktStore, err := bufcli.NewWKTStore(container)
+	require.NoError(t, err)
+	wktBucket, err := wktStore.GetBucket(ctx)
+	require.NoError(t, err)
+	wasmRuntime, err := bufcli.NewWasmRuntime(ctx, container)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = wasmRuntime.Close(context.Background()) })
+
+	serverPipe, clientPipe := net.Pipe()
+	serverConn, err := Serve(ctx, wktBucket, container, controller, wasmRuntime, jsonrpc2.NewStream(serverPipe))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverConn.Close() })
+	clientConn := jsonrpc2.NewConn(jsonrpc2.NewStream(clientPipe))
+	// The server's calls to the client, such as for its configuration, fail.
+	clientConn.Go(ctx, jsonrpc2.MethodNotFoundHandler)
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	client := protocol.ServerDispatcher(clientConn, zap.NewNop())
+	_, err = client.Initialize(ctx, &protocol.InitializeParams{})
+	require.NoError(t, err)
+	require.NoError(t, client.Initialized(ctx, &protocol.InitializedParams{}))
+	return client
+}
+
+// openTestFile opens the file at the path in the client, and returns its URI.
+func openTestFile(t *testing.T, client protocol.Server, path string) protocol.URI {
+	path, err := filepath.Abs(path)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	fileURI := uri.File(path)
+	require.NoError(t, client.DidOpen(context.Background(), &protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{
+			URI:        fileURI,
+			LanguageID: "protobuf",
+			Version:    1,
+			Text:       string(data),
+		},
+	}))
+	return fileURI
+}
@@ -0,0 +1,417 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements code actions, i.e. quick fixes for diagnostics.
+
+package buflsp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/pkg/standard/xslices"
+	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
+	"github.com/bufbuild/protocompile/ast"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+const (
+	// commandRename renames a symbol across the workspace, exactly like
+	// textDocument/rename. It takes a single protocol.RenameParams argument.
+	//
+	// This exists so that code actions which rename symbols only need to do the
+	// (expensive) workspace-wide search when they are actually applied.
+	commandRename = "buf.lsp.rename"
+
+	defaultEnumZeroValueSuffix = "_UNSPECIFIED"
+)
+
+// CodeActions returns the code actions available for the given range of this file.
+func (f *file) CodeActions(ctx context.Context, range_ protocol.Range) []protocol.CodeAction {
+	var (
+		actions    []protocol.CodeAction
+		lintConfig = f.lintConfig()
+	)
+	for _, diagnostic := range f.diagnostics {
+		if diagnostic.Source != lintSource || !rangesOverlap(diagnostic.Range, range_) {
+			continue
+		}
+		ruleID, _ := diagnostic.Code.(string)
+
+		switch ruleID {
+		case "ENUM_ZERO_VALUE_SUFFIX", "FIELD_LOWER_SNAKE_CASE", "MESSAGE_PASCAL_CASE":
+			if action := f.renameFix(ctx, diagnostic, ruleID, lintConfig); action != nil {
+				actions = append(actions, *action)
+			}
+		case "IMPORT_USED":
+			if action := f.removeImportFix(diagnostic); action != nil {
+				actions = append(actions, *action)
+			}
+		}
+
+		if ruleID != "" && lintConfig != nil && lintConfig.AllowCommentIgnores() {
+			actions = append(actions, f.ignoreCommentFix(diagnostic, ruleID))
+		}
+	}
+
+	// Missing imports are found from unresolved references, rather than from
+	// diagnostics, since the compiler's diagnostics do not tell us which name
+	// failed to resolve.
+	seen := make(map[string]bool)
+	for _, symbol := range f.symbols {
+		ref, ok := symbol.kind.(*reference)
+		if !ok || ref.file != nil || ref.seeTypeOf != nil || ref.isNonCustomOptionIn != nil {
+			continue
+		}
+		if !rangesOverlap(symbol.Range(), range_) {
+			continue
+		}
+		for _, path := range f.findImportsDefining(ctx, symbol) {
+			if !seen[path] {
+				seen[path] = true
+				actions = append(actions, f.addImportFix(path))
+			}
+		}
+	}
+
+	return actions
+}
+
+// lintConfig returns the lint configuration for this file's module, if known.
+func (f *file) lintConfig() bufconfig.LintConfig {
+	if f.workspace == nil || f.module == nil {
+		return nil
+	}
+	return f.workspace.GetLintConfigForOpaqueID(f.module.OpaqueID())
+}
+
+// renameFix returns a code action that renames the definition flagged by a naming lint,
+// or nil if no fix is possible.
+func (f *file) renameFix(
+	ctx context.Context,
+	diagnostic protocol.Diagnostic,
+	ruleID string,
+	lintConfig bufconfig.LintConfig,
+) *protocol.CodeAction {
+	symbol := f.SymbolAt(ctx, diagnostic.Range.Start)
+	if symbol == nil {
+		return nil
+	}
+	def, ok := symbol.kind.(*definition)
+	if !ok || len(def.path) == 0 || symbol.CheckRenamable(ctx) != nil {
+		return nil
+	}
+
+	name := def.path[len(def.path)-1]
+	var newName string
+	switch ruleID {
+	case "ENUM_ZERO_VALUE_SUFFIX":
+		if len(def.path) < 2 {
+			return nil
+		}
+		suffix := defaultEnumZeroValueSuffix
+		if lintConfig != nil && lintConfig.EnumZeroValueSuffix() != "" {
+			suffix = lintConfig.EnumZeroValueSuffix()
+		}
+		// Name the zero value after its enum, which also satisfies ENUM_VALUE_PREFIX.
+		newName = xstrings.ToUpperSnakeCase(def.path[len(def.path)-2]) + suffix
+		// Enum values may be referred to by option values, such as field defaults, which
+		// are not indexed as symbols, so renaming would leave those references dangling.
+		if f.isUsedInOptionValues(ctx, name) {
+			return nil
+		}
+	case "FIELD_LOWER_SNAKE_CASE":
+		newName = xstrings.ToLowerSnakeCase(name)
+	case "MESSAGE_PASCAL_CASE":
+		newName = xstrings.ToPascalCase(name)
+	}
+	if newName == "" || newName == name {
+		return nil
+	}
+
+	title := fmt.Sprintf("Rename %s to %s", name, newName)
+	return &protocol.CodeAction{
+		Title:       title,
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diagnostic},
+		IsPreferred: true,
+		Command: &protocol.Command{
+			Title:   title,
+			Command: commandRename,
+			Arguments: []any{protocol.RenameParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: f.uri},
+					Position:     diagnostic.Range.Start,
+				},
+				NewName: newName,
+			}},
+		},
+	}
+}
+
+// isUsedInOptionValues returns whether an option value in this file, or in any other
+// local file of its workspace, refers to an enum value with the given name.
+//
+// Option values refer to enum values by their simple name, so this may also report
+// values of other enums that have the same name.
+func (f *file) isUsedInOptionValues(ctx context.Context, name string) bool {
+	fileNodes := []*ast.FileNode{f.fileNode}
+	for _, path := range xslices.MapKeysToSortedSlice(f.importablePathToObject) {
+		fileInfo := f.importablePathToObject[path]
+		// Files from dependencies cannot import a local file.
+		if fileInfo.LocalPath() == f.uri.Filename() || fileInfo.LocalPath() != fileInfo.ExternalPath() {
+			continue
+		}
+		if fileNode := f.parseImportable(ctx, fileInfo.LocalPath()); fileNode != nil {
+			fileNodes = append(fileNodes, fileNode)
+		}
+	}
+
+	var used bool
+	for _, fileNode := range fileNodes {
+		_ = ast.Walk(fileNode, ast.NoOpVisitor{}, ast.WithBefore(func(node ast.Node) error {
+			if option, ok := node.(*ast.OptionNode); ok && valueRefersTo(option.Val, name) {
+				used = true
+			}
+			return nil
+		}))
+	}
+	return used
+}
+
+// valueRefersTo returns whether the option value contains an identifier with the given
+// name, including within message and array literals.
+func valueRefersTo(value ast.ValueNode, name string) bool {
+	switch value := value.(type) {
+	case *ast.IdentNode:
+		return value.Val == name
+	case *ast.ArrayLiteralNode:
+		return slices.ContainsFunc(value.Elements, func(element ast.ValueNode) bool {
+			return valueRefersTo(element, name)
+		})
+	case *ast.MessageLiteralNode:
+		return slices.ContainsFunc(value.Elements, func(field *ast.MessageFieldNode) bool {
+			return valueRefersTo(field.Val, name)
+		})
+	}
+	return false
+}
+
+// removeImportFix returns a code action that deletes an unused import, or nil if the
+// import cannot be found.
+func (f *file) removeImportFix(diagnostic protocol.Diagnostic) *protocol.CodeAction {
+	if f.fileNode == nil {
+		return nil
+	}
+
+	var node *ast.ImportNode
+	for _, decl := range f.fileNode.Decls {
+		importNode, ok := decl.(*ast.ImportNode)
+		if !ok {
+			continue
+		}
+		start := infoToRange(f.fileNode.NodeInfo(importNode)).Start
+		if start.Line != diagnostic.Range.Start.Line {
+			continue
+		}
+		// If there are several imports on the line, the diagnostic starts at its own.
+		if node == nil || start == diagnostic.Range.Start {
+			node = importNode
+		}
+	}
+	if node == nil {
+		return nil
+	}
+
+	// NOTE: The offsets of end positions are those of the last character, even though
+	// their columns are exclusive.
+	info := f.fileNode.NodeInfo(node)
+	start, end := info.Start().Offset, info.End().Offset+1
+
+	// If the import is the only thing on its line, other than a trailing comment,
+	// delete the whole line.
+	lineStart := strings.LastIndexByte(f.text[:start], '\n') + 1
+	lineEnd := len(f.text)
+	if next := strings.IndexByte(f.text[end:], '\n'); next >= 0 {
+		lineEnd = end + next + 1
+	}
+	rest := strings.TrimSpace(f.text[end:lineEnd])
+	if strings.TrimSpace(f.text[lineStart:start]) == "" && (rest == "" || strings.HasPrefix(rest, "//")) {
+		start, end = lineStart, lineEnd
+	}
+	range_ := protocol.Range{
+		Start: offsetToPosition(f.text, start),
+		End:   offsetToPosition(f.text, end),
+	}
+
+	return &protocol.CodeAction{
+		Title:       fmt.Sprintf("Remove unused import %q", node.Name.AsString()),
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diagnostic},
+		IsPreferred: true,
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				f.uri: {{Range: range_}},
+			},
+		},
+	}
+}
+
+// ignoreCommentFix returns a code action that suppresses a lint with a
+// buf:lint:ignore comment on the line above it.
+func (f *file) ignoreCommentFix(diagnostic protocol.Diagnostic, ruleID string) protocol.CodeAction {
+	// The comment is indented like the line it applies to.
+	line := diagnostic.Range.Start.Line
+	lineText := f.text[positionToOffset(f.text, protocol.Position{Line: line}):]
+	if lineEnd := strings.IndexByte(lineText, '\n'); lineEnd >= 0 {
+		lineText = lineText[:lineEnd]
+	}
+	indent := lineText[:len(lineText)-len(strings.TrimLeft(lineText, " \t"))]
+
+	return protocol.CodeAction{
+		Title:       fmt.Sprintf("Suppress %s with a buf:lint:ignore comment", ruleID),
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diagnostic},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				f.uri: {{
+					Range:   protocol.Range{Start: protocol.Position{Line: line}, End: protocol.Position{Line: line}},
+					NewText: indent + "// buf:lint:ignore " + ruleID + "\n",
+				}},
+			},
+		},
+	}
+}
+
+// addImportFix returns a code action that adds an import for the given path.
+func (f *file) addImportFix(path string) protocol.CodeAction {
+	// Insert after the last import. If there are none, start a new block of
+	// imports after the package declaration or the syntax declaration.
+	var (
+		after   ast.Node
+		newText = fmt.Sprintf("import %q;\n", path)
+	)
+	if f.fileNode != nil {
+		switch {
+		case f.fileNode.Syntax != nil:
+			after = f.fileNode.Syntax
+		case f.fileNode.Edition != nil:
+			after = f.fileNode.Edition
+		}
+		for _, decl := range f.fileNode.Decls {
+			switch decl := decl.(type) {
+			case *ast.PackageNode:
+				if _, ok := after.(*ast.ImportNode); !ok {
+					after = decl
+				}
+			case *ast.ImportNode:
+				after = decl
+			}
+		}
+	}
+
+	var at protocol.Position
+	if after == nil {
+		newText += "\n"
+	} else {
+		at = protocol.Position{Line: infoToRange(f.fileNode.NodeInfo(after)).End.Line + 1}
+		if _, ok := after.(*ast.ImportNode); !ok {
+			newText = "\n" + newText
+		}
+	}
+
+	return protocol.CodeAction{
+		Title: fmt.Sprintf("Add import %q", path),
+		Kind:  protocol.QuickFix,
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				f.uri: {{Range: protocol.Range{Start: at, End: at}, NewText: newText}},
+			},
+		},
+	}
+}
+
+// findImportsDefining returns the paths of the files, not yet imported by this file,
+// that define the type named by the given unresolved reference.
+func (f *file) findImportsDefining(ctx context.Context, symbol *symbol) []string {
+	components, absolute := symbol.ReferencePath()
+	if len(components) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for _, path := range xslices.MapKeysToSortedSlice(f.importablePathToObject) {
+		if _, imported := f.importToFile[path]; imported {
+			continue
+		}
+		fileInfo := f.importablePathToObject[path]
+		if fileInfo.LocalPath() == f.uri.Filename() {
+			continue
+		}
+
+		fileNode := f.parseImportable(ctx, fileInfo.LocalPath())
+		if fileNode == nil {
+			continue
+		}
+		var pkg []string
+		for _, decl := range fileNode.Decls {
+			if node, ok := decl.(*ast.PackageNode); ok {
+				pkg = strings.Split(string(node.Name.AsIdentifier()), ".")
+				break
+			}
+		}
+
+		// This mirrors the search in [symbol.ResolveCrossFile].
+		declPath, ok := xslices.TrimPrefix(components, pkg)
+		if !ok && !absolute {
+			declPath, ok = xslices.TrimPrefix(slices.Concat(f.Package(), components), pkg)
+		}
+		if ok && findDeclByPath(fileNode.Decls, declPath) != nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// parseImportable returns the AST of a file this file could import. Files that are
+// already tracked are not reparsed.
+//
+// Returns nil if the file could not be read.
+func (f *file) parseImportable(ctx context.Context, path string) *ast.FileNode {
+	if file := f.Manager().Get(uri.File(path)); file != nil && file.fileNode != nil {
+		return file.fileNode
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		f.lsp.logger.DebugContext(ctx, fmt.Sprintf("could not read %q: %s", path, err))
+		return nil
+	}
+	// Errors are irrelevant here: we only want whatever declarations can be found.
+	fileNode, _ := parser.Parse(path, strings.NewReader(string(data)), reporter.NewHandler(nil))
+	return fileNode
+}
+
+// rangesOverlap returns whether the two ranges overlap, counting ranges that merely
+// touch as overlapping.
+func rangesOverlap(a, b protocol.Range) bool {
+	return comparePositions(a.Start, b.End) <= 0 && comparePositions(b.Start, a.End) <= 0
+}
@@ -0,0 +1,215 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/bufbuild/buf/private/pkg/storage/storageutil"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestRemoveImportFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		start    protocol.Position
+		expected string
+	}{
+		{
+			name:     "line",
+			text:     "syntax = \"proto3\";\n\nimport \"a.proto\";\nimport \"b.proto\";\n",
+			start:    protocol.Position{Line: 2, Character: 0},
+			expected: "syntax = \"proto3\";\n\nimport \"b.proto\";\n",
+		},
+		{
+			name:     "trailing-comment",
+			text:     "syntax = \"proto3\";\n\n  import \"a.proto\"; // é😀\nimport \"b.proto\";\n",
+			start:    protocol.Position{Line: 2, Character: 2},
+			expected: "syntax = \"proto3\";\n\nimport \"b.proto\";\n",
+		},
+		{
+			name: "same-line",
+			// The path of the first import makes the character offsets in UTF-16 code
+			// units differ from the byte offsets. Like the diagnostic of the lint, the
+			// start of the second import is at its column in the AST.
+			text:     "syntax = \"proto3\";\n\nimport \"é😀.proto\"; import \"b.proto\"; // b\n",
+			start:    protocol.Position{Line: 2, Character: 19},
+			expected: "syntax = \"proto3\";\n\nimport \"é😀.proto\";  // b\n",
+		},
+		{
+			name:     "same-line-first",
+			text:     "syntax = \"proto3\";\n\nimport \"é😀.proto\"; import \"b.proto\";\n",
+			start:    protocol.Position{Line: 2, Character: 0},
+			expected: "syntax = \"proto3\";\n\n import \"b.proto\";\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			file := newTestCodeActionFile(t, test.text)
+			file.diagnostics = []protocol.Diagnostic{{
+				Range:  protocol.Range{Start: test.start, End: test.start},
+				Code:   "IMPORT_USED",
+				Source: lintSource,
+			}}
+			actions := file.CodeActions(context.Background(), protocol.Range{Start: test.start, End: test.start})
+			require.Len(t, actions, 1)
+			assert.Equal(t, test.expected, applyCodeAction(t, file, actions[0]))
+		})
+	}
+}
+
+func TestIgnoreCommentFix(t *testing.T) {
+	t.Parallel()
+
+	const text = `syntax = "proto3";
+
+message Foo {
+	/* é😀 */ string fooBar = 1;
+}
+`
+	file := newTestCodeActionFile(t, text)
+	// The diagnostic is at a column that is past the end of the line in UTF-16 code units,
+	// since the tab is expanded in the columns of the AST.
+	start := protocol.Position{Line: 3, Character: 20}
+	action := file.ignoreCommentFix(
+		protocol.Diagnostic{Range: protocol.Range{Start: start, End: start}},
+		"FIELD_LOWER_SNAKE_CASE",
+	)
+	assert.Equal(t, `syntax = "proto3";
+
+message Foo {
+	// buf:lint:ignore FIELD_LOWER_SNAKE_CASE
+	/* é😀 */ string fooBar = 1;
+}
+`, applyCodeAction(t, file, action))
+}
+
+func TestAddImportFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "imports",
+			text:     "syntax = \"proto3\";\n\npackage a; // é😀\n\nimport \"b.proto\";\n",
+			expected: "syntax = \"proto3\";\n\npackage a; // é😀\n\nimport \"b.proto\";\nimport \"c.proto\";\n",
+		},
+		{
+			name:     "package",
+			text:     "syntax = \"proto3\";\n\npackage a; // é😀\n\nmessage Foo {}\n",
+			expected: "syntax = \"proto3\";\n\npackage a; // é😀\n\nimport \"c.proto\";\n\nmessage Foo {}\n",
+		},
+		{
+			name:     "empty",
+			text:     "",
+			expected: "import \"c.proto\";\n\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			file := newTestCodeActionFile(t, test.text)
+			assert.Equal(t, test.expected, applyCodeAction(t, file, file.addImportFix("c.proto")))
+		})
+	}
+}
+
+func TestEnumZeroValueRenameFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		// expected is the new name of the zero value, or empty if the rename must not be offered.
+		expected string
+	}{
+		{
+			name:     "unreferenced",
+			text:     "syntax = \"proto2\";\n\nenum Color {\n  RED = 0;\n}\n",
+			expected: "COLOR_UNSPECIFIED",
+		},
+		{
+			name: "field-default",
+			text: "syntax = \"proto2\";\n\nenum Color {\n  RED = 0;\n}\n\n" +
+				"message Paint {\n  optional Color color = 1 [default = RED];\n}\n",
+		},
+		{
+			name: "message-literal",
+			text: "syntax = \"proto2\";\n\nenum Color {\n  RED = 0;\n}\n\n" +
+				"option (palette) = { colors: [BLUE, RED] };\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			file := newTestCodeActionFile(t, test.text)
+			file.lsp = &lsp{logger: slogtestext.NewLogger(t)}
+			file.objectInfo = storageutil.NewObjectInfo("test.proto", "/test.proto", "/test.proto")
+			walker := newWalker(file)
+			walker.Walk(file.fileNode, file.fileNode)
+			file.symbols = walker.symbols
+			// The diagnostic is on RED.
+			start := protocol.Position{Line: 3, Character: 2}
+			file.diagnostics = []protocol.Diagnostic{{
+				Range:  protocol.Range{Start: start, End: protocol.Position{Line: 3, Character: 5}},
+				Code:   "ENUM_ZERO_VALUE_SUFFIX",
+				Source: lintSource,
+			}}
+			actions := file.CodeActions(context.Background(), protocol.Range{Start: start, End: start})
+			if test.expected == "" {
+				assert.Empty(t, actions)
+				return
+			}
+			require.Len(t, actions, 1)
+			require.NotNil(t, actions[0].Command)
+			assert.Equal(t, commandRename, actions[0].Command.Command)
+			assert.Equal(t, []any{protocol.RenameParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: file.uri},
+					Position:     start,
+				},
+				NewName: test.expected,
+			}}, actions[0].Command.Arguments)
+		})
+	}
+}
+
+func newTestCodeActionFile(t *testing.T, text string) *file {
+	fileNode, err := parser.Parse("test.proto", strings.NewReader(text), reporter.NewHandler(nil))
+	require.NoError(t, err)
+	return &file{uri: uri.File("/test.proto"), text: text, fileNode: fileNode}
+}
+
+// applyCodeAction applies the edits of the code action to the text of the file.
+func applyCodeAction(t *testing.T, file *file, action protocol.CodeAction) string {
+	require.NotNil(t, action.Edit)
+	require.Len(t, action.Edit.Changes, 1)
+	return applyTextEdits(file.text, action.Edit.Changes[file.uri])
+}
@@ -0,0 +1,137 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements code lenses, which summarize how each message is used.
+
+package buflsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/protocompile/ast"
+	"go.lsp.dev/protocol"
+)
+
+// commandShowReferences asks the client to show a list of locations, as if they were
+// the result of a find-all-references request. It takes the URI and position the
+// references were requested at, and the locations to show.
+//
+// This is a client-side command: it is not one of ours, but it is the de-facto
+// standard command for this, originally from VS Code, which other clients implement.
+const commandShowReferences = "editor.action.showReferences"
+
+// messageUsage is a way in which a message can be used by a declaration.
+type messageUsage int
+
+const (
+	usageField messageUsage = iota + 1
+	usageRPC
+)
+
+// CodeLenses returns a code lens for each message defined in this file, which shows how
+// many fields and RPCs among files refer to it.
+//
+// files should be every file in this file's workspace; see [fileManager.IndexWorkspace].
+func (f *file) CodeLenses(files []*file) []protocol.CodeLens {
+	// Gather every use of a message in this file, keyed by the message's path.
+	type useCounts struct {
+		fields, rpcs int
+		locations    []protocol.Location
+	}
+	pathToUses := make(map[string]*useCounts)
+	for _, file := range files {
+		nameToUsage := file.messageUsages()
+		for _, symbol := range file.symbols {
+			ref, ok := symbol.kind.(*reference)
+			if !ok || ref.file == nil || ref.file.uri != f.uri {
+				continue
+			}
+			usage, ok := nameToUsage[symbol.name]
+			if !ok {
+				continue
+			}
+
+			key := strings.Join(ref.path, ".")
+			if pathToUses[key] == nil {
+				pathToUses[key] = &useCounts{}
+			}
+			uses := pathToUses[key]
+			switch usage {
+			case usageField:
+				uses.fields++
+			case usageRPC:
+				uses.rpcs++
+			}
+			uses.locations = append(uses.locations, protocol.Location{URI: file.uri, Range: symbol.Range()})
+		}
+	}
+
+	var lenses []protocol.CodeLens
+	for _, symbol := range f.symbols {
+		def, ok := symbol.kind.(*definition)
+		if !ok {
+			continue
+		}
+		if _, ok := def.node.(*ast.MessageNode); !ok {
+			continue
+		}
+
+		uses := pathToUses[strings.Join(def.path, ".")]
+		if uses == nil {
+			// Avoid sending a JSON null to the client.
+			uses = &useCounts{locations: []protocol.Location{}}
+		}
+		range_ := symbol.Range()
+		lenses = append(lenses, protocol.CodeLens{
+			Range: range_,
+			Command: &protocol.Command{
+				Title:     fmt.Sprintf("%s, %s", pluralize(uses.fields, "field"), pluralize(uses.rpcs, "RPC")),
+				Command:   commandShowReferences,
+				Arguments: []any{f.uri, range_.Start, uses.locations},
+			},
+		})
+	}
+	return lenses
+}
+
+// messageUsages returns the name nodes of the types of this file's fields and RPCs,
+// mapped to how they use that type.
+func (f *file) messageUsages() map[ast.Node]messageUsage {
+	usages := make(map[ast.Node]messageUsage)
+	for _, symbol := range f.symbols {
+		def, ok := symbol.kind.(*definition)
+		if !ok {
+			continue
+		}
+		switch node := def.node.(type) {
+		case *ast.FieldNode:
+			usages[node.FldType] = usageField
+		case *ast.MapFieldNode:
+			usages[node.MapType.ValueType] = usageField
+		case *ast.RPCNode:
+			usages[node.Input.MessageType] = usageRPC
+			usages[node.Output.MessageType] = usageRPC
+		}
+	}
+	return usages
+}
+
+// pluralize formats a count of things with the given singular noun.
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestCodeLenses(t *testing.T) {
+	t.Parallel()
+
+	const text = `syntax = "proto3";
+
+message Foo {
+  Bar bar = 1;
+  map<string, Bar> bars = 2;
+}
+
+message Bar {}
+
+service FooService {
+  rpc GetFoo(Bar) returns (Foo);
+}
+`
+	fileNode, err := parser.Parse("test.proto", strings.NewReader(text), reporter.NewHandler(nil))
+	require.NoError(t, err)
+	protoFile := &file{uri: uri.File("/test.proto"), text: text, fileNode: fileNode}
+	walker := newWalker(protoFile)
+	walker.Walk(fileNode, fileNode)
+	protoFile.symbols = walker.symbols
+
+	lenses := protoFile.CodeLenses([]*file{protoFile})
+	require.Len(t, lenses, 2)
+	assert.Equal(t, "0 fields, 1 RPC", lenses[0].Command.Title)
+	assert.Equal(t, "2 fields, 1 RPC", lenses[1].Command.Title)
+}
@@ -0,0 +1,523 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements context-aware completion.
+//
+// Completion is usually requested while the user is in the middle of typing, at which
+// point the file rarely parses. Hence, the context of the cursor is recovered with a
+// lightweight scan of the text preceding it, rather than from the AST.
+
+package buflsp
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/standard/xslices"
+	"github.com/bufbuild/protocompile/ast"
+	"go.lsp.dev/protocol"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Scopes that a completionContext can be in. These are named after the keyword that
+// opens the corresponding block.
+const (
+	scopeFile    = ""
+	scopeMessage = "message"
+	scopeEnum    = "enum"
+	scopeService = "service"
+	scopeOneof   = "oneof"
+	scopeExtend  = "extend"
+	scopeRPC     = "rpc"
+	// scopeLiteral is the body of a message literal, such as an option value.
+	scopeLiteral = "literal"
+)
+
+// scalarTypes is every scalar type name, in the order they are offered for completion.
+var scalarTypes = []string{
+	"double", "float", "int32", "int64", "uint32", "uint64",
+	"sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64",
+	"bool", "string", "bytes",
+}
+
+// mapKeyTypes is every type that is permitted as the key of a map field.
+var mapKeyTypes = []string{
+	"int32", "int64", "uint32", "uint64", "sint32", "sint64",
+	"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string",
+}
+
+// scopeToOptionsMessage maps the scope an option appears in to the name of the options
+// message in descriptor.proto that defines it.
+var scopeToOptionsMessage = map[string]string{
+	scopeFile:    "FileOptions",
+	scopeMessage: "MessageOptions",
+	scopeEnum:    "EnumOptions",
+	scopeService: "ServiceOptions",
+	scopeOneof:   "OneofOptions",
+	scopeRPC:     "MethodOptions",
+}
+
+// completionContext describes what surrounds the cursor, for the purposes of completion.
+type completionContext struct {
+	// The innermost block containing the cursor; one of the scope* constants.
+	scope string
+	// The complete words of the statement containing the cursor, up to the cursor.
+	// Punctuation is included as single-character words.
+	words []string
+	// The partial word immediately before the cursor, if any.
+	prefix string
+
+	// Whether the cursor is inside a string literal. If so, prefix contains the
+	// contents of the string up to the cursor.
+	inString bool
+	// The number of unclosed (, [ and < within the current statement.
+	parens, brackets, angles int
+	// Whether the cursor is where the name of an option would go: after the option
+	// keyword or after [ or , in a compact option list, but before the =.
+	wantOptionName bool
+}
+
+// newCompletionContext scans text, which is the text of a file up to the cursor, and
+// recovers the context the cursor is in.
+func newCompletionContext(text string) completionContext {
+	var (
+		cc        completionContext
+		stack     []string
+		startWord = -1
+	)
+	endWord := func(end int) {
+		if startWord >= 0 {
+			cc.words = append(cc.words, text[startWord:end])
+			startWord = -1
+		}
+	}
+	endStatement := func() {
+		cc.words = nil
+		cc.parens, cc.brackets, cc.angles = 0, 0, 0
+		cc.wantOptionName = false
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if isCompletionWordByte(c) {
+			if startWord < 0 {
+				startWord = i
+			}
+			continue
+		}
+		endWord(i)
+
+		switch c {
+		case '/':
+			if strings.HasPrefix(text[i:], "//") {
+				end := strings.IndexByte(text[i:], '\n')
+				if end < 0 {
+					// The cursor is inside a comment.
+					return completionContext{scope: scopeLiteral}
+				}
+				i += end
+			} else if strings.HasPrefix(text[i:], "/*") {
+				end := strings.Index(text[i+2:], "*/")
+				if end < 0 {
+					return completionContext{scope: scopeLiteral}
+				}
+				i += end + 3
+			}
+		case '"', '\'':
+			end := strings.IndexByte(text[i+1:], c)
+			if end < 0 {
+				cc.inString = true
+				cc.prefix = text[i+1:]
+				if len(stack) > 0 {
+					cc.scope = stack[len(stack)-1]
+				}
+				return cc
+			}
+			cc.words = append(cc.words, text[i:i+end+2])
+			i += end + 1
+		case ';':
+			endStatement()
+		case '{':
+			scope := scopeLiteral
+			if len(stack) == 0 || stack[len(stack)-1] != scopeLiteral {
+				switch {
+				case len(cc.words) == 0:
+				case slices.Contains(cc.words, "group"):
+					// Groups are messages, as far as their bodies are concerned.
+					scope = scopeMessage
+				case cc.words[0] == scopeMessage, cc.words[0] == scopeEnum,
+					cc.words[0] == scopeService, cc.words[0] == scopeOneof,
+					cc.words[0] == scopeExtend, cc.words[0] == scopeRPC:
+					scope = cc.words[0]
+				}
+			}
+			stack = append(stack, scope)
+			endStatement()
+		case '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			endStatement()
+		case '(':
+			cc.parens++
+			cc.words = append(cc.words, "(")
+		case ')':
+			cc.parens--
+			cc.words = append(cc.words, ")")
+		case '[':
+			cc.brackets++
+			cc.wantOptionName = true
+			cc.words = append(cc.words, "[")
+		case ']':
+			cc.brackets--
+			cc.wantOptionName = false
+			cc.words = append(cc.words, "]")
+		case '<':
+			cc.angles++
+			cc.words = append(cc.words, "<")
+		case '>':
+			cc.angles--
+			cc.words = append(cc.words, ">")
+		case ',':
+			if cc.brackets > 0 && cc.angles == 0 {
+				cc.wantOptionName = true
+			}
+			cc.words = append(cc.words, ",")
+		case '=':
+			cc.wantOptionName = false
+			cc.words = append(cc.words, "=")
+		}
+
+		if len(cc.words) == 1 && cc.words[0] == "option" {
+			cc.wantOptionName = true
+		}
+	}
+
+	if startWord >= 0 {
+		cc.prefix = text[startWord:]
+	}
+	if len(cc.words) == 1 && cc.words[0] == "option" {
+		cc.wantOptionName = true
+	}
+	if len(stack) > 0 {
+		cc.scope = stack[len(stack)-1]
+	}
+	return cc
+}
+
+// isCompletionWordByte returns whether c can appear in a (possibly qualified) name.
+func isCompletionWordByte(c byte) bool {
+	return c == '_' || c == '.' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// Completions returns the completion items for the given cursor position in this file.
+func (f *file) Completions(ctx context.Context, cursor protocol.Position) []protocol.CompletionItem {
+	offset := positionToOffset(f.text, cursor)
+	cc := newCompletionContext(f.text[:offset])
+	if cc.scope == scopeLiteral {
+		return nil
+	}
+
+	// The range of text that a completion replaces: the partially typed word.
+	start := offset - len(cc.prefix)
+	if cc.wantOptionName && !cc.inString && start > 0 && f.text[start-1] == '(' {
+		// Custom option names are completed along with their opening paren.
+		start--
+	}
+	replace := protocol.Range{Start: offsetToPosition(f.text, start), End: cursor}
+
+	var items []protocol.CompletionItem
+	switch {
+	case cc.inString:
+		if len(cc.words) > 0 && cc.words[0] == "import" {
+			items = f.importCompletions(cc.prefix)
+		}
+		return items
+
+	case cc.wantOptionName:
+		options := scopeToOptionsMessage[cc.scope]
+		if cc.brackets > 0 {
+			// This is a compact option list, which is only permitted on fields and
+			// enum values.
+			options = "FieldOptions"
+			if cc.scope == scopeEnum {
+				options = "EnumValueOptions"
+			}
+		}
+		if options != "" {
+			items = f.optionCompletions(options)
+		}
+
+	case len(cc.words) > 0 && cc.words[0] == "import":
+		if len(cc.words) == 1 {
+			items = keywordCompletions("public", "weak")
+		}
+
+	case len(cc.words) > 0 && cc.words[0] == "rpc":
+		if cc.parens > 0 {
+			if cc.words[len(cc.words)-1] == "(" {
+				items = keywordCompletions("stream")
+			}
+			items = append(items, f.typeCompletions(true)...)
+		} else if cc.words[len(cc.words)-1] == ")" {
+			items = keywordCompletions("returns")
+		}
+
+	case cc.angles > 0:
+		// This is a map type. Only the key and the value are completed.
+		if cc.words[len(cc.words)-1] == "<" {
+			items = keywordCompletions(mapKeyTypes...)
+		} else if cc.words[len(cc.words)-1] == "," {
+			items = append(keywordCompletions(scalarTypes...), f.typeCompletions(false)...)
+		}
+
+	case len(cc.words) == 0:
+		items = keywordCompletions(f.keywordsForScope(cc.scope)...)
+		if cc.scope == scopeMessage || cc.scope == scopeOneof || cc.scope == scopeExtend {
+			items = append(items, keywordCompletions(scalarTypes...)...)
+			items = append(items, f.typeCompletions(false)...)
+		}
+
+	case len(cc.words) == 1 && (cc.scope == scopeMessage || cc.scope == scopeExtend):
+		switch cc.words[0] {
+		case "optional", "repeated", "required":
+			if f.syntax() == "proto2" {
+				items = keywordCompletions("group")
+			}
+			items = append(items, keywordCompletions(scalarTypes...)...)
+			items = append(items, f.typeCompletions(false)...)
+		}
+	}
+
+	for i := range items {
+		items[i].TextEdit = &protocol.TextEdit{Range: replace, NewText: items[i].Label}
+	}
+	return items
+}
+
+// syntax returns the syntax of this file: "proto2", "proto3" or "editions".
+func (f *file) syntax() string {
+	switch {
+	case f.fileNode == nil:
+	case f.fileNode.Edition != nil:
+		return "editions"
+	case f.fileNode.Syntax != nil:
+		return f.fileNode.Syntax.Syntax.AsString()
+	}
+	return "proto2"
+}
+
+// keywordsForScope returns the keywords that may begin a declaration in the given scope,
+// for this file's syntax.
+func (f *file) keywordsForScope(scope string) []string {
+	syntax := f.syntax()
+	switch scope {
+	case scopeFile:
+		keywords := []string{"package", "import", "option", "message", "enum", "service", "extend"}
+		if f.fileNode == nil || (f.fileNode.Syntax == nil && f.fileNode.Edition == nil) {
+			keywords = append([]string{"syntax", "edition"}, keywords...)
+		}
+		return keywords
+	case scopeMessage:
+		keywords := []string{"message", "enum", "oneof", "map", "reserved", "extend", "option", "repeated"}
+		switch syntax {
+		case "proto2":
+			keywords = append(keywords, "optional", "required", "group", "extensions")
+		case "proto3":
+			keywords = append(keywords, "optional")
+		case "editions":
+			keywords = append(keywords, "extensions")
+		}
+		return keywords
+	case scopeExtend:
+		keywords := []string{"repeated"}
+		switch syntax {
+		case "proto2":
+			keywords = append(keywords, "optional", "required", "group")
+		case "proto3":
+			keywords = append(keywords, "optional")
+		}
+		return keywords
+	case scopeOneof:
+		if syntax == "proto2" {
+			return []string{"option", "group"}
+		}
+		return []string{"option"}
+	case scopeEnum:
+		return []string{"option", "reserved"}
+	case scopeService:
+		return []string{"rpc", "option"}
+	case scopeRPC:
+		return []string{"option"}
+	}
+	return nil
+}
+
+// keywordCompletions returns a completion item for each of the given keywords.
+func keywordCompletions(keywords ...string) []protocol.CompletionItem {
+	return xslices.Map(keywords, func(keyword string) protocol.CompletionItem {
+		return protocol.CompletionItem{
+			Label: keyword,
+			Kind:  protocol.CompletionItemKindKeyword,
+		}
+	})
+}
+
+// typeCompletions returns a completion item for every message and enum that is visible
+// from this file, i.e. those defined in this file and in the files it imports, directly
+// or through public imports.
+//
+// If onlyMessages is set, enums are skipped.
+func (f *file) typeCompletions(onlyMessages bool) []protocol.CompletionItem {
+	files := []*file{f}
+	for _, path := range xslices.MapKeysToSortedSlice(f.importToFile) {
+		if imported := f.importToFile[path]; imported != f {
+			files = append(files, imported)
+		}
+	}
+
+	var items []protocol.CompletionItem
+	seen := make(map[string]bool)
+	for _, file := range files {
+		pkg := strings.Join(file.Package(), ".")
+		for _, symbol := range file.symbols {
+			def, ok := symbol.kind.(*definition)
+			if !ok {
+				continue
+			}
+
+			var what string
+			kind := protocol.CompletionItemKindStruct
+			switch def.node.(type) {
+			case *ast.MessageNode:
+				what = "message"
+			case *ast.EnumNode:
+				if onlyMessages {
+					continue
+				}
+				what = "enum"
+				kind = protocol.CompletionItemKindEnum
+			default:
+				continue
+			}
+
+			// Types in our own package can be named without qualification.
+			name := strings.Join(def.path, ".")
+			fullName := name
+			if pkg != "" {
+				fullName = pkg + "." + name
+			}
+			label := fullName
+			if slices.Equal(file.Package(), f.Package()) {
+				label = name
+			}
+
+			if seen[label] {
+				continue
+			}
+			seen[label] = true
+			items = append(items, protocol.CompletionItem{
+				Label:  label,
+				Kind:   kind,
+				Detail: what + " " + fullName,
+			})
+		}
+	}
+	return items
+}
+
+// importCompletions returns a completion item for every file that this file could
+// import, whose path starts with prefix.
+func (f *file) importCompletions(prefix string) []protocol.CompletionItem {
+	var items []protocol.CompletionItem
+	for _, path := range xslices.MapKeysToSortedSlice(f.importablePathToObject) {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if f.objectInfo != nil && path == f.objectInfo.Path() {
+			// A file cannot import itself.
+			continue
+		}
+		items = append(items, protocol.CompletionItem{
+			Label: path,
+			Kind:  protocol.CompletionItemKindFile,
+		})
+	}
+	return items
+}
+
+// optionCompletions returns a completion item for every option that can be set on the
+// given options message from descriptor.proto: both the built-in options and the custom
+// options visible from this file.
+func (f *file) optionCompletions(options string) []protocol.CompletionItem {
+	var items []protocol.CompletionItem
+
+	// Built-in options are the fields of the options message.
+	if descriptorProto := f.importToFile[descriptorPath]; descriptorProto != nil {
+		for _, symbol := range descriptorProto.symbols {
+			def, ok := symbol.kind.(*definition)
+			if !ok || len(def.path) != 2 || def.path[0] != options {
+				continue
+			}
+			if _, ok := def.node.(*ast.FieldNode); !ok || def.path[1] == "uninterpreted_option" {
+				continue
+			}
+			items = append(items, protocol.CompletionItem{
+				Label:  def.path[1],
+				Kind:   protocol.CompletionItemKindProperty,
+				Detail: "google.protobuf." + options,
+			})
+		}
+	}
+
+	// Custom options are extensions of the options message. We use the image for
+	// these, since it has already resolved every extendee for us.
+	if f.image == nil {
+		return items
+	}
+	extendee := ".google.protobuf." + options
+	for _, imageFile := range f.image.Files() {
+		if _, imported := f.importToFile[imageFile.Path()]; !imported &&
+			(f.objectInfo == nil || imageFile.Path() != f.objectInfo.Path()) {
+			continue
+		}
+
+		fileProto := imageFile.FileDescriptorProto()
+		var walk func(scope string, extensions []*descriptorpb.FieldDescriptorProto, messages []*descriptorpb.DescriptorProto)
+		walk = func(scope string, extensions []*descriptorpb.FieldDescriptorProto, messages []*descriptorpb.DescriptorProto) {
+			for _, extension := range extensions {
+				if extension.GetExtendee() != extendee {
+					continue
+				}
+				items = append(items, protocol.CompletionItem{
+					Label:  "(" + scope + extension.GetName() + ")",
+					Kind:   protocol.CompletionItemKindProperty,
+					Detail: "extension of google.protobuf." + options,
+				})
+			}
+			for _, message := range messages {
+				walk(scope+message.GetName()+".", message.GetExtension(), message.GetNestedType())
+			}
+		}
+
+		scope := fileProto.GetPackage()
+		if scope != "" {
+			scope += "."
+		}
+		walk(scope, fileProto.GetExtension(), fileProto.GetMessageType())
+	}
+	return items
+}
@@ -0,0 +1,145 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/protocol"
+)
+
+func TestNewCompletionContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected completionContext
+	}{
+		{
+			name:     "top-level",
+			input:    "syntax = \"proto3\";\npack",
+			expected: completionContext{scope: scopeFile, prefix: "pack"},
+		},
+		{
+			name:  "field-type",
+			input: "message Foo {\n  repeated foo.v1.Ba",
+			expected: completionContext{
+				scope:  scopeMessage,
+				words:  []string{"repeated"},
+				prefix: "foo.v1.Ba",
+			},
+		},
+		{
+			name:  "nested-closed",
+			input: "message Foo {\n  enum Bar { BAR_UNSPECIFIED = 0; }\n  ",
+			expected: completionContext{
+				scope: scopeMessage,
+			},
+		},
+		{
+			name:  "import",
+			input: "import public \"foo/",
+			expected: completionContext{
+				scope:    scopeFile,
+				words:    []string{"import", "public"},
+				prefix:   "foo/",
+				inString: true,
+			},
+		},
+		{
+			name:  "option",
+			input: "service Foo {\n  option (my.",
+			expected: completionContext{
+				scope:          scopeService,
+				words:          []string{"option", "("},
+				prefix:         "my.",
+				parens:         1,
+				wantOptionName: true,
+			},
+		},
+		{
+			name:  "compact-option",
+			input: "message Foo {\n  string bar = 1 [deprecated = true, ",
+			expected: completionContext{
+				scope:          scopeMessage,
+				words:          []string{"string", "bar", "=", "1", "[", "deprecated", "=", "true", ","},
+				brackets:       1,
+				wantOptionName: true,
+			},
+		},
+		{
+			name:  "rpc",
+			input: "service Foo {\n  rpc Bar(stream ",
+			expected: completionContext{
+				scope:  scopeService,
+				words:  []string{"rpc", "Bar", "(", "stream"},
+				parens: 1,
+			},
+		},
+		{
+			name:  "map",
+			input: "message Foo {\n  map<string, ",
+			expected: completionContext{
+				scope:  scopeMessage,
+				words:  []string{"map", "<", "string", ","},
+				angles: 1,
+			},
+		},
+		{
+			name:     "message-literal",
+			input:    "option (foo) = {\n  ba",
+			expected: completionContext{scope: scopeLiteral, prefix: "ba"},
+		},
+		{
+			name:     "comment",
+			input:    "message Foo {\n  // Ba",
+			expected: completionContext{scope: scopeLiteral},
+		},
+		{
+			name:  "after-comments",
+			input: "/* message Bar { */\nmessage Foo { // }\n  int",
+			expected: completionContext{
+				scope:  scopeMessage,
+				prefix: "int",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, newCompletionContext(test.input))
+		})
+	}
+}
+
+func TestCompletionsNonASCII(t *testing.T) {
+	t.Parallel()
+
+	// The cursor is after "mes", which is preceded by a multi-byte character.
+	file := &file{text: "syntax = \"proto3\";\n/* é */ mes"}
+	items := file.Completions(context.Background(), protocol.Position{Line: 1, Character: 11})
+	require.NotEmpty(t, items)
+	for _, item := range items {
+		assert.Equal(t, protocol.Range{
+			Start: protocol.Position{Line: 1, Character: 8},
+			End:   protocol.Position{Line: 1, Character: 11},
+		}, item.TextEdit.Range)
+	}
+}
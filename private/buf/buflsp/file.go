@@ -37,6 +37,7 @@ import (
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/standard/xio"
 	"github.com/bufbuild/buf/private/pkg/standard/xlog/xslog"
+	"github.com/bufbuild/buf/private/pkg/standard/xslices"
 	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/protocompile/ast"
 	"github.com/bufbuild/protocompile/parser"
@@ -46,8 +47,13 @@ import (
 )
 
 const (
-	descriptorPath     = "google/protobuf/descriptor.proto"
-	checkRefreshPeriod = 3 * time.Second
+	descriptorPath = "google/protobuf/descriptor.proto"
+	// How long a file must go without changes before checks are run on it.
+	checkDebouncePeriod = 500 * time.Millisecond
+
+	// The sources of the diagnostics produced by lint and breaking checks.
+	lintSource     = "buf lint"
+	breakingSource = "buf breaking"
 )
 
 // file is a file that has been opened by the client.
@@ -57,6 +63,8 @@ type file struct {
 	lsp       *lsp
 	uri       protocol.URI
 	checkWork chan<- struct{}
+	// Cancels the checks currently running on this file, if any.
+	cancelChecks context.CancelFunc
 
 	text string
 	// Version is an opaque version identifier given to us by the LSP client. This
@@ -121,12 +129,22 @@ func (f *file) Reset(ctx context.Context) {
 	f.packageNode = nil
 	f.diagnostics = nil
 	f.importablePathToObject = nil
-	f.importToFile = nil
 	f.symbols = nil
 	f.image = nil
 
-	for _, imported := range f.importToFile {
-		imported.Close(ctx)
+	f.CloseImports(ctx)
+}
+
+// CloseImports releases the files opened by IndexImports, and forgets this file's
+// imports, so that they are indexed again by the next call to IndexImports.
+func (f *file) CloseImports(ctx context.Context) {
+	imports := f.importToFile
+	f.importToFile = nil
+	for _, imported := range imports {
+		// A file may import itself, e.g. the implicit import of descriptor.proto.
+		if imported != f {
+			imported.Close(ctx)
+		}
 	}
 }
 
@@ -136,6 +154,15 @@ func (f *file) Reset(ctx context.Context) {
 // for this file.
 func (f *file) Close(ctx context.Context) {
 	f.lsp.fileManager.Close(ctx, f.uri)
+}
+
+// StopChecks cancels any checks running on this file, and stops the goroutine that
+// runs them. This is called once the file is evicted; see [fileManager.Close].
+func (f *file) StopChecks() {
+	if f.cancelChecks != nil {
+		f.cancelChecks()
+		f.cancelChecks = nil
+	}
 	if f.checkWork != nil {
 		close(f.checkWork)
 		f.checkWork = nil
@@ -181,6 +208,12 @@ func (f *file) Update(ctx context.Context, version int32, text string) {
 	f.hasText = true
 }
 
+// Edit applies a sequence of incremental changes received from the LSP client to the
+// contents of this file.
+func (f *file) Edit(ctx context.Context, version int32, changes []contentChangeEvent) {
+	f.Update(ctx, version, applyContentChanges(f.text, changes))
+}
+
 // RefreshSettings refreshes configuration settings for this file.
 //
 // This only needs to happen when the file is open or when the client signals
@@ -313,43 +346,50 @@ func (f *file) Refresh(ctx context.Context) {
 // returns immediately.
 //
 // Checks are executed in a background goroutine to avoid blocking the LSP
-// call. Each call to RunChecks invalidates any ongoing checks, triggering a
-// fresh run. However, previous checks are not interrupted. The checks acquire
-// the LSP mutex. Subsequent LSP calls will wait for the current check to
-// complete before proceeding.
+// call. Each call to RunChecks invalidates any ongoing checks, cancelling them
+// and triggering a fresh run. Checks only hold the LSP mutex while taking a
+// snapshot of the file and while recording their results, so they do not block
+// subsequent LSP calls.
 //
-// Checks are debounce (with the delay defined by checkRefreshPeriod) to avoid
+// Checks are debounced (with the delay defined by checkDebouncePeriod) to avoid
 // overwhelming the client with expensive checks. If the file is not open in the
-// editor, checks are skipped. Diagnostics are published after checks are run.
+// editor, checks are skipped. Diagnostics are published after checks are run,
+// unless the file has changed since they started.
 func (f *file) RunChecks(ctx context.Context) {
+	// Abandon any checks running against an older version of this file.
+	if f.cancelChecks != nil {
+		f.cancelChecks()
+		f.cancelChecks = nil
+	}
+
 	// If we have not yet started a goroutine to run checks, start one.
 	// This goroutine will run checks in the background and publish diagnostics.
-	// We debounce checks to avoid spamming the client.
 	if f.checkWork == nil {
 		// We use a buffered channel of length one as the check invalidation mechanism.
 		work := make(chan struct{}, 1)
 		f.checkWork = work
-		runChecks := func(ctx context.Context) {
-			f.lsp.lock.Lock()
-			defer f.lsp.lock.Unlock()
-			if !f.IsOpenInEditor() {
-				// Skip checks if the file is not open in the editor.
-				return
-			}
-			f.lsp.logger.Info(fmt.Sprintf("running checks for %v, %v", f.uri, f.version))
-			f.BuildImages(ctx)
-			f.RunLints(ctx)
-			f.RunBreaking(ctx)
-			f.PublishDiagnostics(ctx) // Publish the latest diagnostics.
-		}
 		// Start a goroutine to process checks.
 		go func() {
 			// Detach from the parent RPC context.
 			ctx := context.WithoutCancel(ctx)
 			for range work {
-				runChecks(ctx)
-				// Debounce checks to prevent thrashing expensive checks.
-				time.Sleep(checkRefreshPeriod)
+				// Debounce checks to prevent thrashing expensive checks while the
+				// user is typing: wait until no invalidations arrive for a while.
+				timer := time.NewTimer(checkDebouncePeriod)
+			debounce:
+				for {
+					select {
+					case _, ok := <-work:
+						if !ok {
+							timer.Stop()
+							return
+						}
+						timer.Reset(checkDebouncePeriod)
+					case <-timer.C:
+						break debounce
+					}
+				}
+				f.runChecks(ctx)
 			}
 		}()
 	}
@@ -361,6 +401,50 @@ func (f *file) RunChecks(ctx context.Context) {
 	}
 }
 
+// runChecks runs checks on the current version of this file and publishes the
+// resulting diagnostics.
+//
+// This must be called without holding the LSP mutex.
+func (f *file) runChecks(ctx context.Context) {
+	f.lsp.lock.Lock()
+	if !f.IsOpenInEditor() {
+		// Skip checks if the file is not open in the editor.
+		f.lsp.lock.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	f.cancelChecks = cancel
+	checker := f.newChecker(ctx)
+	f.lsp.lock.Unlock()
+
+	f.lsp.logger.Info(fmt.Sprintf("running checks for %v, %v", checker.uri, checker.version))
+	checker.BuildImages(ctx)
+	checker.RunLints(ctx)
+	checker.RunBreaking(ctx)
+
+	f.lsp.lock.Lock()
+	defer f.lsp.lock.Unlock()
+	if ctx.Err() != nil || f.version != checker.version {
+		// The file changed while we were running; a newer run will take care of it.
+		f.lsp.logger.Debug(fmt.Sprintf("discarding stale checks for %v, %v", checker.uri, checker.version))
+		return
+	}
+	f.cancelChecks = nil
+
+	f.image = checker.image
+	f.againstImage = checker.againstImage
+	if len(checker.diagnostics) > 0 {
+		f.diagnostics = checker.diagnostics
+	}
+	for _, source := range []string{lintSource, breakingSource} {
+		if err, ok := checker.checkErrs[source]; ok {
+			f.appendLintErrors(source, err)
+		}
+	}
+	f.PublishDiagnostics(ctx) // Publish the latest diagnostics.
+}
+
 // RefreshAST reparses the file and generates diagnostics if necessary.
 //
 // Returns whether a reparse was necessary.
@@ -477,13 +561,17 @@ func (f *file) IndexImports(ctx context.Context) {
 		return
 	}
 
-	importable, err := findImportable(ctx, f.uri, f.lsp)
-	if err != nil {
-		f.lsp.logger.Warn(fmt.Sprintf("could not compute importable files for %s: %s", f.uri, err))
-		if f.importablePathToObject == nil {
+	// Files indexed as part of a workspace-wide query are seeded with the importable
+	// files of the file that triggered the query; see [fileManager.IndexWorkspace].
+	// Reusing them avoids recomputing the workspace for every file.
+	importable := f.importablePathToObject
+	if importable == nil {
+		var err error
+		importable, err = findImportable(ctx, f.uri, f.lsp)
+		if err != nil {
+			f.lsp.logger.Warn(fmt.Sprintf("could not compute importable files for %s: %s", f.uri, err))
 			return
 		}
-	} else if f.importablePathToObject == nil {
 		f.importablePathToObject = importable
 	}
 
@@ -505,61 +593,9 @@ func (f *file) IndexImports(ctx context.Context) {
 		if !ok {
 			continue
 		}
-
-		// If this is an external file, it will be in the cache and therefore
-		// finding imports via lsp.findImportable() will not work correctly:
-		// the bucket for the workspace found for a dependency will have
-		// truncated paths, and those workspace files will appear to be
-		// local rather than external.
-		//
-		// Thus, we search for name and all of its path suffixes. This is not
-		// ideal but is our only option in this case.
-		var fileInfo storage.ObjectInfo
-		var pathWasTruncated bool
-		name := node.Name.AsString()
-		for {
-			fileInfo, ok = importable[name]
-			if ok {
-				break
-			}
-
-			idx := strings.Index(name, "/")
-			if idx == -1 {
-				break
-			}
-
-			name = name[idx+1:]
-			pathWasTruncated = true
-		}
-		if fileInfo == nil {
-			f.lsp.logger.Warn(fmt.Sprintf("could not find URI for import %q", node.Name.AsString()))
-			continue
-		}
-		if pathWasTruncated && !strings.HasSuffix(fileInfo.LocalPath(), node.Name.AsString()) {
-			// Verify that the file we found, with a potentially too-short path, does in fact have
-			// the "correct" full path as a prefix. E.g., suppose we import a/b/c.proto. We find
-			// c.proto in importable. Now, we look at the full local path, which we expect to be of
-			// the form /home/blah/.cache/blah/a/b/c.proto or similar. If it does not contain
-			// a/b/c.proto as a suffix, we didn't find our file.
-			f.lsp.logger.Warn(fmt.Sprintf("could not find URI for import %q, but found same-suffix path %q", node.Name.AsString(), fileInfo.LocalPath()))
-			continue
-		}
-
-		f.lsp.logger.Debug(
-			"mapped import -> path",
-			slog.String("import", name),
-			slog.String("path", fileInfo.LocalPath()),
-		)
-
-		var imported *file
-		if fileInfo.LocalPath() == f.uri.Filename() {
-			imported = f
-		} else {
-			imported = f.Manager().Open(ctx, uri.File(fileInfo.LocalPath()))
+		if imported := f.openImport(ctx, importable, node.Name.AsString()); imported != nil {
+			f.importToFile[node.Name.AsString()] = imported
 		}
-
-		imported.objectInfo = fileInfo
-		f.importToFile[node.Name.AsString()] = imported
 	}
 
 	// descriptor.proto is always implicitly imported.
@@ -575,10 +611,12 @@ func (f *file) IndexImports(ctx context.Context) {
 		}
 	}
 
-	// FIXME: This algorithm is not correct: it does not account for `import public`.
-	fileImports := f.importToFile
-
-	for _, file := range fileImports {
+	// Walk the imports in order, so that results are deterministic. Files that are
+	// imported publicly by an import are visible from this file too, just as if this
+	// file imported them itself, so they are appended to the worklist as they are found.
+	worklist := xslices.MapKeysToSortedSlice(f.importToFile)
+	for i := 0; i < len(worklist); i++ {
+		file := f.importToFile[worklist[i]]
 		if err := file.ReadFromDisk(ctx); err != nil {
 			file.lsp.logger.Warn(fmt.Sprintf("could not load import import %q from disk: %s",
 				file.uri, err.Error()))
@@ -593,28 +631,160 @@ func (f *file) IndexImports(ctx context.Context) {
 			file.RefreshAST(ctx)
 		}
 		file.IndexSymbols(ctx)
+
+		if file == f || file.fileNode == nil {
+			continue
+		}
+		for _, decl := range file.fileNode.Decls {
+			node, ok := decl.(*ast.ImportNode)
+			if !ok || node.Public == nil {
+				continue
+			}
+			path := node.Name.AsString()
+			if _, ok := f.importToFile[path]; ok {
+				continue
+			}
+			if imported := f.openImport(ctx, importable, path); imported != nil {
+				f.importToFile[path] = imported
+				worklist = append(worklist, path)
+			}
+		}
+	}
+}
+
+// openImport opens the file with the given import path, which is looked up in
+// importable.
+//
+// Returns nil if the import could not be found.
+func (f *file) openImport(ctx context.Context, importable map[string]storage.ObjectInfo, path string) *file {
+	// If this is an external file, it will be in the cache and therefore
+	// finding imports via lsp.findImportable() will not work correctly:
+	// the bucket for the workspace found for a dependency will have
+	// truncated paths, and those workspace files will appear to be
+	// local rather than external.
+	//
+	// Thus, we search for name and all of its path suffixes. This is not
+	// ideal but is our only option in this case.
+	var fileInfo storage.ObjectInfo
+	var pathWasTruncated bool
+	name := path
+	for {
+		var ok bool
+		fileInfo, ok = importable[name]
+		if ok {
+			break
+		}
+
+		idx := strings.Index(name, "/")
+		if idx == -1 {
+			break
+		}
+
+		name = name[idx+1:]
+		pathWasTruncated = true
+	}
+	if fileInfo == nil {
+		f.lsp.logger.Warn(fmt.Sprintf("could not find URI for import %q", path))
+		return nil
+	}
+	if pathWasTruncated && !strings.HasSuffix(fileInfo.LocalPath(), path) {
+		// Verify that the file we found, with a potentially too-short path, does in fact have
+		// the "correct" full path as a prefix. E.g., suppose we import a/b/c.proto. We find
+		// c.proto in importable. Now, we look at the full local path, which we expect to be of
+		// the form /home/blah/.cache/blah/a/b/c.proto or similar. If it does not contain
+		// a/b/c.proto as a suffix, we didn't find our file.
+		f.lsp.logger.Warn(fmt.Sprintf("could not find URI for import %q, but found same-suffix path %q", path, fileInfo.LocalPath()))
+		return nil
+	}
+
+	f.lsp.logger.Debug(
+		"mapped import -> path",
+		slog.String("import", name),
+		slog.String("path", fileInfo.LocalPath()),
+	)
+
+	var imported *file
+	if fileInfo.LocalPath() == f.uri.Filename() {
+		imported = f
+	} else {
+		imported = f.Manager().Open(ctx, uri.File(fileInfo.LocalPath()))
+	}
+
+	imported.objectInfo = fileInfo
+	return imported
+}
+
+// checker is a snapshot of the state needed to run checks on a particular version of
+// a file, along with the results of those checks.
+//
+// Checkers are created while holding the LSP mutex, but are run without it, so that
+// slow checks do not block the LSP.
+type checker struct {
+	logger  *slog.Logger
+	uri     protocol.URI
+	version int32
+	isWKT   bool
+
+	objectInfo            storage.ObjectInfo
+	opener, againstOpener fileOpener
+	workspace             bufworkspace.Workspace
+	module                bufmodule.Module
+	checkClient           bufcheck.Client
+
+	image, againstImage bufimage.Image
+	diagnostics         []protocol.Diagnostic
+	// The errors returned by each check that was run, keyed by diagnostic source.
+	checkErrs map[string]error
+}
+
+// newChecker takes a snapshot of this file for running checks.
+//
+// This operation requires IndexImports() and FindModule().
+func (f *file) newChecker(ctx context.Context) *checker {
+	return &checker{
+		logger:        f.lsp.logger,
+		uri:           f.uri,
+		version:       f.version,
+		isWKT:         f.IsWKT(),
+		objectInfo:    f.objectInfo,
+		opener:        f.newFileOpener(),
+		againstOpener: f.newAgainstFileOpener(ctx),
+		workspace:     f.workspace,
+		module:        f.module,
+		checkClient:   f.checkClient,
+		checkErrs:     make(map[string]error),
 	}
 }
 
 // newFileOpener returns a fileOpener for the context of this file.
 //
+// The opener sees the contents that files tracked by the LSP have at the time this
+// function is called, and may be used without holding the LSP mutex.
+//
 // May return nil, if insufficient information is present to open the file.
 func (f *file) newFileOpener() fileOpener {
 	if f.importablePathToObject == nil {
 		return nil
 	}
 
+	importablePathToObject := f.importablePathToObject
+	uriToText := make(map[protocol.URI]string)
+	f.Manager().uriToFile.Range(func(uri protocol.URI, file *file) bool {
+		uriToText[uri] = file.text
+		return true
+	})
+
 	return func(path string) (io.ReadCloser, error) {
 		var newURI protocol.URI
-		fileInfo, ok := f.importablePathToObject[path]
+		fileInfo, ok := importablePathToObject[path]
 		if ok {
 			newURI = uri.File(fileInfo.LocalPath())
 		} else {
 			newURI = uri.File(path)
 		}
 
-		if file := f.Manager().Get(newURI); file != nil {
-			return xio.CompositeReadCloser(strings.NewReader(file.text), xio.NopCloser), nil
+		if text, tracked := uriToText[newURI]; tracked {
+			return xio.CompositeReadCloser(strings.NewReader(text), xio.NopCloser), nil
 		} else if !ok {
 			return nil, os.ErrNotExist
 		}
@@ -638,8 +808,13 @@ func (f *file) newAgainstFileOpener(ctx context.Context) fileOpener {
 		return nil
 	}
 
+	var (
+		importablePathToObject = f.importablePathToObject
+		container              = f.lsp.container
+		againstGitRef          = f.againstGitRef
+	)
 	return func(path string) (io.ReadCloser, error) {
-		fileInfo, ok := f.importablePathToObject[path]
+		fileInfo, ok := importablePathToObject[path]
 		if !ok {
 			return nil, fmt.Errorf("failed to resolve import: %s", path)
 		}
@@ -648,12 +823,12 @@ func (f *file) newAgainstFileOpener(ctx context.Context) fileOpener {
 			data []byte
 			err  error
 		)
-		if f.againstGitRef != "" {
+		if againstGitRef != "" {
 			data, err = git.ReadFileAtRef(
 				ctx,
-				f.lsp.container,
+				container,
 				fileInfo.LocalPath(),
-				f.againstGitRef,
+				againstGitRef,
 			)
 		}
 
@@ -667,89 +842,83 @@ func (f *file) newAgainstFileOpener(ctx context.Context) fileOpener {
 
 // BuildImages builds Buf Images for this file, to be used with linting
 // routines.
-//
-// This operation requires IndexImports().
-func (f *file) BuildImages(ctx context.Context) {
-	if f.objectInfo == nil {
+func (c *checker) BuildImages(ctx context.Context) {
+	if c.objectInfo == nil {
 		return
 	}
 
-	if opener := f.newFileOpener(); opener != nil {
-		image, diagnostics := buildImage(ctx, f.objectInfo.Path(), f.lsp.logger, opener)
-		if len(diagnostics) > 0 {
-			f.diagnostics = diagnostics
-		}
-		f.image = image
+	if c.opener != nil {
+		c.image, c.diagnostics = buildImage(ctx, c.objectInfo.Path(), c.logger, c.opener)
 	} else {
-		f.lsp.logger.Warn("not building image", slog.String("uri", string(f.uri)))
+		c.logger.Warn("not building image", slog.String("uri", string(c.uri)))
 	}
 
-	if opener := f.newAgainstFileOpener(ctx); opener != nil {
+	if c.againstOpener != nil && ctx.Err() == nil {
 		// We explicitly throw the diagnostics away.
-		image, diagnostics := buildImage(ctx, f.objectInfo.Path(), f.lsp.logger, opener)
+		image, diagnostics := buildImage(ctx, c.objectInfo.Path(), c.logger, c.againstOpener)
 
-		f.againstImage = image
+		c.againstImage = image
 		if image == nil {
-			f.lsp.logger.Warn("failed to build --against image", slog.Any("diagnostics", diagnostics))
+			c.logger.Warn("failed to build --against image", slog.Any("diagnostics", diagnostics))
 		}
 	} else {
-		f.lsp.logger.Warn("not building --against image", slog.String("uri", string(f.uri)))
+		c.logger.Warn("not building --against image", slog.String("uri", string(c.uri)))
 	}
 }
 
-// RunLints runs linting on this file. Returns whether any lints failed.
+// RunLints runs linting on this file.
 //
-// This operation requires BuildImage().
-func (f *file) RunLints(ctx context.Context) bool {
-	if f.IsWKT() {
+// This operation requires BuildImages().
+func (c *checker) RunLints(ctx context.Context) {
+	if c.isWKT || ctx.Err() != nil {
 		// Well-known types are not linted.
-		return false
+		return
 	}
 
-	if f.module == nil || f.image == nil {
-		f.lsp.logger.Warn(fmt.Sprintf("could not find image for %q", f.uri))
-		return false
+	if c.module == nil || c.image == nil {
+		c.logger.Warn(fmt.Sprintf("could not find image for %q", c.uri))
+		return
 	}
-	if f.checkClient == nil {
-		f.lsp.logger.Warn(fmt.Sprintf("could not find check client for %q", f.uri))
-		return false
+	if c.checkClient == nil {
+		c.logger.Warn(fmt.Sprintf("could not find check client for %q", c.uri))
+		return
 	}
 
-	f.lsp.logger.Debug(fmt.Sprintf("running lint for %q in %v", f.uri, f.module.FullName()))
-	return f.appendLintErrors("buf lint", f.checkClient.Lint(
+	c.logger.Debug(fmt.Sprintf("running lint for %q in %v", c.uri, c.module.FullName()))
+	c.checkErrs[lintSource] = c.checkClient.Lint(
 		ctx,
-		f.workspace.GetLintConfigForOpaqueID(f.module.OpaqueID()),
-		f.image,
-		bufcheck.WithPluginConfigs(f.workspace.PluginConfigs()...),
-	))
+		c.workspace.GetLintConfigForOpaqueID(c.module.OpaqueID()),
+		c.image,
+		bufcheck.WithPluginConfigs(c.workspace.PluginConfigs()...),
+	)
 }
 
-// RunBreaking runs breaking lints on this file. Returns whether any lints failed.
+// RunBreaking runs breaking lints on this file.
 //
-// This operation requires BuildImage().
-func (f *file) RunBreaking(ctx context.Context) bool {
-	if f.IsWKT() {
+// This operation requires BuildImages().
+func (c *checker) RunBreaking(ctx context.Context) {
+	if c.isWKT || ctx.Err() != nil {
 		// Well-known types are not linted.
-		return false
+		return
 	}
 
-	if f.module == nil || f.image == nil || f.againstImage == nil {
-		f.lsp.logger.Warn(fmt.Sprintf("could not find --against image for %q", f.uri))
-		return false
+	if c.module == nil || c.image == nil || c.againstImage == nil {
+		c.logger.Warn(fmt.Sprintf("could not find --against image for %q", c.uri))
+		return
 	}
-	if f.checkClient == nil {
-		f.lsp.logger.Warn(fmt.Sprintf("could not find check client for %q", f.uri))
-		return false
+	if c.checkClient == nil {
+		c.logger.Warn(fmt.Sprintf("could not find check client for %q", c.uri))
+		return
 	}
 
-	f.lsp.logger.Debug(fmt.Sprintf("running breaking for %q in %v", f.uri, f.module.FullName()))
-	return f.appendLintErrors("buf breaking", f.checkClient.Breaking(
+	c.logger.Debug(fmt.Sprintf("running breaking for %q in %v", c.uri, c.module.FullName()))
+	c.checkErrs[breakingSource] = c.checkClient.Breaking(
 		ctx,
-		f.workspace.GetBreakingConfigForOpaqueID(f.module.OpaqueID()),
-		f.image,
-		f.againstImage,
-		bufcheck.WithPluginConfigs(f.workspace.PluginConfigs()...),
-	))
+		c.workspace.GetBreakingConfigForOpaqueID(c.module.OpaqueID()),
+		c.image,
+		c.againstImage,
+		bufcheck.WithPluginConfigs(c.workspace.PluginConfigs()...),
+	)
 }
 
 func (f *file) appendLintErrors(source string, err error) bool {
@@ -769,6 +938,11 @@ func (f *file) appendLintErrors(source string, err error) bool {
 	}
 
 	for _, annotation := range annotations.FileAnnotations() {
+		// Check annotations are reported as warnings, as the file still compiles.
+		severity := protocol.DiagnosticSeverityWarning
+		if annotation.Severity() == bufanalysis.SeverityInfo {
+			severity = protocol.DiagnosticSeverityInformation
+		}
 		f.diagnostics = append(f.diagnostics, protocol.Diagnostic{
 			Range: protocol.Range{
 				Start: protocol.Position{
@@ -781,7 +955,7 @@ func (f *file) appendLintErrors(source string, err error) bool {
 				},
 			},
 			Code:     annotation.Type(),
-			Severity: protocol.DiagnosticSeverityWarning,
+			Severity: severity,
 			Source:   source,
 			Message:  annotation.Message(),
 		})
@@ -910,3 +1084,30 @@ func findImportable(
 type wktObjectInfo struct {
 	storage.ObjectInfo
 }
+
+// contentChangeEvent is a change to the contents of a file, as sent by the LSP client.
+//
+// This is a protocol.TextDocumentContentChangeEvent whose range may be absent: the range
+// of protocol.TextDocumentContentChangeEvent is not a pointer, so a change without a range
+// cannot be told apart from an insertion at the start of the file.
+type contentChangeEvent struct {
+	// Range is the range of the text that is replaced.
+	//
+	// If nil, the whole text is replaced, which the LSP permits even with incremental sync.
+	Range *protocol.Range `json:"range,omitempty"`
+	Text  string          `json:"text"`
+}
+
+// applyContentChanges applies a sequence of changes, as sent by the LSP client, to text.
+func applyContentChanges(text string, changes []contentChangeEvent) string {
+	for _, change := range changes {
+		if change.Range == nil {
+			text = change.Text
+			continue
+		}
+		start := positionToOffset(text, change.Range.Start)
+		end := max(start, positionToOffset(text, change.Range.End))
+		text = text[:start] + change.Text + text[end:]
+	}
+	return text
+}
@@ -18,9 +18,13 @@ package buflsp
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/bufbuild/buf/private/pkg/refcount"
+	"github.com/bufbuild/buf/private/pkg/standard/xslices"
 	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
 )
 
 // fileManager tracks all files the LSP is currently handling, whether read from disk or opened
@@ -60,5 +64,73 @@ func (fm *fileManager) Get(uri protocol.URI) *file {
 func (fm *fileManager) Close(ctx context.Context, uri protocol.URI) {
 	if deleted := fm.uriToFile.Delete(uri); deleted != nil {
 		deleted.Reset(ctx)
+		deleted.StopChecks()
+	}
+}
+
+// IndexWorkspace opens and indexes every file that is importable from the given file,
+// i.e. every file in its workspace and its dependencies. This is used to answer queries
+// that span the whole workspace, such as find-all-references.
+//
+// Files that were not already being tracked are opened for the duration of the query,
+// along with the files they import; the caller must call the returned function once it
+// is done with the files, to release them again.
+func (fm *fileManager) IndexWorkspace(ctx context.Context, from *file) ([]*file, func()) {
+	if from.importablePathToObject == nil {
+		return []*file{from}, func() {}
+	}
+
+	var (
+		files  []*file
+		opened []*file
+		// Files that were already being tracked, but whose imports are only indexed for
+		// the duration of the query.
+		indexed []*file
+		seen    = make(map[protocol.URI]bool)
+	)
+	// Walk the paths in order, so that results are deterministic.
+	for _, path := range xslices.MapKeysToSortedSlice(from.importablePathToObject) {
+		fileURI := uri.File(from.importablePathToObject[path].LocalPath())
+		if seen[fileURI] {
+			continue
+		}
+		seen[fileURI] = true
+
+		file := fm.Get(fileURI)
+		if file == nil {
+			file = fm.Open(ctx, fileURI)
+			opened = append(opened, file)
+			if err := file.ReadFromDisk(ctx); err != nil {
+				fm.lsp.logger.Warn(fmt.Sprintf("could not load workspace file %q from disk: %s", fileURI, err))
+				continue
+			}
+		}
+		if file.importablePathToObject == nil {
+			file.importablePathToObject = from.importablePathToObject
+		}
+
+		if file.fileNode == nil {
+			file.RefreshAST(ctx)
+		}
+		// Files that were only opened as imports have not had their own imports
+		// indexed, so their references to other files are not resolved yet.
+		if file.importToFile == nil {
+			file.IndexImports(ctx)
+			file.IndexSymbols(ctx)
+			if !slices.Contains(opened, file) {
+				indexed = append(indexed, file)
+			}
+		}
+		files = append(files, file)
+	}
+
+	return files, func() {
+		// Closing a file also closes its imports; see [file.Reset].
+		for _, file := range opened {
+			file.Close(ctx)
+		}
+		for _, file := range indexed {
+			file.CloseImports(ctx)
+		}
 	}
 }
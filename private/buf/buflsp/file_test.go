@@ -0,0 +1,148 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestApplyContentChanges(t *testing.T) {
+	t.Parallel()
+
+	change := func(startLine, startChar, endLine, endChar uint32, text string) contentChangeEvent {
+		return contentChangeEvent{
+			Range: &protocol.Range{
+				Start: protocol.Position{Line: startLine, Character: startChar},
+				End:   protocol.Position{Line: endLine, Character: endChar},
+			},
+			Text: text,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		changes  []contentChangeEvent
+		expected string
+	}{
+		{
+			name:     "insert",
+			text:     "message Foo {}\n",
+			changes:  []contentChangeEvent{change(0, 13, 0, 13, "\n  string bar = 1;\n")},
+			expected: "message Foo {\n  string bar = 1;\n}\n",
+		},
+		{
+			name:     "replace-across-lines",
+			text:     "message Foo {\n  string bar = 1;\n}\n",
+			changes:  []contentChangeEvent{change(0, 12, 2, 0, "")},
+			expected: "message Foo }\n",
+		},
+		{
+			name: "sequential",
+			text: "enum Foo {}\n",
+			changes: []contentChangeEvent{
+				change(0, 5, 0, 8, "Bar"),
+				change(1, 0, 1, 0, "// done\n"),
+			},
+			expected: "enum Bar {}\n// done\n",
+		},
+		{
+			name: "utf16",
+			// U+1F600 is two UTF-16 code units, but four UTF-8 bytes.
+			text:     "// \U0001F600 x\n",
+			changes:  []contentChangeEvent{change(0, 6, 0, 7, "y")},
+			expected: "// \U0001F600 y\n",
+		},
+		{
+			name: "full",
+			text: "message Foo {}\n",
+			changes: []contentChangeEvent{
+				change(0, 0, 0, 0, "// comment\n"),
+				{Text: "enum Bar {}\n"},
+				change(0, 5, 0, 8, "Baz"),
+			},
+			expected: "enum Baz {}\n",
+		},
+		{
+			name:     "out-of-range",
+			text:     "foo\n",
+			changes:  []contentChangeEvent{change(0, 10, 5, 0, "bar")},
+			expected: "foobar",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, applyContentChanges(test.text, test.changes))
+		})
+	}
+}
+
+func TestDidChangeTextDocumentParamsJSON(t *testing.T) {
+	t.Parallel()
+
+	var params didChangeTextDocumentParams
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "textDocument": {"uri": "file:///a.proto", "version": 2},
+  "contentChanges": [
+    {"range": {"start": {"line": 0, "character": 0}, "end": {"line": 0, "character": 0}}, "text": "a"},
+    {"text": "b"}
+  ]
+}`), &params))
+	require.Len(t, params.ContentChanges, 2)
+	assert.Equal(t, &protocol.Range{}, params.ContentChanges[0].Range)
+	assert.Nil(t, params.ContentChanges[1].Range)
+	assert.Equal(t, "b", applyContentChanges("foo", params.ContentChanges))
+}
+
+func TestCloseStopsChecksOnLastReference(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lsp := &lsp{logger: slogtestext.NewLogger(t)}
+	lsp.fileManager = newFileManager(lsp)
+	fileURI := uri.File("/test.proto")
+	// The file is opened twice, e.g. by the editor and as an import of another file.
+	file := lsp.fileManager.Open(ctx, fileURI)
+	require.Same(t, file, lsp.fileManager.Open(ctx, fileURI))
+	work := make(chan struct{}, 1)
+	file.checkWork = work
+	var cancelled bool
+	file.cancelChecks = func() { cancelled = true }
+
+	// Checks keep running while the file is still held.
+	file.Close(ctx)
+	assert.False(t, cancelled)
+	assert.NotNil(t, file.checkWork)
+	work <- struct{}{}
+
+	file.Close(ctx)
+	assert.True(t, cancelled)
+	assert.Nil(t, file.checkWork)
+	assert.Nil(t, lsp.fileManager.Get(fileURI))
+	// The pending work is still delivered before the channel is closed.
+	_, ok := <-work
+	assert.True(t, ok)
+	_, ok = <-work
+	assert.False(t, ok)
+}
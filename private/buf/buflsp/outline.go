@@ -0,0 +1,342 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements the structural views of files: document symbols (i.e., the
+// outline), workspace symbols and folding ranges.
+
+package buflsp
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/protocompile/ast"
+	"go.lsp.dev/protocol"
+)
+
+// maxWorkspaceSymbols is the maximum number of results returned for a workspace
+// symbol query.
+const maxWorkspaceSymbols = 500
+
+// DocumentSymbols returns the hierarchical outline of this file.
+func (f *file) DocumentSymbols() []protocol.DocumentSymbol {
+	if f.fileNode == nil {
+		return nil
+	}
+	return documentSymbols(f, f.fileNode.Decls)
+}
+
+// documentSymbols returns the outline of the given declarations, recursively.
+func documentSymbols[N ast.Node](f *file, decls []N) []protocol.DocumentSymbol {
+	var symbols []protocol.DocumentSymbol
+	for _, decl := range decls {
+		node := ast.Node(decl)
+		kind, ok := symbolKindForNode(node)
+		if !ok {
+			continue
+		}
+
+		var (
+			name     ast.Node
+			text     string
+			detail   string
+			children []protocol.DocumentSymbol
+		)
+		switch node := node.(type) {
+		case *ast.MessageNode:
+			name, text = node.Name, node.Name.Val
+			children = documentSymbols(f, node.Decls)
+		case *ast.GroupNode:
+			name, text = node.Name, node.Name.Val
+			detail = "group"
+			children = documentSymbols(f, node.Decls)
+		case *ast.FieldNode:
+			name, text = node.Name, node.Name.Val
+			detail = string(node.FldType.AsIdentifier())
+		case *ast.MapFieldNode:
+			name, text = node.Name, node.Name.Val
+			detail = fmt.Sprintf("map<%s, %s>", node.MapType.KeyType.Val, node.MapType.ValueType.AsIdentifier())
+		case *ast.OneofNode:
+			name, text = node.Name, node.Name.Val
+			detail = "oneof"
+			children = documentSymbols(f, node.Decls)
+		case *ast.EnumNode:
+			name, text = node.Name, node.Name.Val
+			children = documentSymbols(f, node.Decls)
+		case *ast.EnumValueNode:
+			name, text = node.Name, node.Name.Val
+			if node.Number != nil {
+				detail = f.fileNode.NodeInfo(node.Number).RawText()
+			}
+		case *ast.ServiceNode:
+			name, text = node.Name, node.Name.Val
+			children = documentSymbols(f, node.Decls)
+		case *ast.RPCNode:
+			name, text = node.Name, node.Name.Val
+			detail = fmt.Sprintf("(%s) returns (%s)", formatRPCType(node.Input), formatRPCType(node.Output))
+		case *ast.ExtendNode:
+			name, text = node.Extendee, "extend "+string(node.Extendee.AsIdentifier())
+			children = documentSymbols(f, node.Decls)
+		}
+
+		symbols = append(symbols, protocol.DocumentSymbol{
+			Name:           text,
+			Detail:         detail,
+			Kind:           kind,
+			Range:          infoToRange(f.fileNode.NodeInfo(node)),
+			SelectionRange: infoToRange(f.fileNode.NodeInfo(name)),
+			Children:       children,
+		})
+	}
+	return symbols
+}
+
+// formatRPCType formats the request or response type of an RPC.
+func formatRPCType(node *ast.RPCTypeNode) string {
+	if node.Stream != nil {
+		return "stream " + string(node.MessageType.AsIdentifier())
+	}
+	return string(node.MessageType.AsIdentifier())
+}
+
+// symbolKindForNode returns the LSP symbol kind for a declaration.
+//
+// Returns false for nodes that do not declare anything that shows up in an outline.
+func symbolKindForNode(node ast.Node) (protocol.SymbolKind, bool) {
+	switch node.(type) {
+	case *ast.MessageNode, *ast.GroupNode, *ast.OneofNode:
+		return protocol.SymbolKindStruct, true
+	case *ast.FieldNode, *ast.MapFieldNode:
+		return protocol.SymbolKindField, true
+	case *ast.EnumNode:
+		return protocol.SymbolKindEnum, true
+	case *ast.EnumValueNode:
+		return protocol.SymbolKindEnumMember, true
+	case *ast.ServiceNode:
+		return protocol.SymbolKindInterface, true
+	case *ast.RPCNode:
+		return protocol.SymbolKindMethod, true
+	case *ast.ExtendNode:
+		return protocol.SymbolKindNamespace, true
+	}
+	return 0, false
+}
+
+// workspaceSymbol is a definition that matches a workspace symbol query.
+type workspaceSymbol struct {
+	info     protocol.SymbolInformation
+	fullName string
+	score    int
+}
+
+// workspaceSymbols searches the definitions in files for ones that fuzzily match query.
+//
+// The results are not ordered; see rankWorkspaceSymbols.
+func workspaceSymbols(files []*file, query string) []workspaceSymbol {
+	var matches []workspaceSymbol
+	for _, file := range files {
+		pkg := strings.Join(file.Package(), ".")
+		for _, symbol := range file.symbols {
+			def, ok := symbol.kind.(*definition)
+			if !ok || len(def.path) == 0 {
+				continue
+			}
+			kind, ok := symbolKindForNode(def.node)
+			if !ok {
+				continue
+			}
+
+			name := def.path[len(def.path)-1]
+			container := strings.Join(def.path[:len(def.path)-1], ".")
+			if pkg != "" {
+				container = strings.Trim(pkg+"."+container, ".")
+			}
+			fullName := name
+			if container != "" {
+				fullName = container + "." + name
+			}
+
+			score, ok := fuzzyMatch(query, name, fullName)
+			if !ok {
+				continue
+			}
+			matches = append(matches, workspaceSymbol{
+				info: protocol.SymbolInformation{
+					Name:          name,
+					Kind:          kind,
+					Location:      protocol.Location{URI: file.uri, Range: symbol.Range()},
+					ContainerName: container,
+				},
+				fullName: fullName,
+				score:    score,
+			})
+		}
+	}
+	return matches
+}
+
+// rankWorkspaceSymbols orders matches from best to worst, and returns at most
+// maxWorkspaceSymbols of them.
+func rankWorkspaceSymbols(matches []workspaceSymbol) []protocol.SymbolInformation {
+	slices.SortStableFunc(matches, func(a, b workspaceSymbol) int {
+		if diff := b.score - a.score; diff != 0 {
+			return diff
+		}
+		return cmp.Compare(a.fullName, b.fullName)
+	})
+	if len(matches) > maxWorkspaceSymbols {
+		matches = matches[:maxWorkspaceSymbols]
+	}
+
+	symbols := make([]protocol.SymbolInformation, len(matches))
+	for i, match := range matches {
+		symbols[i] = match.info
+	}
+	return symbols
+}
+
+// fuzzyMatch matches a workspace symbol query against a symbol, given its name and
+// its fully-qualified name. Matching is case-insensitive.
+//
+// Returns false if the symbol does not match at all. Otherwise, returns a score that
+// is higher the better the match is: a prefix of the name beats a substring of the
+// fully-qualified name, which beats a subsequence of it.
+func fuzzyMatch(query, name, fullName string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	query = strings.ToLower(query)
+	name = strings.ToLower(name)
+	fullName = strings.ToLower(fullName)
+	switch {
+	case strings.HasPrefix(name, query):
+		return 3, true
+	case strings.Contains(fullName, query):
+		return 2, true
+	}
+
+	// Check if query is a subsequence of fullName.
+	rest := fullName
+	for _, r := range query {
+		idx := strings.IndexRune(rest, r)
+		if idx < 0 {
+			return 0, false
+		}
+		rest = rest[idx+len(string(r)):]
+	}
+	return 1, true
+}
+
+// FoldingRanges returns the foldable regions of this file: declaration bodies, blocks
+// of comments and groups of imports.
+func (f *file) FoldingRanges() []protocol.FoldingRange {
+	if f.fileNode == nil {
+		return nil
+	}
+
+	var ranges []protocol.FoldingRange
+	// addRange adds a range between two 0-indexed lines, if it spans more than one line.
+	addRange := func(start, end int, kind protocol.FoldingRangeKind) {
+		if end > start {
+			ranges = append(ranges, protocol.FoldingRange{
+				StartLine: clampToUint32(start),
+				EndLine:   clampToUint32(end),
+				Kind:      kind,
+			})
+		}
+	}
+
+	// Bodies. We fold up to the line before the closing brace, so that it stays
+	// visible, just like most editors do for other languages.
+	_ = ast.Walk(f.fileNode, ast.NoOpVisitor{}, ast.WithBefore(func(node ast.Node) error {
+		openBrace, closeBrace := bracesOf(node)
+		if openBrace != nil && closeBrace != nil {
+			addRange(f.fileNode.NodeInfo(openBrace).Start().Line-1, f.fileNode.NodeInfo(closeBrace).Start().Line-2, "")
+		}
+		return nil
+	}))
+
+	// Comment blocks, i.e. runs of comments on consecutive lines with no tokens
+	// between them.
+	runStart, runEnd := -1, -1
+	items := f.fileNode.Items()
+	for item, ok := items.First(); ok; item, ok = items.Next(item) {
+		_, comment := f.fileNode.GetItem(item)
+		if !comment.IsValid() {
+			addRange(runStart, runEnd, protocol.CommentFoldingRange)
+			runStart, runEnd = -1, -1
+			continue
+		}
+
+		start, end := comment.Start().Line-1, comment.End().Line-1
+		if runStart < 0 || start > runEnd+1 {
+			addRange(runStart, runEnd, protocol.CommentFoldingRange)
+			runStart = start
+		}
+		runEnd = end
+	}
+	addRange(runStart, runEnd, protocol.CommentFoldingRange)
+
+	// Groups of consecutive imports.
+	var first, last *ast.ImportNode
+	flush := func() {
+		if first != nil {
+			addRange(f.fileNode.NodeInfo(first).Start().Line-1, f.fileNode.NodeInfo(last).End().Line-1, protocol.ImportsFoldingRange)
+		}
+		first, last = nil, nil
+	}
+	for _, decl := range f.fileNode.Decls {
+		imp, ok := decl.(*ast.ImportNode)
+		if !ok {
+			flush()
+			continue
+		}
+		if first == nil {
+			first = imp
+		}
+		last = imp
+	}
+	flush()
+
+	slices.SortStableFunc(ranges, func(a, b protocol.FoldingRange) int {
+		return cmp.Compare(a.StartLine, b.StartLine)
+	})
+	return ranges
+}
+
+// bracesOf returns the braces that delimit the body of node, if it has one.
+func bracesOf(node ast.Node) (openBrace, closeBrace *ast.RuneNode) {
+	switch node := node.(type) {
+	case *ast.MessageNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.GroupNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.ExtendNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.OneofNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.EnumNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.ServiceNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.RPCNode:
+		return node.OpenBrace, node.CloseBrace
+	case *ast.MessageLiteralNode:
+		return node.Open, node.Close
+	}
+	return nil, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/protocol"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query    string
+		score    int
+		expected bool
+	}{
+		{query: "", score: 0, expected: true},
+		{query: "get", score: 3, expected: true},
+		{query: "v1.GetFoo", score: 2, expected: true},
+		{query: "fvgfr", score: 1, expected: true},
+		{query: "bar", score: 0, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			t.Parallel()
+			score, ok := fuzzyMatch(test.query, "GetFooRequest", "foo.v1.GetFooRequest")
+			assert.Equal(t, test.expected, ok)
+			assert.Equal(t, test.score, score)
+		})
+	}
+}
+
+func TestFoldingRanges(t *testing.T) {
+	t.Parallel()
+
+	const text = `syntax = "proto3";
+
+import "a.proto";
+import "b.proto";
+
+// Foo is a message.
+// It has a field.
+message Foo {
+  string bar = 1;
+  enum Baz {
+    BAZ_UNSPECIFIED = 0;
+  }
+}
+`
+	fileNode, err := parser.Parse("test.proto", strings.NewReader(text), reporter.NewHandler(nil))
+	require.NoError(t, err)
+	file := &file{text: text, fileNode: fileNode}
+
+	assert.Equal(t, []protocol.FoldingRange{
+		{StartLine: 2, EndLine: 3, Kind: protocol.ImportsFoldingRange},
+		{StartLine: 5, EndLine: 6, Kind: protocol.CommentFoldingRange},
+		{StartLine: 7, EndLine: 11},
+		{StartLine: 9, EndLine: 10},
+	}, file.FoldingRanges())
+}
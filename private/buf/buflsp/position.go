@@ -0,0 +1,79 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements conversions between LSP positions and byte offsets.
+
+package buflsp
+
+import (
+	"math"
+	"strings"
+	"unicode/utf16"
+
+	"go.lsp.dev/protocol"
+)
+
+// positionToOffset converts an LSP position into a byte offset into text.
+//
+// The character offset of an LSP position is in UTF-16 code units, so that lines with
+// non-ASCII characters, such as in comments, are converted correctly. Positions past the
+// end of a line or of the text are clamped.
+func positionToOffset(text string, pos protocol.Position) int {
+	offset := 0
+	for range pos.Line {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+
+	units := 0
+	for i, r := range text[offset:] {
+		if r == '\n' || units >= int(pos.Character) {
+			return offset + i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(text)
+}
+
+// offsetToPosition converts a byte offset into text into an LSP position.
+//
+// This is the inverse of positionToOffset.
+func offsetToPosition(text string, offset int) protocol.Position {
+	line := strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	units := 0
+	for _, r := range text[lineStart:offset] {
+		units += utf16.RuneLen(r)
+	}
+	return protocol.Position{
+		Line:      clampToUint32(line),
+		Character: clampToUint32(units),
+	}
+}
+
+// clampToUint32 converts n to a uint32, clamping it to the range of uint32.
+//
+// LSP positions are uint32s, so this is used to convert line and character counts.
+func clampToUint32(n int) uint32 {
+	if n < 0 {
+		return 0
+	}
+	if n > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(n)
+}
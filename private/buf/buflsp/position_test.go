@@ -0,0 +1,55 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/protocol"
+)
+
+func TestPositionToOffset(t *testing.T) {
+	t.Parallel()
+
+	text := "ab\ncde\n\nf"
+	for offset := range len(text) + 1 {
+		assert.Equal(t, offset, positionToOffset(text, offsetToPosition(text, offset)))
+	}
+	// Positions past the end of a line are clamped to the end of that line.
+	assert.Equal(t, 6, positionToOffset(text, protocol.Position{Line: 1, Character: 10}))
+	assert.Equal(t, len(text), positionToOffset(text, protocol.Position{Line: 10}))
+
+	// Characters are in UTF-16 code units: "é" is one code unit in two bytes, and "😀"
+	// is two code units in four bytes.
+	text = "// é😀\nab"
+	assert.Equal(t, 5, positionToOffset(text, protocol.Position{Character: 4}))
+	assert.Equal(t, 9, positionToOffset(text, protocol.Position{Character: 6}))
+	assert.Equal(t, protocol.Position{Character: 6}, offsetToPosition(text, 9))
+	assert.Equal(t, protocol.Position{Line: 1, Character: 1}, offsetToPosition(text, 11))
+	for _, offset := range []int{0, 3, 5, 9, 10, 11, 12} {
+		assert.Equal(t, offset, positionToOffset(text, offsetToPosition(text, offset)))
+	}
+}
+
+func TestClampToUint32(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uint32(0), clampToUint32(-1))
+	assert.Equal(t, uint32(42), clampToUint32(42))
+	assert.Equal(t, uint32(math.MaxUint32), clampToUint32(math.MaxUint32))
+	assert.Equal(t, uint32(math.MaxUint32), clampToUint32(math.MaxUint32+1))
+}
@@ -19,8 +19,11 @@ package buflsp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"runtime/debug"
+	"slices"
 	"strings"
 
 	"github.com/bufbuild/buf/private/buf/bufformat"
@@ -44,6 +47,9 @@ const (
 )
 
 var (
+	// identifierRegexp matches a single (unqualified) Protobuf identifier.
+	identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 	// These slices must match the order of the indices in the above const block.
 	semanticTypeLegend = []string{
 		"type", "struct", "variable", "enum",
@@ -68,7 +74,7 @@ type server struct {
 }
 
 // newServer creates a protocol.Server implementation out of an lsp.
-func newServer(lsp *lsp) protocol.Server {
+func newServer(lsp *lsp) *server {
 	return &server{lsp: lsp}
 }
 
@@ -110,19 +116,44 @@ func (s *server) Initialize(
 			// For now, incomplete features are explicitly disabled here as TODOs.
 			TextDocumentSync: &protocol.TextDocumentSyncOptions{
 				OpenClose: true,
-				// Request that whole files be sent to us. Protobuf IDL files don't
-				// usually get especially huge, so this simplifies our logic without
-				// necessarily making the LSP slow.
-				Change: protocol.TextDocumentSyncKindFull,
+				// Request that only the changed parts of files be sent to us. Some
+				// files, such as generated APIs, get large enough that sending the
+				// whole file on every keystroke is noticeably slow.
+				Change: protocol.TextDocumentSyncKindIncremental,
 				Save: &protocol.SaveOptions{
 					IncludeText: false,
 				},
 			},
+			CodeActionProvider: &protocol.CodeActionOptions{
+				CodeActionKinds: []protocol.CodeActionKind{protocol.QuickFix},
+			},
+			CodeLensProvider: &protocol.CodeLensOptions{},
+			CompletionProvider: &protocol.CompletionOptions{
+				TriggerCharacters: []string{".", "(", "\"", "/"},
+			},
 			DefinitionProvider: &protocol.DefinitionOptions{
 				WorkDoneProgressOptions: protocol.WorkDoneProgressOptions{WorkDoneProgress: true},
 			},
 			DocumentFormattingProvider: true,
-			HoverProvider:              true,
+			DocumentOnTypeFormattingProvider: &protocol.DocumentOnTypeFormattingOptions{
+				FirstTriggerCharacter: "}",
+			},
+			DocumentRangeFormattingProvider: true,
+			DocumentSymbolProvider:          true,
+			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
+				Commands: []string{commandRename},
+			},
+			FoldingRangeProvider: true,
+			HoverProvider:        true,
+			ImplementationProvider: &protocol.ImplementationOptions{
+				WorkDoneProgressOptions: protocol.WorkDoneProgressOptions{WorkDoneProgress: true},
+			},
+			ReferencesProvider: &protocol.ReferenceOptions{
+				WorkDoneProgressOptions: protocol.WorkDoneProgressOptions{WorkDoneProgress: true},
+			},
+			RenameProvider: &protocol.RenameOptions{
+				PrepareProvider: true,
+			},
 			SemanticTokensProvider: &SemanticTokensOptions{
 				WorkDoneProgressOptions: protocol.WorkDoneProgressOptions{WorkDoneProgress: true},
 				Legend: SemanticTokensLegend{
@@ -131,6 +162,10 @@ func (s *server) Initialize(
 				},
 				Full: true,
 			},
+			TypeDefinitionProvider: &protocol.TypeDefinitionOptions{
+				WorkDoneProgressOptions: protocol.WorkDoneProgressOptions{WorkDoneProgress: true},
+			},
+			WorkspaceSymbolProvider: true,
 		},
 		ServerInfo: info,
 	}, nil
@@ -218,9 +253,34 @@ func (s *server) DidOpen(
 
 // DidChange is called whenever the client opens a document. This is our signal to parse
 // the file.
+//
+// The notifications sent by the client are decoded into didChangeTextDocumentParams
+// instead, see handleDidChange. This is only here to implement protocol.Server.
 func (s *server) DidChange(
 	ctx context.Context,
 	params *protocol.DidChangeTextDocumentParams,
+) error {
+	changes := make([]contentChangeEvent, len(params.ContentChanges))
+	for i, change := range params.ContentChanges {
+		changes[i] = contentChangeEvent{Range: &change.Range, Text: change.Text}
+	}
+	return s.didChange(ctx, &didChangeTextDocumentParams{
+		TextDocument:   params.TextDocument,
+		ContentChanges: changes,
+	})
+}
+
+// didChangeTextDocumentParams is a protocol.DidChangeTextDocumentParams whose changes
+// may replace the whole document, see contentChangeEvent.
+type didChangeTextDocumentParams struct {
+	TextDocument   protocol.VersionedTextDocumentIdentifier `json:"textDocument"`
+	ContentChanges []contentChangeEvent                     `json:"contentChanges"`
+}
+
+// didChange applies the changes that the client made to a document.
+func (s *server) didChange(
+	ctx context.Context,
+	params *didChangeTextDocumentParams,
 ) error {
 	file := s.fileManager.Get(params.TextDocument.URI)
 	if file == nil {
@@ -228,7 +288,7 @@ func (s *server) DidChange(
 		return fmt.Errorf("received update for file that was not open: %q", params.TextDocument.URI)
 	}
 
-	file.Update(ctx, params.TextDocument.Version, params.ContentChanges[0].Text)
+	file.Edit(ctx, params.TextDocument.Version, params.ContentChanges)
 	file.Refresh(ctx)
 	return nil
 }
@@ -260,16 +320,8 @@ func (s *server) Formatting(
 		return nil, fmt.Errorf("received update for file that was not open: %q", params.TextDocument.URI)
 	}
 
-	// We check the diagnostics on the file, if there are any build errors, we do not want
-	// to format an invalid AST, so we skip formatting and return an error for logging.
-	errorCount := 0
-	for _, diagnostic := range file.diagnostics {
-		if diagnostic.Severity == protocol.DiagnosticSeverityError {
-			errorCount += 1
-		}
-	}
-	if errorCount > 0 {
-		return nil, fmt.Errorf("cannot format file %q, %v error(s) found", file.uri.Filename(), errorCount)
+	if err := checkFormattable(file); err != nil {
+		return nil, err
 	}
 
 	// Currently we have no way to honor any of the parameters.
@@ -320,6 +372,94 @@ func (s *server) Formatting(
 	}, nil
 }
 
+// RangeFormatting is called whenever the user requests formatting of part of a file,
+// such as a selection.
+func (s *server) RangeFormatting(
+	ctx context.Context,
+	params *protocol.DocumentRangeFormattingParams,
+) ([]protocol.TextEdit, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		// Format for a file we don't know about? Seems bad!
+		return nil, fmt.Errorf("received update for file that was not open: %q", params.TextDocument.URI)
+	}
+	if err := checkFormattable(file); err != nil {
+		return nil, err
+	}
+
+	return formatRange(file, params.Range)
+}
+
+// OnTypeFormatting is called whenever the user types one of the trigger characters we
+// advertise, i.e. a closing brace. This formats the declaration it closes.
+func (s *server) OnTypeFormatting(
+	ctx context.Context,
+	params *protocol.DocumentOnTypeFormattingParams,
+) ([]protocol.TextEdit, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	// Unlike with explicit formatting, the user did not ask for this, so we do not
+	// complain about files we cannot format.
+	if file == nil || checkFormattable(file) != nil {
+		return nil, nil
+	}
+
+	// The position is just after the character that was typed.
+	typed := params.Position
+	if typed.Character > 0 {
+		typed.Character--
+	}
+	return formatRange(file, protocol.Range{Start: typed, End: typed})
+}
+
+// checkFormattable returns an error if file cannot be formatted.
+func checkFormattable(file *file) error {
+	// We check the diagnostics on the file, if there are any build errors, we do not want
+	// to format an invalid AST, so we skip formatting and return an error for logging.
+	errorCount := 0
+	for _, diagnostic := range file.diagnostics {
+		if diagnostic.Severity == protocol.DiagnosticSeverityError {
+			errorCount += 1
+		}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("cannot format file %q, %v error(s) found", file.uri.Filename(), errorCount)
+	}
+	return nil
+}
+
+// formatRange returns the edits that format the declarations of file that overlap the
+// given range.
+func formatRange(file *file, range_ protocol.Range) ([]protocol.TextEdit, error) {
+	if file.fileNode == nil {
+		return nil, nil
+	}
+
+	edits, err := bufformat.FormatFileNodeRange(
+		file.fileNode,
+		positionToOffset(file.text, range_.Start),
+		positionToOffset(file.text, range_.End),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var textEdits []protocol.TextEdit
+	for _, edit := range edits {
+		// Avoid sending edits that do not change anything.
+		if file.text[edit.Start:edit.End] == edit.NewText {
+			continue
+		}
+		textEdits = append(textEdits, protocol.TextEdit{
+			Range: protocol.Range{
+				Start: offsetToPosition(file.text, edit.Start),
+				End:   offsetToPosition(file.text, edit.End),
+			},
+			NewText: edit.NewText,
+		})
+	}
+	return textEdits, nil
+}
+
 // DidClose is called whenever the client closes a document.
 func (s *server) DidClose(
 	ctx context.Context,
@@ -400,6 +540,353 @@ func (s *server) Definition(
 	return nil, nil
 }
 
+// Implementation is the entry point for go-to-implementation.
+//
+// For an RPC, this jumps to its request and response messages.
+func (s *server) Implementation(
+	ctx context.Context,
+	params *protocol.ImplementationParams,
+) ([]protocol.Location, error) {
+	return s.rpcMessageTypes(ctx, params.TextDocumentPositionParams, &params.WorkDoneProgressParams)
+}
+
+// TypeDefinition is the entry point for go-to-type-definition.
+//
+// For an RPC, this jumps to its request and response messages.
+func (s *server) TypeDefinition(
+	ctx context.Context,
+	params *protocol.TypeDefinitionParams,
+) ([]protocol.Location, error) {
+	return s.rpcMessageTypes(ctx, params.TextDocumentPositionParams, &params.WorkDoneProgressParams)
+}
+
+// rpcMessageTypes returns the locations of the request and response messages of the RPC
+// at the given position.
+func (s *server) rpcMessageTypes(
+	ctx context.Context,
+	params protocol.TextDocumentPositionParams,
+	progressParams *protocol.WorkDoneProgressParams,
+) ([]protocol.Location, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	progress := newProgressFromClient(s.lsp, progressParams)
+	progress.Begin(ctx, "Searching")
+	defer progress.Done(ctx)
+
+	symbol := file.SymbolAt(ctx, params.Position)
+	if symbol == nil {
+		return nil, nil
+	}
+
+	var locations []protocol.Location
+	for _, def := range symbol.RPCMessageTypes(ctx) {
+		location := protocol.Location{URI: def.file.uri, Range: def.Range()}
+		// The request and response are often the same message, e.g. google.protobuf.Empty.
+		if !slices.Contains(locations, location) {
+			locations = append(locations, location)
+		}
+	}
+	return locations, nil
+}
+
+// References is the entry point for find-all-references.
+func (s *server) References(
+	ctx context.Context,
+	params *protocol.ReferenceParams,
+) ([]protocol.Location, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	progress := newProgressFromClient(s.lsp, &params.WorkDoneProgressParams)
+	progress.Begin(ctx, "Searching")
+	defer progress.Done(ctx)
+
+	symbol := file.SymbolAt(ctx, params.Position)
+	if symbol == nil {
+		return nil, nil
+	}
+	// Symbols are replaced when a file is reindexed, so we identify the definition
+	// by its URI and path rather than by its symbol.
+	defURI, defPath, ok := symbol.DefinitionPath(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	files, release := s.fileManager.IndexWorkspace(ctx, file)
+	defer release()
+
+	var locations []protocol.Location
+	seen := make(map[protocol.Location]bool)
+	for i, file := range files {
+		progress.Report(ctx, fmt.Sprintf("%d/%d", i+1, len(files)), float64(i)/float64(len(files)))
+
+		for _, symbol := range file.symbols {
+			if !symbol.Refers(defURI, defPath) {
+				continue
+			}
+			if _, isDef := symbol.kind.(*definition); isDef && !params.Context.IncludeDeclaration {
+				continue
+			}
+
+			location := protocol.Location{URI: file.uri, Range: symbol.Range()}
+			if !seen[location] {
+				seen[location] = true
+				locations = append(locations, location)
+			}
+		}
+	}
+
+	return locations, nil
+}
+
+// DocumentSymbol is the entry point for the document outline.
+func (s *server) DocumentSymbol(
+	ctx context.Context,
+	params *protocol.DocumentSymbolParams,
+) ([]any, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	// The protocol allows returning either SymbolInformation or DocumentSymbol
+	// values; we always return the latter, since they form a hierarchy.
+	symbols := file.DocumentSymbols()
+	result := make([]any, len(symbols))
+	for i, symbol := range symbols {
+		result[i] = symbol
+	}
+	return result, nil
+}
+
+// Symbols is the entry point for workspace symbol search.
+func (s *server) Symbols(
+	ctx context.Context,
+	params *protocol.WorkspaceSymbolParams,
+) ([]protocol.SymbolInformation, error) {
+	progress := newProgressFromClient(s.lsp, &params.WorkDoneProgressParams)
+	progress.Begin(ctx, "Searching")
+	defer progress.Done(ctx)
+
+	// We cannot open files while ranging over the file manager, so collect the files
+	// we already have first.
+	var tracked []*file
+	s.fileManager.uriToFile.Range(func(_ protocol.URI, file *file) bool {
+		tracked = append(tracked, file)
+		return true
+	})
+
+	var matches []workspaceSymbol
+	seen := make(map[protocol.URI]bool)
+	for _, trackedFile := range tracked {
+		// Files in a workspace that was already searched do not need to index it again.
+		if seen[trackedFile.uri] {
+			continue
+		}
+		workspace, release := s.fileManager.IndexWorkspace(ctx, trackedFile)
+		var files []*file
+		for _, file := range workspace {
+			if !seen[file.uri] {
+				seen[file.uri] = true
+				files = append(files, file)
+			}
+		}
+		// Files opened for the search are closed by release, so their symbols must be
+		// collected before then.
+		matches = append(matches, workspaceSymbols(files, params.Query)...)
+		release()
+	}
+
+	return rankWorkspaceSymbols(matches), nil
+}
+
+// FoldingRanges is the entry point for code folding.
+func (s *server) FoldingRanges(
+	ctx context.Context,
+	params *protocol.FoldingRangeParams,
+) ([]protocol.FoldingRange, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	return file.FoldingRanges(), nil
+}
+
+// CodeLens is the entry point for code lenses.
+func (s *server) CodeLens(
+	ctx context.Context,
+	params *protocol.CodeLensParams,
+) ([]protocol.CodeLens, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	progress := newProgressFromClient(s.lsp, &params.WorkDoneProgressParams)
+	progress.Begin(ctx, "Searching")
+	defer progress.Done(ctx)
+
+	files, release := s.fileManager.IndexWorkspace(ctx, file)
+	defer release()
+
+	return file.CodeLenses(files), nil
+}
+
+// CodeAction is the entry point for code actions, i.e. quick fixes.
+func (s *server) CodeAction(
+	ctx context.Context,
+	params *protocol.CodeActionParams,
+) ([]protocol.CodeAction, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	return file.CodeActions(ctx, params.Range), nil
+}
+
+// ExecuteCommand is called when the client runs one of the commands we advertise, usually
+// because the user picked a code action that carries one.
+func (s *server) ExecuteCommand(
+	ctx context.Context,
+	params *protocol.ExecuteCommandParams,
+) (any, error) {
+	switch params.Command {
+	case commandRename:
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("%s: expected 1 argument, got %d", params.Command, len(params.Arguments))
+		}
+		// The argument has already been decoded into a generic value, so we need to
+		// round-trip it through JSON to get our params back.
+		data, err := json.Marshal(params.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		var renameParams protocol.RenameParams
+		if err := json.Unmarshal(data, &renameParams); err != nil {
+			return nil, fmt.Errorf("%s: invalid argument: %w", params.Command, err)
+		}
+
+		edit, err := s.Rename(ctx, &renameParams)
+		if err != nil || edit == nil {
+			return nil, err
+		}
+		// The error is logged for us by the client wrapper.
+		_, _ = s.client.ApplyEdit(ctx, &protocol.ApplyWorkspaceEditParams{
+			Label: "Rename to " + renameParams.NewName,
+			Edit:  *edit,
+		})
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown command: %q", params.Command)
+}
+
+// Completion is the entry point for code completion.
+func (s *server) Completion(
+	ctx context.Context,
+	params *protocol.CompletionParams,
+) (*protocol.CompletionList, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	items := file.Completions(ctx, params.Position)
+	if items == nil {
+		// The result must not be a JSON null, which would mean "no completion
+		// support" to some clients, rather than "nothing to complete".
+		items = []protocol.CompletionItem{}
+	}
+	return &protocol.CompletionList{Items: items}, nil
+}
+
+// PrepareRename is called to check whether the symbol under the cursor may be renamed,
+// before the client asks the user for a new name.
+func (s *server) PrepareRename(
+	ctx context.Context,
+	params *protocol.PrepareRenameParams,
+) (*protocol.Range, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	symbol := file.SymbolAt(ctx, params.Position)
+	if symbol == nil {
+		return nil, nil
+	}
+	if err := symbol.CheckRenamable(ctx); err != nil {
+		return nil, err
+	}
+	defURI, defPath, _ := symbol.DefinitionPath(ctx)
+
+	range_, ok := symbol.RenameRange(defURI, defPath)
+	if !ok {
+		return nil, nil
+	}
+	return &range_, nil
+}
+
+// Rename is the entry point for renaming a symbol. This renames the symbol's definition
+// and every reference to it in the workspace.
+func (s *server) Rename(
+	ctx context.Context,
+	params *protocol.RenameParams,
+) (*protocol.WorkspaceEdit, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	if !identifierRegexp.MatchString(params.NewName) {
+		return nil, fmt.Errorf("cannot rename: %q is not a valid Protobuf identifier", params.NewName)
+	}
+
+	symbol := file.SymbolAt(ctx, params.Position)
+	if symbol == nil {
+		return nil, nil
+	}
+	if err := symbol.CheckRenamable(ctx); err != nil {
+		return nil, err
+	}
+	// Symbols are replaced when a file is reindexed, so we identify the definition
+	// by its URI and path rather than by its symbol.
+	defURI, defPath, _ := symbol.DefinitionPath(ctx)
+
+	files, release := s.fileManager.IndexWorkspace(ctx, file)
+	defer release()
+
+	changes := make(map[protocol.DocumentURI][]protocol.TextEdit)
+	for _, file := range files {
+		// Never touch files that are not ours to edit.
+		if file.IsWKT() || !file.IsLocal() {
+			continue
+		}
+
+		seen := make(map[protocol.Range]bool)
+		for _, symbol := range file.symbols {
+			range_, ok := symbol.RenameRange(defURI, defPath)
+			if !ok || seen[range_] {
+				continue
+			}
+			seen[range_] = true
+			changes[file.uri] = append(changes[file.uri], protocol.TextEdit{
+				Range:   range_,
+				NewText: params.NewName,
+			})
+		}
+	}
+
+	return &protocol.WorkspaceEdit{Changes: changes}, nil
+}
+
 // SemanticTokensFull is called to render semantic token information on the client.
 func (s *server) SemanticTokensFull(
 	ctx context.Context,
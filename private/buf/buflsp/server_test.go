@@ -0,0 +1,297 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
+
+func TestFormatRange(t *testing.T) {
+	t.Parallel()
+
+	// The comments make the character offsets in UTF-16 code units differ from the byte offsets.
+	const text = `syntax = "proto3";
+
+message Foo { /* é😀 */ string   bar = 1; }
+
+message Bar {   string   baz = 1; }
+`
+	fileNode, err := parser.Parse("test.proto", strings.NewReader(text), reporter.NewHandler(nil))
+	require.NoError(t, err)
+	file := &file{text: text, fileNode: fileNode}
+
+	// Only Foo overlaps the range, which is on the line of the comment.
+	edits, err := formatRange(file, protocol.Range{
+		Start: protocol.Position{Line: 2, Character: 30},
+		End:   protocol.Position{Line: 2, Character: 30},
+	})
+	require.NoError(t, err)
+	require.Len(t, edits, 1)
+	assert.Equal(t, protocol.Range{
+		Start: protocol.Position{Line: 2, Character: 0},
+		// The end of the line is 43 UTF-16 code units, or 46 bytes, into the line.
+		End: protocol.Position{Line: 2, Character: 43},
+	}, edits[0].Range)
+	assert.Equal(t, `syntax = "proto3";
+
+message Foo {
+  /* é😀 */
+  string bar = 1;
+}
+
+message Bar {   string   baz = 1; }
+`, applyTextEdits(text, edits))
+}
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+
+	client := newTestServer(t)
+	// Only the file of the definition is open, so the reference from the other file is
+	// found by indexing the workspace.
+	fooURI := openTestFile(t, client, filepath.Join("testdata", "workspace", "foo", "v1", "foo.proto"))
+	bazURI := uri.File(filepath.Join(filepath.Dir(fooURI.Filename()), "baz.proto"))
+
+	// The references to Foo, but not the reference to Foo.Bar.
+	references := []protocol.Location{
+		{URI: bazURI, Range: newTestRange(7, 2, 7, 5)},
+		{URI: bazURI, Range: newTestRange(8, 2, 8, 12)},
+	}
+	definition := protocol.Location{URI: fooURI, Range: newTestRange(6, 8, 6, 11)}
+	for _, includeDeclaration := range []bool{true, false} {
+		locations, err := client.References(context.Background(), &protocol.ReferenceParams{
+			TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: fooURI},
+				Position:     protocol.Position{Line: 6, Character: 9},
+			},
+			Context: protocol.ReferenceContext{IncludeDeclaration: includeDeclaration},
+		})
+		require.NoError(t, err)
+		if includeDeclaration {
+			assert.ElementsMatch(t, append([]protocol.Location{definition}, references...), locations)
+		} else {
+			assert.ElementsMatch(t, references, locations)
+		}
+	}
+}
+
+func TestWorkspaceQueriesAfterEdit(t *testing.T) {
+	t.Parallel()
+
+	dirPath := t.TempDir()
+	writeFile := func(path string, text string) {
+		path = filepath.Join(dirPath, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(text), 0600))
+	}
+	writeFile("buf.yaml", "version: v2\n")
+	writeFile("a/v1/a.proto", `syntax = "proto3";
+
+package a.v1;
+
+message A {}
+`)
+	// Neither b.proto nor c.proto is open.
+	writeFile("a/v1/b.proto", `syntax = "proto3";
+
+package a.v1;
+
+import "a/v1/c.proto";
+
+message B {
+  Original original = 1;
+}
+`)
+	writeFile("a/v1/c.proto", `syntax = "proto3";
+
+package a.v1;
+
+import "a/v1/a.proto";
+
+message Original {
+  A a = 1;
+}
+`)
+
+	client := newTestServer(t)
+	aURI := openTestFile(t, client, filepath.Join(dirPath, "a", "v1", "a.proto"))
+	cURI := uri.File(filepath.Join(dirPath, "a", "v1", "c.proto"))
+	locations, err := client.References(context.Background(), &protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: aURI},
+			Position:     protocol.Position{Line: 4, Character: 8},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []protocol.Location{{URI: cURI, Range: newTestRange(7, 2, 7, 3)}}, locations)
+
+	// The files indexed for the first query are released, so the second query reads
+	// the edits from disk, even though c.proto is no longer imported by any file.
+	writeFile("a/v1/b.proto", `syntax = "proto3";
+
+package a.v1;
+
+message B {}
+`)
+	writeFile("a/v1/c.proto", `syntax = "proto3";
+
+package a.v1;
+
+import "a/v1/a.proto";
+
+message Renamed {
+  A a = 1;
+}
+`)
+	// symbolLocations returns the locations of the workspace symbols with the given name.
+	symbolLocations := func(name string) []protocol.Location {
+		symbols, err := client.Symbols(context.Background(), &protocol.WorkspaceSymbolParams{Query: name})
+		require.NoError(t, err)
+		var locations []protocol.Location
+		for _, symbol := range symbols {
+			if symbol.Name == name {
+				locations = append(locations, symbol.Location)
+			}
+		}
+		return locations
+	}
+	assert.Empty(t, symbolLocations("Original"))
+	assert.Equal(t, []protocol.Location{{URI: cURI, Range: newTestRange(6, 8, 6, 15)}}, symbolLocations("Renamed"))
+}
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	client := newTestServer(t)
+	fooURI := openTestFile(t, client, filepath.Join("testdata", "workspace", "foo", "v1", "foo.proto"))
+	bazURI := openTestFile(t, client, filepath.Join("testdata", "workspace", "foo", "v1", "baz.proto"))
+
+	// Renaming Foo edits its definition and the references to it from the other file,
+	// including the Foo component of qualified references to Foo.Bar.
+	expectedEdits := map[protocol.DocumentURI][]protocol.TextEdit{
+		fooURI: {
+			{Range: newTestRange(6, 8, 6, 11), NewText: "Qux"},
+		},
+		bazURI: {
+			{Range: newTestRange(7, 2, 7, 5), NewText: "Qux"},
+			{Range: newTestRange(8, 9, 8, 12), NewText: "Qux"},
+			{Range: newTestRange(9, 10, 9, 13), NewText: "Qux"},
+		},
+	}
+	for _, position := range []protocol.TextDocumentPositionParams{
+		// The definition of Foo.
+		{TextDocument: protocol.TextDocumentIdentifier{URI: fooURI}, Position: protocol.Position{Line: 6, Character: 9}},
+		// The fully-qualified reference foo.v1.Foo from the other file.
+		{TextDocument: protocol.TextDocumentIdentifier{URI: bazURI}, Position: protocol.Position{Line: 8, Character: 10}},
+	} {
+		range_, err := client.PrepareRename(context.Background(), &protocol.PrepareRenameParams{
+			TextDocumentPositionParams: position,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, range_)
+		if position.TextDocument.URI == fooURI {
+			assert.Equal(t, newTestRange(6, 8, 6, 11), *range_)
+		} else {
+			assert.Equal(t, newTestRange(8, 9, 8, 12), *range_)
+		}
+		workspaceEdit, err := client.Rename(context.Background(), &protocol.RenameParams{
+			TextDocumentPositionParams: position,
+			NewName:                    "Qux",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, workspaceEdit)
+		require.Len(t, workspaceEdit.Changes, len(expectedEdits))
+		for uri, edits := range expectedEdits {
+			assert.ElementsMatch(t, edits, workspaceEdit.Changes[uri])
+		}
+	}
+
+	// Well-known types may not be renamed.
+	timestamp := protocol.TextDocumentPositionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: fooURI},
+		Position:     protocol.Position{Line: 10, Character: 20},
+	}
+	_, err := client.PrepareRename(context.Background(), &protocol.PrepareRenameParams{
+		TextDocumentPositionParams: timestamp,
+	})
+	assert.ErrorContains(t, err, "cannot rename Timestamp: it is not defined in a local file")
+	_, err = client.Rename(context.Background(), &protocol.RenameParams{
+		TextDocumentPositionParams: timestamp,
+		NewName:                    "Time",
+	})
+	assert.ErrorContains(t, err, "cannot rename Timestamp: it is not defined in a local file")
+}
+
+func TestCompletionPublicImports(t *testing.T) {
+	t.Parallel()
+
+	client := newTestServer(t)
+	usesPublicURI := openTestFile(t, client, filepath.Join("testdata", "workspace", "foo", "v1", "uses_public.proto"))
+
+	// The cursor is on the empty line in the body of UsesPublic.
+	list, err := client.Completion(context.Background(), &protocol.CompletionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: usesPublicURI},
+			Position:     protocol.Position{Line: 7, Character: 2},
+		},
+	})
+	require.NoError(t, err)
+	labels := make(map[string]bool)
+	for _, item := range list.Items {
+		labels[item.Label] = true
+	}
+	// Types from the imported file, and from the files it imports publicly, directly or
+	// through another public import, are visible.
+	assert.True(t, labels["Public"])
+	assert.True(t, labels["PublicInner"])
+	assert.True(t, labels["PublicInnermost"])
+	// Types from files that the imported file imports, but not publicly, are not.
+	assert.False(t, labels["Baz"])
+}
+
+func newTestRange(startLine, startCharacter, endLine, endCharacter uint32) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{Line: startLine, Character: startCharacter},
+		End:   protocol.Position{Line: endLine, Character: endCharacter},
+	}
+}
+
+// applyTextEdits applies the edits to text, as an LSP client would.
+func applyTextEdits(text string, edits []protocol.TextEdit) string {
+	// The edits do not overlap, so they are applied from last to first for the positions of
+	// the edits that remain to be applied to stay valid.
+	edits = slices.Clone(edits)
+	slices.SortFunc(edits, func(a, b protocol.TextEdit) int {
+		return comparePositions(b.Range.Start, a.Range.Start)
+	})
+	for _, edit := range edits {
+		start := positionToOffset(text, edit.Range.Start)
+		end := positionToOffset(text, edit.Range.End)
+		text = text[:start] + edit.NewText + text[end:]
+	}
+	return text
+}
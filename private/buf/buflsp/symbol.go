@@ -22,6 +22,7 @@ package buflsp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -134,6 +135,129 @@ func (s *symbol) Definition(ctx context.Context) (*symbol, ast.Node) {
 	return nil, nil
 }
 
+// DefinitionPath returns the URI of the file that defines this symbol and the path of its
+// definition within that file.
+//
+// Returns false if the definition of this symbol is not known.
+func (s *symbol) DefinitionPath(ctx context.Context) (protocol.URI, []string, bool) {
+	def, _ := s.Definition(ctx)
+	if def == nil {
+		return "", nil, false
+	}
+	kind, ok := def.kind.(*definition)
+	if !ok {
+		return "", nil, false
+	}
+	return def.file.uri, kind.path, true
+}
+
+// RPCMessageTypes returns the definitions of the request and response messages of the
+// RPC this symbol refers to, in that order.
+//
+// Returns nil if this symbol does not refer to an RPC. Types that cannot be resolved
+// are omitted.
+func (s *symbol) RPCMessageTypes(ctx context.Context) []*symbol {
+	def, node := s.Definition(ctx)
+	rpc, ok := node.(*ast.RPCNode)
+	if !ok {
+		return nil
+	}
+
+	var types []*symbol
+	for _, name := range []ast.Node{rpc.Input.MessageType, rpc.Output.MessageType} {
+		for _, symbol := range def.file.symbols {
+			if symbol.name != name {
+				continue
+			}
+			if typeDef, _ := symbol.Definition(ctx); typeDef != nil {
+				types = append(types, typeDef)
+			}
+			break
+		}
+	}
+	return types
+}
+
+// Refers returns whether this symbol is either the definition of, or a reference to, the
+// symbol with the given path defined in the file with the given URI.
+//
+// Definitions are compared by URI and path rather than by pointer, because re-indexing
+// a file replaces all of its symbols.
+func (s *symbol) Refers(uri protocol.URI, path []string) bool {
+	switch kind := s.kind.(type) {
+	case *definition:
+		return s.file.uri == uri && slices.Equal(kind.path, path)
+	case *reference:
+		return kind.file != nil && kind.file.uri == uri && slices.Equal(kind.path, path)
+	}
+	return false
+}
+
+// CheckRenamable returns an error if this symbol cannot be renamed, e.g. because it is a
+// builtin or because it is defined in a file we may not edit, such as a remote dependency
+// or a well-known type.
+func (s *symbol) CheckRenamable(ctx context.Context) error {
+	def, _ := s.Definition(ctx)
+	_, path, ok := s.DefinitionPath(ctx)
+	if !ok {
+		return errors.New("cannot rename: could not resolve symbol")
+	}
+	if def.file.IsWKT() || !def.file.IsLocal() {
+		return fmt.Errorf("cannot rename %s: it is not defined in a local file", strings.Join(path, "."))
+	}
+	return nil
+}
+
+// RenameRange returns the range of the part of this symbol's name that spells out the
+// name of the definition with the given path in the file with the given URI.
+//
+// This is not necessarily the whole symbol: for example, when renaming the message Foo,
+// only the first component of the reference Foo.Bar needs to be edited.
+//
+// Returns false if this symbol's name does not spell out that definition.
+func (s *symbol) RenameRange(uri protocol.URI, path []string) (protocol.Range, bool) {
+	var refPath []string
+	switch kind := s.kind.(type) {
+	case *definition:
+		if s.file.uri != uri || !slices.Equal(kind.path, path) {
+			return protocol.Range{}, false
+		}
+		return s.Range(), true
+	case *reference:
+		if kind.file == nil || kind.file.uri != uri {
+			return protocol.Range{}, false
+		}
+		refPath = kind.path
+	default:
+		return protocol.Range{}, false
+	}
+
+	if len(path) == 0 {
+		return protocol.Range{}, false
+	}
+	if _, ok := xslices.TrimPrefix(refPath, path); !ok {
+		return protocol.Range{}, false
+	}
+
+	var components []*ast.IdentNode
+	switch name := s.name.(type) {
+	case *ast.IdentNode:
+		components = []*ast.IdentNode{name}
+	case *ast.CompoundIdentNode:
+		components = name.Components
+	default:
+		return protocol.Range{}, false
+	}
+
+	// The name as written is a suffix of the fully-qualified name, so the component
+	// we want sits the same distance from the end as it does in refPath.
+	idx := len(components) - (len(refPath) - len(path)) - 1
+	if idx < 0 || components[idx].Val != path[len(path)-1] {
+		return protocol.Range{}, false
+	}
+	return infoToRange(s.file.fileNode.NodeInfo(components[idx])), true
+}
+
 // ReferencePath returns the reference path of this string, i.e., the components of
 // a path like foo.bar.Baz.
 //
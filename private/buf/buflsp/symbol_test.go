@@ -20,9 +20,16 @@
 package buflsp
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/bufbuild/buf/private/pkg/storage/storageutil"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
 )
 
 func TestCommentToMarkdown(t *testing.T) {
@@ -98,3 +105,35 @@ func TestCommentToMarkdown(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckRenamable(t *testing.T) {
+	t.Parallel()
+
+	const text = `syntax = "proto3";
+
+package dep.v1;
+
+message Dep {}
+`
+	fileNode, err := parser.Parse("dep/v1/dep.proto", strings.NewReader(text), reporter.NewHandler(nil))
+	require.NoError(t, err)
+	protoFile := &file{uri: uri.File("/dep/v1/dep.proto"), text: text, fileNode: fileNode}
+	walker := newWalker(protoFile)
+	walker.Walk(fileNode, fileNode)
+	protoFile.symbols = walker.symbols
+	var dep *symbol
+	for _, symbol := range protoFile.symbols {
+		if def, ok := symbol.kind.(*definition); ok && strings.Join(def.path, ".") == "Dep" {
+			dep = symbol
+		}
+	}
+	require.NotNil(t, dep)
+
+	// A file the editor is editing.
+	protoFile.objectInfo = storageutil.NewObjectInfo("dep/v1/dep.proto", "/dep/v1/dep.proto", "/dep/v1/dep.proto")
+	assert.NoError(t, dep.CheckRenamable(context.Background()))
+
+	// A file from a dependency of the module, which has been fetched into the cache.
+	protoFile.objectInfo = storageutil.NewObjectInfo("dep/v1/dep.proto", "buf.build/acme/dep/dep/v1/dep.proto", "/cache/dep/v1/dep.proto")
+	assert.ErrorContains(t, dep.CheckRenamable(context.Background()), "cannot rename Dep: it is not defined in a local file")
+}
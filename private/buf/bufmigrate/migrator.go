@@ -726,6 +726,7 @@ func equivalentCheckConfigInV2(
 		undeprecateSlice(checkConfig.ExceptIDsAndCategories(), deprecations),
 		checkConfig.IgnorePaths(),
 		undeprecateMap(checkConfig.IgnoreIDOrCategoryToPaths(), deprecations),
+		undeprecateMap(checkConfig.IDOrCategoryToSeverity(), deprecations),
 		checkConfig.DisableBuiltin(),
 	)
 	if err != nil {
@@ -793,6 +794,7 @@ func equivalentCheckConfigInV2(
 		exceptIDsAndCategories,
 		simplyTranslatedCheckConfig.IgnorePaths(),
 		simplyTranslatedCheckConfig.IgnoreIDOrCategoryToPaths(),
+		simplyTranslatedCheckConfig.IDOrCategoryToSeverity(),
 		simplyTranslatedCheckConfig.DisableBuiltin(),
 	)
 }
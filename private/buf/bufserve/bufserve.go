@@ -0,0 +1,321 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufserve serves the services of an image with canned responses.
+package bufserve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	connect "connectrpc.com/connect"
+	"connectrpc.com/grpcreflect"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/rs/cors"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// NewHandler returns a new http.Handler that serves every service defined in
+// the non-import files of the image over the Connect, gRPC, and gRPC-Web
+// protocols.
+//
+// Every RPC responds with the responses from its fixture if a fixture
+// directory is given and a fixture exists for the method, and with a
+// default-valued message otherwise. gRPC server reflection is also served
+// for all files in the image.
+func NewHandler(
+	logger *slog.Logger,
+	image bufimage.Image,
+	options ...HandlerOption,
+) (http.Handler, error) {
+	handlerOptions := newHandlerOptions()
+	for _, option := range options {
+		option(handlerOptions)
+	}
+	files, err := protodesc.NewFiles(bufimage.ImageToFileDescriptorSet(image))
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := protoencoding.NewResolver(bufimage.ImageToFileDescriptorProtos(image)...)
+	if err != nil {
+		return nil, err
+	}
+	connectOptions := []connect.HandlerOption{
+		connect.WithCodec(newJSONCodec("json", resolver)),
+		connect.WithCodec(newJSONCodec("json; charset=utf-8", resolver)),
+		connect.WithRequestInitializer(initializeRequest),
+	}
+	mux := http.NewServeMux()
+	var serviceNames []string
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileDescriptor, err := files.FindFileByPath(imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		services := fileDescriptor.Services()
+		for i := range services.Len() {
+			serviceDescriptor := services.Get(i)
+			serviceNames = append(serviceNames, string(serviceDescriptor.FullName()))
+			methods := serviceDescriptor.Methods()
+			for j := range methods.Len() {
+				methodDescriptor := methods.Get(j)
+				procedure := "/" + string(serviceDescriptor.FullName()) + "/" + string(methodDescriptor.Name())
+				methodHandler := &methodHandler{
+					logger:           logger,
+					methodDescriptor: methodDescriptor,
+					resolver:         resolver,
+					fixtureDirPath:   handlerOptions.fixtureDirPath,
+				}
+				mux.Handle(procedure, methodHandler.newHandler(procedure, connectOptions...))
+				logger.Debug("serving", slog.String("procedure", procedure))
+			}
+		}
+	}
+	if len(serviceNames) == 0 {
+		return nil, errors.New("no services found in input")
+	}
+	reflector := grpcreflect.NewReflector(
+		grpcreflect.NamerFunc(func() []string { return serviceNames }),
+		grpcreflect.WithDescriptorResolver(files),
+		grpcreflect.WithExtensionResolver(newExtensionResolver(files)),
+	)
+	mux.Handle(grpcreflect.NewHandlerV1(reflector))
+	mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector))
+	if len(handlerOptions.corsAllowedOrigins) == 0 {
+		return mux, nil
+	}
+	return cors.New(
+		cors.Options{
+			AllowedOrigins: handlerOptions.corsAllowedOrigins,
+			AllowedMethods: []string{http.MethodGet, http.MethodPost},
+			AllowedHeaders: []string{"*"},
+			ExposedHeaders: []string{
+				"Grpc-Status",
+				"Grpc-Message",
+				"Grpc-Status-Details-Bin",
+			},
+		},
+	).Handler(mux), nil
+}
+
+// HandlerOption is an option for a new Handler.
+type HandlerOption func(*handlerOptions)
+
+// HandlerWithFixtureDirPath returns a new HandlerOption that reads canned
+// responses from the given directory.
+//
+// The fixture for a method is read from <dirPath>/<package.Service>/<Method>.{yaml,yml,json}
+// on every call, so fixtures can be edited while serving. A fixture contains
+// either a single response message, or a list of response messages for methods
+// that stream responses.
+func HandlerWithFixtureDirPath(dirPath string) HandlerOption {
+	return func(handlerOptions *handlerOptions) {
+		handlerOptions.fixtureDirPath = dirPath
+	}
+}
+
+// HandlerWithCORSAllowedOrigins returns a new HandlerOption that allows
+// cross-origin requests from the given origins, such as from a web
+// application served by a development server.
+func HandlerWithCORSAllowedOrigins(origins ...string) HandlerOption {
+	return func(handlerOptions *handlerOptions) {
+		handlerOptions.corsAllowedOrigins = append(handlerOptions.corsAllowedOrigins, origins...)
+	}
+}
+
+// *** PRIVATE ***
+
+type handlerOptions struct {
+	fixtureDirPath     string
+	corsAllowedOrigins []string
+}
+
+func newHandlerOptions() *handlerOptions {
+	return &handlerOptions{}
+}
+
+type methodHandler struct {
+	logger           *slog.Logger
+	methodDescriptor protoreflect.MethodDescriptor
+	resolver         protoencoding.Resolver
+	fixtureDirPath   string
+}
+
+func (m *methodHandler) newHandler(procedure string, options ...connect.HandlerOption) http.Handler {
+	options = append(options, connect.WithSchema(m.methodDescriptor))
+	switch {
+	case m.methodDescriptor.IsStreamingClient() && m.methodDescriptor.IsStreamingServer():
+		return connect.NewBidiStreamHandler(procedure, m.handleBidiStream, options...)
+	case m.methodDescriptor.IsStreamingClient():
+		return connect.NewClientStreamHandler(procedure, m.handleClientStream, options...)
+	case m.methodDescriptor.IsStreamingServer():
+		return connect.NewServerStreamHandler(procedure, m.handleServerStream, options...)
+	default:
+		return connect.NewUnaryHandler(procedure, m.handleUnary, options...)
+	}
+}
+
+func (m *methodHandler) handleUnary(
+	_ context.Context,
+	_ *connect.Request[dynamicpb.Message],
+) (*connect.Response[dynamicpb.Message], error) {
+	responses, err := m.getResponses()
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(responses[0]), nil
+}
+
+func (m *methodHandler) handleClientStream(
+	_ context.Context,
+	stream *connect.ClientStream[dynamicpb.Message],
+) (*connect.Response[dynamicpb.Message], error) {
+	for stream.Receive() {
+		// Requests are ignored, drain the stream.
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	responses, err := m.getResponses()
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(responses[0]), nil
+}
+
+func (m *methodHandler) handleServerStream(
+	_ context.Context,
+	_ *connect.Request[dynamicpb.Message],
+	stream *connect.ServerStream[dynamicpb.Message],
+) error {
+	responses, err := m.getResponses()
+	if err != nil {
+		return err
+	}
+	for _, response := range responses {
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *methodHandler) handleBidiStream(
+	_ context.Context,
+	stream *connect.BidiStream[dynamicpb.Message, dynamicpb.Message],
+) error {
+	responses, err := m.getResponses()
+	if err != nil {
+		return err
+	}
+	for _, response := range responses {
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+	for {
+		if _, err := stream.Receive(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// getResponses returns the responses from the fixture for the method, or a
+// single default-valued response if there is no fixture.
+func (m *methodHandler) getResponses() ([]*dynamicpb.Message, error) {
+	responses, err := readFixture(m.fixtureDirPath, m.methodDescriptor, m.resolver)
+	if err != nil {
+		m.logger.Error("failed to read fixture", slog.String("method", string(m.methodDescriptor.FullName())), slog.Any("error", err))
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if responses == nil {
+		return []*dynamicpb.Message{dynamicpb.NewMessage(m.methodDescriptor.Output())}, nil
+	}
+	return responses, nil
+}
+
+func initializeRequest(spec connect.Spec, message any) error {
+	dynamicMessage, ok := message.(*dynamicpb.Message)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", message)
+	}
+	methodDescriptor, ok := spec.Schema.(protoreflect.MethodDescriptor)
+	if !ok {
+		return fmt.Errorf("unexpected schema type %T", spec.Schema)
+	}
+	*dynamicMessage = *dynamicpb.NewMessage(methodDescriptor.Input())
+	return nil
+}
+
+// extensionResolver is a grpcreflect.ExtensionResolver for the extensions
+// defined in a set of files.
+type extensionResolver struct {
+	*dynamicpb.Types
+
+	files *protoregistry.Files
+}
+
+func newExtensionResolver(files *protoregistry.Files) *extensionResolver {
+	return &extensionResolver{
+		Types: dynamicpb.NewTypes(files),
+		files: files,
+	}
+}
+
+func (e *extensionResolver) RangeExtensionsByMessage(
+	message protoreflect.FullName,
+	f func(protoreflect.ExtensionType) bool,
+) {
+	e.files.RangeFiles(func(fileDescriptor protoreflect.FileDescriptor) bool {
+		return rangeExtensions(fileDescriptor.Extensions(), fileDescriptor.Messages(), message, f)
+	})
+}
+
+func rangeExtensions(
+	extensionDescriptors protoreflect.ExtensionDescriptors,
+	messageDescriptors protoreflect.MessageDescriptors,
+	message protoreflect.FullName,
+	f func(protoreflect.ExtensionType) bool,
+) bool {
+	for i := range extensionDescriptors.Len() {
+		extensionDescriptor := extensionDescriptors.Get(i)
+		if extensionDescriptor.ContainingMessage().FullName() != message {
+			continue
+		}
+		if !f(dynamicpb.NewExtensionType(extensionDescriptor)) {
+			return false
+		}
+	}
+	for i := range messageDescriptors.Len() {
+		messageDescriptor := messageDescriptors.Get(i)
+		if !rangeExtensions(messageDescriptor.Extensions(), messageDescriptor.Messages(), message, f) {
+			return false
+		}
+	}
+	return true
+}
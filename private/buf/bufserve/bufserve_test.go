@@ -0,0 +1,236 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufserve
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpcreflect"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	fileDescriptorProto := newTestFileDescriptorProto()
+	fileDescriptor, err := protodesc.NewFile(fileDescriptorProto, nil)
+	require.NoError(t, err)
+	serviceDescriptor := fileDescriptor.Services().ByName("PingService")
+	imageFile, err := bufimage.NewImageFile(fileDescriptorProto, nil, uuid.Nil, "", "", false, false, nil)
+	require.NoError(t, err)
+	image, err := bufimage.NewImage([]bufimage.ImageFile{imageFile})
+	require.NoError(t, err)
+	fixtureDirPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(fixtureDirPath, "foo.v1.PingService"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(fixtureDirPath, "foo.v1.PingService", "Ping.json"),
+		[]byte(`{"text": "pong"}`),
+		0600,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(fixtureDirPath, "foo.v1.PingService", "PingStream.yaml"),
+		[]byte("- text: one\n- text: two\n"),
+		0600,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(fixtureDirPath, "foo.v1.PingService", "PingInvalid.yaml"),
+		[]byte("- text: one\n- text: two\n"),
+		0600,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(fixtureDirPath, "foo.v1.PingService", "PingEmpty.json"),
+		[]byte("[]"),
+		0600,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(fixtureDirPath, "foo.v1.PingService", "PingEmptyStream.yaml"),
+		[]byte("[]\n"),
+		0600,
+	))
+	handler, err := NewHandler(
+		slogtestext.NewLogger(t),
+		image,
+		HandlerWithFixtureDirPath(fixtureDirPath),
+	)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(handler)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	ctx := context.Background()
+
+	t.Run("fixture", func(t *testing.T) {
+		t.Parallel()
+		for _, clientOption := range []connect.ClientOption{
+			connect.WithProtoJSON(),
+			connect.WithGRPC(),
+			connect.WithGRPCWeb(),
+		} {
+			client := newTestClient(server, serviceDescriptor.Methods().ByName("Ping"), clientOption)
+			response, err := client.CallUnary(ctx, connect.NewRequest(newTestRequest(serviceDescriptor)))
+			require.NoError(t, err)
+			assert.Equal(t, "pong", getText(response.Msg))
+		}
+	})
+	t.Run("fixture_stream", func(t *testing.T) {
+		t.Parallel()
+		client := newTestClient(server, serviceDescriptor.Methods().ByName("PingStream"))
+		stream, err := client.CallServerStream(ctx, connect.NewRequest(newTestRequest(serviceDescriptor)))
+		require.NoError(t, err)
+		var texts []string
+		for stream.Receive() {
+			texts = append(texts, getText(stream.Msg()))
+		}
+		require.NoError(t, stream.Err())
+		assert.Equal(t, []string{"one", "two"}, texts)
+	})
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		client := newTestClient(server, serviceDescriptor.Methods().ByName("PingDefault"))
+		response, err := client.CallUnary(ctx, connect.NewRequest(newTestRequest(serviceDescriptor)))
+		require.NoError(t, err)
+		assert.Empty(t, getText(response.Msg))
+	})
+	t.Run("invalid_fixture", func(t *testing.T) {
+		t.Parallel()
+		client := newTestClient(server, serviceDescriptor.Methods().ByName("PingInvalid"))
+		_, err := client.CallUnary(ctx, connect.NewRequest(newTestRequest(serviceDescriptor)))
+		connectErr := &connect.Error{}
+		require.True(t, errors.As(err, &connectErr))
+		assert.Equal(t, connect.CodeInternal, connectErr.Code())
+	})
+	t.Run("empty_fixture", func(t *testing.T) {
+		t.Parallel()
+		client := newTestClient(server, serviceDescriptor.Methods().ByName("PingEmpty"))
+		_, err := client.CallUnary(ctx, connect.NewRequest(newTestRequest(serviceDescriptor)))
+		connectErr := &connect.Error{}
+		require.True(t, errors.As(err, &connectErr))
+		assert.Equal(t, connect.CodeInternal, connectErr.Code())
+		assert.Contains(t, connectErr.Message(), "0 responses were given")
+	})
+	t.Run("empty_fixture_stream", func(t *testing.T) {
+		t.Parallel()
+		client := newTestClient(server, serviceDescriptor.Methods().ByName("PingEmptyStream"))
+		stream, err := client.CallServerStream(ctx, connect.NewRequest(newTestRequest(serviceDescriptor)))
+		require.NoError(t, err)
+		assert.False(t, stream.Receive())
+		require.NoError(t, stream.Err())
+	})
+	t.Run("reflection", func(t *testing.T) {
+		t.Parallel()
+		stream := grpcreflect.NewClient(server.Client(), server.URL).NewStream(ctx)
+		t.Cleanup(func() { _, _ = stream.Close() })
+		serviceNames, err := stream.ListServices()
+		require.NoError(t, err)
+		assert.Equal(t, []protoreflect.FullName{"foo.v1.PingService"}, serviceNames)
+		fileDescriptorProtos, err := stream.FileContainingSymbol("foo.v1.PingService")
+		require.NoError(t, err)
+		require.Len(t, fileDescriptorProtos, 1)
+		assert.Equal(t, fileDescriptorProto.GetName(), fileDescriptorProtos[0].GetName())
+		assert.True(t, proto.Equal(fileDescriptorProto.GetService()[0], fileDescriptorProtos[0].GetService()[0]))
+	})
+}
+
+func newTestClient(
+	server *httptest.Server,
+	methodDescriptor protoreflect.MethodDescriptor,
+	options ...connect.ClientOption,
+) *connect.Client[dynamicpb.Message, dynamicpb.Message] {
+	options = append(
+		options,
+		connect.WithSchema(methodDescriptor),
+		connect.WithResponseInitializer(func(_ connect.Spec, message any) error {
+			*message.(*dynamicpb.Message) = *dynamicpb.NewMessage(methodDescriptor.Output())
+			return nil
+		}),
+	)
+	return connect.NewClient[dynamicpb.Message, dynamicpb.Message](
+		server.Client(),
+		server.URL+"/"+string(methodDescriptor.Parent().FullName())+"/"+string(methodDescriptor.Name()),
+		options...,
+	)
+}
+
+func newTestRequest(serviceDescriptor protoreflect.ServiceDescriptor) *dynamicpb.Message {
+	return dynamicpb.NewMessage(serviceDescriptor.Methods().Get(0).Input())
+}
+
+func getText(message *dynamicpb.Message) string {
+	return message.Get(message.Descriptor().Fields().ByName("text")).String()
+}
+
+func newTestFileDescriptorProto() *descriptorpb.FileDescriptorProto {
+	newMethod := func(name string, serverStreaming bool) *descriptorpb.MethodDescriptorProto {
+		methodDescriptorProto := &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".foo.v1.PingRequest"),
+			OutputType: proto.String(".foo.v1.PingResponse"),
+		}
+		if serverStreaming {
+			methodDescriptorProto.ServerStreaming = proto.Bool(true)
+		}
+		return methodDescriptorProto
+	}
+	newMessage := func(name string) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{
+			Name: proto.String(name),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:     proto.String("text"),
+					JsonName: proto.String("text"),
+					Number:   proto.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				},
+			},
+		}
+	}
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("foo/v1/ping.proto"),
+		Package: proto.String("foo.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			newMessage("PingRequest"),
+			newMessage("PingResponse"),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("PingService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					newMethod("Ping", false),
+					newMethod("PingStream", true),
+					newMethod("PingDefault", false),
+					newMethod("PingInvalid", false),
+					newMethod("PingEmpty", false),
+					newMethod("PingEmptyStream", true),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureFileExtensions are the file extensions of fixture files, in the
+// order they are checked.
+var fixtureFileExtensions = []string{
+	".yaml",
+	".yml",
+	".json",
+}
+
+// readFixture reads the responses for the method from the fixture directory.
+//
+// The fixture for a method is read from <dirPath>/<package.Service>/<Method>.{yaml,yml,json}.
+// The file contains either a single response message, or a list of response
+// messages for methods that stream responses. Methods that do not stream
+// responses must be given exactly one response.
+//
+// Returns nil if there is no fixture for the method.
+func readFixture(
+	dirPath string,
+	methodDescriptor protoreflect.MethodDescriptor,
+	resolver protoencoding.Resolver,
+) ([]*dynamicpb.Message, error) {
+	if dirPath == "" {
+		return nil, nil
+	}
+	basePath := filepath.Join(
+		dirPath,
+		string(methodDescriptor.Parent().FullName()),
+		string(methodDescriptor.Name()),
+	)
+	for _, fileExtension := range fixtureFileExtensions {
+		filePath := basePath + fileExtension
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		var responses []*dynamicpb.Message
+		if fileExtension == ".json" {
+			responses, err = parseJSONFixture(data, methodDescriptor.Output(), resolver)
+		} else {
+			responses, err = parseYAMLFixture(filePath, data, methodDescriptor.Output(), resolver)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", filePath, err)
+		}
+		if len(responses) != 1 && !methodDescriptor.IsStreamingServer() {
+			return nil, fmt.Errorf("invalid fixture %s: %s does not stream responses but %d responses were given", filePath, methodDescriptor.FullName(), len(responses))
+		}
+		return responses, nil
+	}
+	return nil, nil
+}
+
+func parseJSONFixture(
+	data []byte,
+	messageDescriptor protoreflect.MessageDescriptor,
+	resolver protoencoding.Resolver,
+) ([]*dynamicpb.Message, error) {
+	unmarshaler := protoencoding.NewJSONUnmarshaler(resolver)
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		message := dynamicpb.NewMessage(messageDescriptor)
+		if err := unmarshaler.Unmarshal(data, message); err != nil {
+			return nil, err
+		}
+		return []*dynamicpb.Message{message}, nil
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, err
+	}
+	messages := make([]*dynamicpb.Message, len(elements))
+	for i, element := range elements {
+		message := dynamicpb.NewMessage(messageDescriptor)
+		if err := unmarshaler.Unmarshal(element, message); err != nil {
+			return nil, err
+		}
+		messages[i] = message
+	}
+	return messages, nil
+}
+
+func parseYAMLFixture(
+	filePath string,
+	data []byte,
+	messageDescriptor protoreflect.MessageDescriptor,
+	resolver protoencoding.Resolver,
+) ([]*dynamicpb.Message, error) {
+	unmarshaler := protoencoding.NewYAMLUnmarshaler(resolver, protoencoding.YAMLUnmarshalerWithPath(filePath))
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.SequenceNode {
+		message := dynamicpb.NewMessage(messageDescriptor)
+		if err := unmarshaler.Unmarshal(data, message); err != nil {
+			return nil, err
+		}
+		return []*dynamicpb.Message{message}, nil
+	}
+	elements := document.Content[0].Content
+	messages := make([]*dynamicpb.Message, len(elements))
+	for i, element := range elements {
+		elementData, err := yaml.Marshal(element)
+		if err != nil {
+			return nil, err
+		}
+		message := dynamicpb.NewMessage(messageDescriptor)
+		if err := unmarshaler.Unmarshal(elementData, message); err != nil {
+			return nil, err
+		}
+		messages[i] = message
+	}
+	return messages, nil
+}
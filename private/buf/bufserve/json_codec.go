@@ -0,0 +1,59 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufserve
+
+import (
+	"fmt"
+
+	connect "connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/protobuf/proto"
+)
+
+// jsonCodec is a connect.Codec for JSON that resolves google.protobuf.Any
+// and extensions against the served image instead of the global registry.
+type jsonCodec struct {
+	name        string
+	marshaler   protoencoding.Marshaler
+	unmarshaler protoencoding.Unmarshaler
+}
+
+var _ connect.Codec = (*jsonCodec)(nil)
+
+func newJSONCodec(name string, resolver protoencoding.Resolver) *jsonCodec {
+	return &jsonCodec{
+		name:        name,
+		marshaler:   protoencoding.NewJSONMarshaler(resolver),
+		unmarshaler: protoencoding.NewJSONUnmarshaler(resolver),
+	}
+}
+
+func (j *jsonCodec) Name() string { return j.name }
+
+func (j *jsonCodec) Marshal(src any) ([]byte, error) {
+	message, ok := src.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("marshal unexpected type %T", src)
+	}
+	return j.marshaler.Marshal(message)
+}
+
+func (j *jsonCodec) Unmarshal(src []byte, dst any) error {
+	message, ok := dst.(proto.Message)
+	if !ok {
+		return fmt.Errorf("unmarshal unexpected type %T", dst)
+	}
+	return j.unmarshaler.Unmarshal(src, message)
+}
@@ -909,52 +909,61 @@ ENUM_FIRST_VALUE_ZERO             OTHER
 func TestCheckLsLintRulesV2(t *testing.T) {
 	t.Parallel()
 	expectedStdout := `
-ID                                 CATEGORIES                DEFAULT  PURPOSE
-DIRECTORY_SAME_PACKAGE             MINIMAL, BASIC, STANDARD  *        Checks that all files in a given directory are in the same package.
-PACKAGE_DEFINED                    MINIMAL, BASIC, STANDARD  *        Checks that all files have a package defined.
-PACKAGE_DIRECTORY_MATCH            MINIMAL, BASIC, STANDARD  *        Checks that all files are in a directory that matches their package name.
-PACKAGE_NO_IMPORT_CYCLE            MINIMAL, BASIC, STANDARD  *        Checks that packages do not have import cycles.
-PACKAGE_SAME_DIRECTORY             MINIMAL, BASIC, STANDARD  *        Checks that all files with a given package are in the same directory.
-ENUM_FIRST_VALUE_ZERO              BASIC, STANDARD           *        Checks that all first values of enums have a numeric value of 0.
-ENUM_NO_ALLOW_ALIAS                BASIC, STANDARD           *        Checks that enums do not have the allow_alias option set.
-ENUM_PASCAL_CASE                   BASIC, STANDARD           *        Checks that enums are PascalCase.
-ENUM_VALUE_UPPER_SNAKE_CASE        BASIC, STANDARD           *        Checks that enum values are UPPER_SNAKE_CASE.
-FIELD_LOWER_SNAKE_CASE             BASIC, STANDARD           *        Checks that field names are lower_snake_case.
-FIELD_NOT_REQUIRED                 BASIC, STANDARD           *        Checks that fields are not configured to be required.
-IMPORT_NO_PUBLIC                   BASIC, STANDARD           *        Checks that imports are not public.
-IMPORT_USED                        BASIC, STANDARD           *        Checks that imports are used.
-MESSAGE_PASCAL_CASE                BASIC, STANDARD           *        Checks that messages are PascalCase.
-ONEOF_LOWER_SNAKE_CASE             BASIC, STANDARD           *        Checks that oneof names are lower_snake_case.
-PACKAGE_LOWER_SNAKE_CASE           BASIC, STANDARD           *        Checks that packages are lower_snake.case.
-PACKAGE_SAME_CSHARP_NAMESPACE      BASIC, STANDARD           *        Checks that all files with a given package have the same value for the csharp_namespace option.
-PACKAGE_SAME_GO_PACKAGE            BASIC, STANDARD           *        Checks that all files with a given package have the same value for the go_package option.
-PACKAGE_SAME_JAVA_MULTIPLE_FILES   BASIC, STANDARD           *        Checks that all files with a given package have the same value for the java_multiple_files option.
-PACKAGE_SAME_JAVA_PACKAGE          BASIC, STANDARD           *        Checks that all files with a given package have the same value for the java_package option.
-PACKAGE_SAME_PHP_NAMESPACE         BASIC, STANDARD           *        Checks that all files with a given package have the same value for the php_namespace option.
-PACKAGE_SAME_RUBY_PACKAGE          BASIC, STANDARD           *        Checks that all files with a given package have the same value for the ruby_package option.
-PACKAGE_SAME_SWIFT_PREFIX          BASIC, STANDARD           *        Checks that all files with a given package have the same value for the swift_prefix option.
-RPC_PASCAL_CASE                    BASIC, STANDARD           *        Checks that RPCs are PascalCase.
-SERVICE_PASCAL_CASE                BASIC, STANDARD           *        Checks that services are PascalCase.
-SYNTAX_SPECIFIED                   BASIC, STANDARD           *        Checks that all files have a syntax specified.
-ENUM_VALUE_PREFIX                  STANDARD                  *        Checks that enum values are prefixed with ENUM_NAME_UPPER_SNAKE_CASE.
-ENUM_ZERO_VALUE_SUFFIX             STANDARD                  *        Checks that enum zero values have a consistent suffix (configurable, default suffix is "_UNSPECIFIED").
-FILE_LOWER_SNAKE_CASE              STANDARD                  *        Checks that filenames are lower_snake_case.
-PACKAGE_VERSION_SUFFIX             STANDARD                  *        Checks that the last component of all packages is a version of the form v\d+, v\d+test.*, v\d+(alpha|beta)\d+, or v\d+p\d+(alpha|beta)\d+, where numbers are >=1.
-PROTOVALIDATE                      STANDARD                  *        Checks that protovalidate rules are valid and all CEL expressions compile.
-RPC_REQUEST_RESPONSE_UNIQUE        STANDARD                  *        Checks that RPC request and response types are only used in one RPC (configurable).
-RPC_REQUEST_STANDARD_NAME          STANDARD                  *        Checks that RPC request type names are RPCNameRequest or ServiceNameRPCNameRequest (configurable).
-RPC_RESPONSE_STANDARD_NAME         STANDARD                  *        Checks that RPC response type names are RPCNameResponse or ServiceNameRPCNameResponse (configurable).
-SERVICE_SUFFIX                     STANDARD                  *        Checks that services have a consistent suffix (configurable, default suffix is "Service").
-COMMENT_ENUM                       COMMENTS                           Checks that enums have non-empty comments.
-COMMENT_ENUM_VALUE                 COMMENTS                           Checks that enum values have non-empty comments.
-COMMENT_FIELD                      COMMENTS                           Checks that fields have non-empty comments.
-COMMENT_MESSAGE                    COMMENTS                           Checks that messages have non-empty comments.
-COMMENT_ONEOF                      COMMENTS                           Checks that oneofs have non-empty comments.
-COMMENT_RPC                        COMMENTS                           Checks that RPCs have non-empty comments.
-COMMENT_SERVICE                    COMMENTS                           Checks that services have non-empty comments.
-RPC_NO_CLIENT_STREAMING            UNARY_RPC                          Checks that RPCs are not client streaming.
-RPC_NO_SERVER_STREAMING            UNARY_RPC                          Checks that RPCs are not server streaming.
-STABLE_PACKAGE_NO_IMPORT_UNSTABLE                                     Checks that all files that have stable versioned packages do not import packages with unstable version packages.
+ID                                  CATEGORIES                DEFAULT  PURPOSE
+DIRECTORY_SAME_PACKAGE              MINIMAL, BASIC, STANDARD  *        Checks that all files in a given directory are in the same package.
+PACKAGE_DEFINED                     MINIMAL, BASIC, STANDARD  *        Checks that all files have a package defined.
+PACKAGE_DIRECTORY_MATCH             MINIMAL, BASIC, STANDARD  *        Checks that all files are in a directory that matches their package name.
+PACKAGE_NO_IMPORT_CYCLE             MINIMAL, BASIC, STANDARD  *        Checks that packages do not have import cycles.
+PACKAGE_SAME_DIRECTORY              MINIMAL, BASIC, STANDARD  *        Checks that all files with a given package are in the same directory.
+ENUM_FIRST_VALUE_ZERO               BASIC, STANDARD           *        Checks that all first values of enums have a numeric value of 0.
+ENUM_NO_ALLOW_ALIAS                 BASIC, STANDARD           *        Checks that enums do not have the allow_alias option set.
+ENUM_PASCAL_CASE                    BASIC, STANDARD           *        Checks that enums are PascalCase.
+ENUM_VALUE_UPPER_SNAKE_CASE         BASIC, STANDARD           *        Checks that enum values are UPPER_SNAKE_CASE.
+FIELD_LOWER_SNAKE_CASE              BASIC, STANDARD           *        Checks that field names are lower_snake_case.
+FIELD_NOT_REQUIRED                  BASIC, STANDARD           *        Checks that fields are not configured to be required.
+IMPORT_NO_PUBLIC                    BASIC, STANDARD           *        Checks that imports are not public.
+IMPORT_USED                         BASIC, STANDARD           *        Checks that imports are used.
+MESSAGE_PASCAL_CASE                 BASIC, STANDARD           *        Checks that messages are PascalCase.
+ONEOF_LOWER_SNAKE_CASE              BASIC, STANDARD           *        Checks that oneof names are lower_snake_case.
+PACKAGE_LOWER_SNAKE_CASE            BASIC, STANDARD           *        Checks that packages are lower_snake.case.
+PACKAGE_SAME_CSHARP_NAMESPACE       BASIC, STANDARD           *        Checks that all files with a given package have the same value for the csharp_namespace option.
+PACKAGE_SAME_GO_PACKAGE             BASIC, STANDARD           *        Checks that all files with a given package have the same value for the go_package option.
+PACKAGE_SAME_JAVA_MULTIPLE_FILES    BASIC, STANDARD           *        Checks that all files with a given package have the same value for the java_multiple_files option.
+PACKAGE_SAME_JAVA_PACKAGE           BASIC, STANDARD           *        Checks that all files with a given package have the same value for the java_package option.
+PACKAGE_SAME_PHP_NAMESPACE          BASIC, STANDARD           *        Checks that all files with a given package have the same value for the php_namespace option.
+PACKAGE_SAME_RUBY_PACKAGE           BASIC, STANDARD           *        Checks that all files with a given package have the same value for the ruby_package option.
+PACKAGE_SAME_SWIFT_PREFIX           BASIC, STANDARD           *        Checks that all files with a given package have the same value for the swift_prefix option.
+RPC_PASCAL_CASE                     BASIC, STANDARD           *        Checks that RPCs are PascalCase.
+SERVICE_PASCAL_CASE                 BASIC, STANDARD           *        Checks that services are PascalCase.
+SYNTAX_SPECIFIED                    BASIC, STANDARD           *        Checks that all files have a syntax specified.
+ENUM_VALUE_PREFIX                   STANDARD                  *        Checks that enum values are prefixed with ENUM_NAME_UPPER_SNAKE_CASE.
+ENUM_ZERO_VALUE_SUFFIX              STANDARD                  *        Checks that enum zero values have a consistent suffix (configurable, default suffix is "_UNSPECIFIED").
+FILE_LOWER_SNAKE_CASE               STANDARD                  *        Checks that filenames are lower_snake_case.
+PACKAGE_VERSION_SUFFIX              STANDARD                  *        Checks that the last component of all packages is a version of the form v\d+, v\d+test.*, v\d+(alpha|beta)\d+, or v\d+p\d+(alpha|beta)\d+, where numbers are >=1.
+PROTOVALIDATE                       STANDARD                  *        Checks that protovalidate rules are valid and all CEL expressions compile.
+RPC_REQUEST_RESPONSE_UNIQUE         STANDARD                  *        Checks that RPC request and response types are only used in one RPC (configurable).
+RPC_REQUEST_STANDARD_NAME           STANDARD                  *        Checks that RPC request type names are RPCNameRequest or ServiceNameRPCNameRequest (configurable).
+RPC_RESPONSE_STANDARD_NAME          STANDARD                  *        Checks that RPC response type names are RPCNameResponse or ServiceNameRPCNameResponse (configurable).
+SERVICE_SUFFIX                      STANDARD                  *        Checks that services have a consistent suffix (configurable, default suffix is "Service").
+COMMENT_ENUM                        COMMENTS                           Checks that enums have non-empty comments.
+COMMENT_ENUM_VALUE                  COMMENTS                           Checks that enum values have non-empty comments.
+COMMENT_FIELD                       COMMENTS                           Checks that fields have non-empty comments.
+COMMENT_MESSAGE                     COMMENTS                           Checks that messages have non-empty comments.
+COMMENT_ONEOF                       COMMENTS                           Checks that oneofs have non-empty comments.
+COMMENT_RPC                         COMMENTS                           Checks that RPCs have non-empty comments.
+COMMENT_SERVICE                     COMMENTS                           Checks that services have non-empty comments.
+RPC_NO_CLIENT_STREAMING             UNARY_RPC                          Checks that RPCs are not client streaming.
+RPC_NO_SERVER_STREAMING             UNARY_RPC                          Checks that RPCs are not server streaming.
+AIP_FIELD_BEHAVIOR_CONFLICT         AIP                                Checks that fields are not annotated with conflicting google.api.field_behavior values.
+AIP_FIELD_BEHAVIOR_REQUIRED         AIP                                Checks that the name, parent, and resource fields of AIP standard method requests are annotated as REQUIRED.
+AIP_LIST_PAGINATION                 AIP                                Checks that AIP List methods have page_size, page_token, and next_page_token fields.
+AIP_RESOURCE_ANNOTATION             AIP                                Checks that google.api.resource annotations have a type matching the message name and at least one pattern.
+AIP_RESOURCE_NAME_FIELD             AIP                                Checks that messages with a google.api.resource annotation have a string name field.
+AIP_STANDARD_METHOD_REQUEST_FIELDS  AIP                                Checks that AIP standard method requests have the name or resource field for their method.
+AIP_STANDARD_METHOD_REQUEST_NAME    AIP                                Checks that AIP standard method request types are named MethodNameRequest.
+AIP_STANDARD_METHOD_RESPONSE_NAME   AIP                                Checks that AIP standard methods return the resource, a ListResourcesResponse, or google.protobuf.Empty as appropriate.
+AIP_UPDATE_MASK                     AIP                                Checks that AIP Update method requests have a google.protobuf.FieldMask update_mask field.
+STABLE_PACKAGE_NO_IMPORT_UNSTABLE                                      Checks that all files that have stable versioned packages do not import packages with unstable version packages.
 		`
 	testRunStdout(
 		t,
@@ -1190,6 +1199,9 @@ ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED     CSR, WIRE_JSON, WIRE
 FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED          CSR, WIRE_JSON, WIRE                          Checks that fields are not deleted from a given message unless the number is reserved.
 FIELD_WIRE_COMPATIBLE_CARDINALITY               WIRE                                          Checks that fields have wire-compatible cardinalities in a given message.
 FIELD_WIRE_COMPATIBLE_TYPE                      WIRE                                          Checks that fields have wire-compatible types in a given message.
+FIELD_NO_PROTOVALIDATE_TIGHTENING               PROTOVALIDATE_RULES                           Checks that protovalidate rules on fields are not tightened.
+MESSAGE_NO_PROTOVALIDATE_TIGHTENING             PROTOVALIDATE_RULES                           Checks that protovalidate rules on messages are not tightened.
+ONEOF_NO_PROTOVALIDATE_TIGHTENING               PROTOVALIDATE_RULES                           Checks that protovalidate rules on oneofs are not tightened.
 		`
 	testRunStdout(
 		t,
@@ -2871,6 +2883,282 @@ func TestLintWithPaths(t *testing.T) {
 	)
 }
 
+func TestLintFix(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint", "fix"))))
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		0,
+		"",
+		"",
+		"lint",
+		tempDir,
+		"--fix",
+	)
+	for _, path := range []string{
+		"foo/bar/v1/a.proto",
+		"foo/bar/v1/b.proto",
+		"foo/bar/v1/unused.proto",
+	} {
+		expectedData, err := os.ReadFile(filepath.Join("testdata", "lint", "fix_expected", filepath.FromSlash(path)))
+		require.NoError(t, err)
+		actualData, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		assert.Equal(t, string(expectedData), string(actualData), path)
+	}
+	// Everything was fixed.
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		0,
+		"",
+		"",
+		"lint",
+		tempDir,
+	)
+}
+
+func TestLintFixDiff(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		bufctl.ExitCodeFileAnnotation,
+		nil,
+		stdout,
+		"lint",
+		filepath.Join("testdata", "lint", "fix"),
+		"--fix",
+		"--diff",
+	)
+	assert.Contains(
+		t,
+		stdout.String(),
+		`
+ message Other {
+-	int32 someValue = 1;
++  int32 some_value = 1;
+ }
+`,
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"Failure: --diff requires --fix"},
+		"lint",
+		filepath.Join("testdata", "lint", "fix"),
+		"--diff",
+	)
+}
+
+func TestLintFixUnsafe(t *testing.T) {
+	t.Parallel()
+	// With --diff, the failures are printed to stderr, so that stdout only has the diff,
+	// which is empty as nothing can be fixed.
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		"",
+		filepath.FromSlash(`testdata/lint/fix_unsafe/a.proto:7:9:Message name "value" should be PascalCase, such as "Value".
+testdata/lint/fix_unsafe/a.proto:16:10:Field name "someOption" should be lower_snake_case, such as "some_option".
+testdata/lint/fix_unsafe/a.proto:26:12:Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+		"lint",
+		filepath.Join("testdata", "lint", "fix_unsafe"),
+		"--fix",
+		"--diff",
+	)
+	pathJSON, err := json.Marshal(filepath.Join("testdata", "lint", "fix_unsafe", "a.proto"))
+	require.NoError(t, err)
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		"",
+		strings.ReplaceAll(
+			`{"path":PATH,"start_line":7,"start_column":9,"end_line":7,"end_column":14,"type":"MESSAGE_PASCAL_CASE","message":"Message name \"value\" should be PascalCase, such as \"Value\"."}
+{"path":PATH,"start_line":16,"start_column":10,"end_line":16,"end_column":20,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"someOption\" should be lower_snake_case, such as \"some_option\"."}
+{"path":PATH,"start_line":26,"start_column":12,"end_line":26,"end_column":18,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"fooBar\" should be lower_snake_case, such as \"foo_bar\"."}`,
+			"PATH",
+			string(pathJSON),
+		),
+		"lint",
+		filepath.Join("testdata", "lint", "fix_unsafe"),
+		"--fix",
+		"--diff",
+		"--error-format",
+		"json",
+	)
+}
+
+func TestLintBaseline(t *testing.T) {
+	t.Parallel()
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/lint/baseline/a.proto:7:10:Field name "otherValue" should be lower_snake_case, such as "other_value".`),
+		filepath.FromSlash(`The following entries of the baseline testdata/lint/baseline/buf.lint-baseline.json are fixed, and can be removed by writing the baseline again with --write-baseline:
+  a.proto: FIELD_LOWER_SNAKE_CASE a.foo_bar.removedValue`),
+		"lint",
+		filepath.Join("testdata", "lint", "baseline"),
+		"--baseline",
+		filepath.Join("testdata", "lint", "baseline", "buf.lint-baseline.json"),
+	)
+}
+
+func TestLintWriteBaseline(t *testing.T) {
+	t.Parallel()
+	baselinePath := filepath.Join(t.TempDir(), "buf.lint-baseline.json")
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		0,
+		"",
+		"",
+		"lint",
+		filepath.Join("testdata", "lint", "baseline"),
+		"--write-baseline",
+		baselinePath,
+	)
+	data, err := os.ReadFile(baselinePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{
+  "version": "v1",
+  "entries": [
+    {
+      "rule": "FIELD_LOWER_SNAKE_CASE",
+      "file": "a.proto",
+      "symbol": "a.foo_bar.otherValue"
+    },
+    {
+      "rule": "FIELD_LOWER_SNAKE_CASE",
+      "file": "a.proto",
+      "symbol": "a.foo_bar.someValue"
+    },
+    {
+      "rule": "MESSAGE_PASCAL_CASE",
+      "file": "a.proto",
+      "symbol": "a.foo_bar"
+    }
+  ]
+}
+`,
+		string(data),
+	)
+	// Nothing is reported with the baseline that was just written.
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		0,
+		"",
+		"",
+		"lint",
+		filepath.Join("testdata", "lint", "baseline"),
+		"--baseline",
+		baselinePath,
+	)
+}
+
+func TestLintSeverity(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`
+		testdata/lint/severity/a.proto:3:1:warning: Files with package "a" must be within a directory "a" relative to root but were in directory ".".
+		testdata/lint/severity/a.proto:3:1:Package name "a" should be suffixed with a correctly formed version, such as "a.v1".
+		testdata/lint/severity/a.proto:6:10:info: Field name "badName" should be lower_snake_case, such as "bad_name".
+		testdata/lint/severity/a.proto:11:3:Enum value name "BAZ" should be prefixed with "BAR_".
+		`),
+		"lint",
+		filepath.Join("testdata", "lint", "severity"),
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`
+		::warning file=testdata/lint/severity/a.proto,line=3,col=1,endLine=3,endColumn=11::Files with package "a" must be within a directory "a" relative to root but were in directory ".".
+		::error file=testdata/lint/severity/a.proto,line=3,col=1,endLine=3,endColumn=11::Package name "a" should be suffixed with a correctly formed version, such as "a.v1".
+		::notice file=testdata/lint/severity/a.proto,line=6,col=10,endLine=6,endColumn=17::Field name "badName" should be lower_snake_case, such as "bad_name".
+		::error file=testdata/lint/severity/a.proto,line=11,col=3,endLine=11,endColumn=6::Enum value name "BAZ" should be prefixed with "BAR_".
+		`),
+		"lint",
+		filepath.Join("testdata", "lint", "severity"),
+		"--error-format",
+		"github-actions",
+	)
+}
+
+func TestLintSeverityNoErrors(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(`
+		testdata/lint/severity_warning/a.proto:3:1:warning: Files with package "a" must be within a directory "a" relative to root but were in directory ".".
+		testdata/lint/severity_warning/a.proto:3:1:warning: Package name "a" should be suffixed with a correctly formed version, such as "a.v1".
+		testdata/lint/severity_warning/a.proto:6:10:warning: Field name "badName" should be lower_snake_case, such as "bad_name".
+		testdata/lint/severity_warning/a.proto:11:3:warning: Enum value name "BAZ" should be prefixed with "BAR_".
+		`),
+		"lint",
+		filepath.Join("testdata", "lint", "severity_warning"),
+	)
+}
+
+func TestSARIFNoAnnotations(t *testing.T) {
+	t.Parallel()
+	// A SARIF log is printed even if there are no annotations.
+	const expected = `{"$schema":"https://json.schemastore.org/sarif-2.1.0.json","version":"2.1.0","runs":[{"tool":{"driver":{"name":"buf","informationUri":"https://buf.build"}},"results":[]}]}`
+	testRunStdout(
+		t,
+		nil,
+		0,
+		expected,
+		"lint",
+		filepath.Join("testdata", "success"),
+		"--error-format",
+		"sarif",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		expected,
+		"breaking",
+		filepath.Join("testdata", "success"),
+		"--against",
+		filepath.Join("testdata", "success"),
+		"--error-format",
+		"sarif",
+	)
+}
+
+func TestBreakingSeverityNoErrors(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(`
+		testdata/breaking/severity/current/a.proto:5:1:warning: Previously present field "2" with name "name" on message "Foo" was deleted.
+		testdata/breaking/severity/current/a.proto:6:3:info: Field "1" with name "value" on message "Foo" changed type from "int32" to "string".
+		`),
+		"breaking",
+		filepath.Join("testdata", "breaking", "severity", "current"),
+		"--against",
+		filepath.Join("testdata", "breaking", "severity", "previous"),
+	)
+}
+
 func TestLintWithPlugins(t *testing.T) {
 	t.Parallel()
 	// defaults only, comment ignores on.
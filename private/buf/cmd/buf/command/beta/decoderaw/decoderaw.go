@@ -0,0 +1,189 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoderaw
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"buf.build/go/app/appcmd"
+	"buf.build/go/app/appext"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufconvert"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
+	"github.com/spf13/pflag"
+)
+
+const (
+	errorFormatFlagName     = "error-format"
+	fromFlagName            = "from"
+	maxCandidatesFlagName   = "max-candidates"
+	disableSymlinksFlagName = "disable-symlinks"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " [<input>]",
+		Short: "Decode a binary message without knowing its type",
+		Long: `
+Decode a message in the binary format without a schema, and print its field numbers,
+wire types and values, like "protoc --decode_raw".
+
+Length-delimited values that can be decoded as a message are assumed to be embedded messages
+and are decoded recursively. Other length-delimited values are printed as quoted strings.
+Fixed-width values are printed in hexadecimal.
+
+    $ buf beta decode-raw --from=payload.binpb
+    1 (varint): 150
+    2 (len): "testing"
+    3 (len) {
+      1 (i64): 0x3ff0000000000000
+    }
+
+If an <input> is given, the message is also matched against the message types of the input,
+and the candidate types that the most fields fit are printed. A field fits a type if the type
+has a field with the same number and a type that is compatible with the wire type:
+
+    $ buf beta decode-raw buf.build/acme/weather --from=payload.binpb
+
+The <input> can be anything that "buf build" accepts, such as a local directory, a .proto file,
+an image or a module on the BSR. Only the types of the files of the input are candidates, not
+the types of their imports.
+`,
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat     string
+	From            string
+	MaxCandidates   int
+	DisableSymlinks bool
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			xstrings.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+	flagSet.StringVar(
+		&f.From,
+		fromFlagName,
+		"-",
+		`The location of the binary message to decode. Use "-" to read from stdin`,
+	)
+	flagSet.IntVar(
+		&f.MaxCandidates,
+		maxCandidatesFlagName,
+		5,
+		"The maximum number of candidate message types to print when an input is given",
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if flags.MaxCandidates < 1 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be at least 1", maxCandidatesFlagName)
+	}
+	data, err := readFrom(container, flags.From)
+	if err != nil {
+		return fmt.Errorf("--%s: %w", fromFlagName, err)
+	}
+	fields, err := bufconvert.DecodeRaw(data)
+	if err != nil {
+		return fmt.Errorf("--%s: failed to decode message: %w", fromFlagName, err)
+	}
+	if err := bufconvert.PrintRaw(container.Stdout(), fields); err != nil {
+		return err
+	}
+	if container.NumArgs() == 0 {
+		return nil
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(ctx, container.Arg(0))
+	if err != nil {
+		return err
+	}
+	messageTypeMatches, err := bufconvert.MatchMessageTypes(image, fields)
+	if err != nil {
+		return err
+	}
+	if len(messageTypeMatches) == 0 {
+		_, err := fmt.Fprintln(container.Stdout(), "\nNo message types in the input match the message.")
+		return err
+	}
+	if len(messageTypeMatches) > flags.MaxCandidates {
+		messageTypeMatches = messageTypeMatches[:flags.MaxCandidates]
+	}
+	if _, err := fmt.Fprintln(container.Stdout(), "\nCandidate types:"); err != nil {
+		return err
+	}
+	tabWriter := tabwriter.NewWriter(container.Stdout(), 0, 0, 2, ' ', 0)
+	for _, messageTypeMatch := range messageTypeMatches {
+		if _, err := fmt.Fprintf(
+			tabWriter,
+			"  %s\t%d/%d fields\n",
+			messageTypeMatch.MessageDescriptor.FullName(),
+			messageTypeMatch.MatchedFields,
+			messageTypeMatch.TotalFields,
+		); err != nil {
+			return err
+		}
+	}
+	return tabWriter.Flush()
+}
+
+func readFrom(container appext.Container, from string) ([]byte, error) {
+	if from == "-" {
+		return io.ReadAll(container.Stdin())
+	}
+	return os.ReadFile(from)
+}
@@ -0,0 +1,177 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"buf.build/go/app/appcmd"
+	"buf.build/go/app/appext"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufserve"
+	"github.com/bufbuild/buf/private/pkg/transport/http/httpserver"
+	"github.com/spf13/pflag"
+)
+
+const (
+	bindFlagName            = "bind"
+	portFlagName            = "port"
+	fixturesFlagName        = "fixtures"
+	originFlagName          = "origin"
+	configFlagName          = "config"
+	pathsFlagName           = "path"
+	excludePathsFlagName    = "exclude-path"
+	disableSymlinksFlagName = "disable-symlinks"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Run a mock server for the services of an input",
+		Long: `Every service defined in the input is served over the Connect, gRPC, and gRPC-Web protocols,
+using HTTP/1.1 and HTTP/2 without TLS. gRPC server reflection is also served, so the server
+can be called with "buf curl --reflect".
+
+Every RPC responds with a default-valued message, unless a fixture exists for the method in
+the directory given by --fixtures. The fixture for a method is read from the file
+<package.Service>/<Method>.yaml, <package.Service>/<Method>.yml, or <package.Service>/<Method>.json
+in that directory, and contains the response message. For methods that stream responses, the
+fixture may instead contain a list of response messages, which are all sent. Fixtures are read
+on every call, so they can be edited while the server is running.
+
+Request messages are ignored.
+
+` + bufcli.GetInputLong(`the source, module, or image to serve`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	BindAddress     string
+	Port            string
+	Fixtures        string
+	Origins         []string
+	Config          string
+	Paths           []string
+	ExcludePaths    []string
+	DisableSymlinks bool
+
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
+	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.BindAddress,
+		bindFlagName,
+		"127.0.0.1",
+		"The address to be exposed to accept HTTP requests",
+	)
+	flagSet.StringVar(
+		&f.Port,
+		portFlagName,
+		"8080",
+		"The port to be exposed to accept HTTP requests",
+	)
+	flagSet.StringVar(
+		&f.Fixtures,
+		fixturesFlagName,
+		"",
+		"The directory to read canned responses from",
+	)
+	flagSet.StringSliceVar(
+		&f.Origins,
+		originFlagName,
+		nil,
+		`The origins allowed to make cross-origin requests, such as "http://localhost:3000". Multiple origins are appended if specified multiple times`,
+	)
+	flagSet.StringVar(
+		&f.Config,
+		configFlagName,
+		"",
+		`The buf.yaml file or data to use for configuration`,
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(
+		ctx,
+		input,
+		bufctl.WithTargetPaths(flags.Paths, flags.ExcludePaths),
+		bufctl.WithConfigOverride(flags.Config),
+	)
+	if err != nil {
+		return err
+	}
+	handler, err := bufserve.NewHandler(
+		container.Logger(),
+		image,
+		bufserve.HandlerWithFixtureDirPath(flags.Fixtures),
+		bufserve.HandlerWithCORSAllowedOrigins(flags.Origins...),
+	)
+	if err != nil {
+		return err
+	}
+	var httpListenConfig net.ListenConfig
+	httpListener, err := httpListenConfig.Listen(ctx, "tcp", fmt.Sprintf("%s:%s", flags.BindAddress, flags.Port))
+	if err != nil {
+		return err
+	}
+	container.Logger().Info("serving", slog.String("address", httpListener.Addr().String()))
+	return httpserver.Run(
+		ctx,
+		container.Logger(),
+		httpListener,
+		handler,
+	)
+}
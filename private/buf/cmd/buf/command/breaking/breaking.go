@@ -21,6 +21,7 @@ import (
 
 	"buf.build/go/app/appcmd"
 	"buf.build/go/app/appext"
+	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/buf/buffetch"
@@ -283,6 +284,9 @@ func run(
 		allCheckConfigs = append(allCheckConfigs, imageWithConfig.BreakingConfig())
 	}
 	var allFileAnnotations []bufanalysis.FileAnnotation
+	// SARIF output describes each rule that was violated, so we gather the
+	// descriptions of all configured rules if it was requested.
+	ruleIDToDescription := make(map[string]string)
 	for i, imageWithConfig := range imageWithConfigs {
 		breakingOptions := []bufcheck.BreakingOption{
 			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
@@ -305,17 +309,38 @@ func run(
 				return err
 			}
 		}
+		if flags.ErrorFormat == bufanalysis.FormatSARIF.String() {
+			rules, err := checkClient.ConfiguredRules(
+				ctx,
+				check.RuleTypeBreaking,
+				imageWithConfig.BreakingConfig(),
+				bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+				bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+			)
+			if err != nil {
+				return err
+			}
+			for _, rule := range rules {
+				ruleIDToDescription[rule.ID()] = rule.Purpose()
+			}
+		}
 	}
-	if len(allFileAnnotations) > 0 {
+	// A SARIF log is printed even if there are no annotations, since consumers such as
+	// code scanning uploads expect one.
+	if len(allFileAnnotations) > 0 || flags.ErrorFormat == bufanalysis.FormatSARIF.String() {
 		allFileAnnotationSet := bufanalysis.NewFileAnnotationSet(allFileAnnotations...)
 		if err := bufanalysis.PrintFileAnnotationSet(
 			container.Stdout(),
 			allFileAnnotationSet,
 			flags.ErrorFormat,
+			bufanalysis.PrintWithRuleDescriptions(ruleIDToDescription),
 		); err != nil {
 			return err
 		}
-		return bufctl.ErrFileAnnotation
+		// Annotations with a severity other than error are printed, but do not fail.
+		if bufanalysis.HasErrors(allFileAnnotations...) {
+			return bufctl.ErrFileAnnotation
+		}
 	}
 	return nil
 }
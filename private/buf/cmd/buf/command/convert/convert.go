@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 
 	"buf.build/go/app/appcmd"
@@ -33,6 +34,7 @@ import (
 	"github.com/bufbuild/buf/private/gen/data/datawkt"
 	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
 	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 const (
@@ -42,6 +44,8 @@ const (
 	toFlagName              = "to"
 	validateFlagName        = "validate"
 	disableSymlinksFlagName = "disable-symlinks"
+	streamFlagName          = "stream"
+	filterFlagName          = "filter"
 )
 
 // NewCommand returns a new Command.
@@ -83,6 +87,18 @@ Redirect from stdin to <input>:
 Use a module on the bsr:
 
     $ buf convert <buf.build/owner/repository> --type buf.Foo --from=payload.json
+
+Convert a stream of messages with "--stream". Messages are converted one at a time, so
+streams of any size can be converted. Binary messages are each prefixed by their size as
+a varint, JSON messages are one per line, and YAML and text format messages are separated
+by lines that consist of "---":
+
+    $ buf convert example.proto --type buf.Foo --from=payloads.binpb --to=payloads.jsonl#format=json --stream
+
+With "--stream", "--validate" is applied to each message, and "--filter" only keeps the
+messages for which a CEL expression evaluates to true. The message is bound to "this":
+
+    $ buf convert example.proto --type buf.Foo --from=payloads.binpb --to=-#format=json --stream --filter 'this.one > 50'
 `,
 		Args: appcmd.MaximumNArgs(1),
 		Run: builder.NewRunFunc(
@@ -101,6 +117,8 @@ type flags struct {
 	To              string
 	Validate        bool
 	DisableSymlinks bool
+	Stream          bool
+	Filter          string
 
 	// special
 	InputHashtag string
@@ -155,6 +173,25 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 			fromFlagName,
 		),
 	)
+	flagSet.BoolVar(
+		&f.Stream,
+		streamFlagName,
+		false,
+		fmt.Sprintf(
+			`Convert a stream of messages specified with --%s one message at a time. Binary messages must each be prefixed by their size as a varint, JSON messages must be one per line, and YAML and text format messages must be separated by lines that consist of "---". The messages written to --%s are delimited the same way`,
+			fromFlagName,
+			toFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.Filter,
+		filterFlagName,
+		"",
+		fmt.Sprintf(
+			`A CEL expression that must evaluate to true for a message to be converted, with the message bound to "this". Requires --%s`,
+			streamFlagName,
+		),
+	)
 }
 
 func run(
@@ -162,6 +199,9 @@ func run(
 	container appext.Container,
 	flags *flags,
 ) error {
+	if flags.Filter != "" && !flags.Stream {
+		return appcmd.NewInvalidArgumentErrorf("--%s requires --%s", filterFlagName, streamFlagName)
+	}
 	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
 	if err != nil {
 		return err
@@ -219,6 +259,9 @@ func run(
 	if flags.Validate {
 		fromFunctionOptions = append(fromFunctionOptions, bufctl.WithMessageValidation())
 	}
+	if flags.Stream {
+		return runStream(ctx, controller, schemaImage, flags, fromFunctionOptions)
+	}
 	fromMessage, fromMessageEncoding, err := controller.GetMessage(
 		ctx,
 		schemaImage,
@@ -246,6 +289,79 @@ func run(
 	return nil
 }
 
+func runStream(
+	ctx context.Context,
+	controller bufctl.Controller,
+	schemaImage bufimage.Image,
+	flags *flags,
+	fromFunctionOptions []bufctl.FunctionOption,
+) (retErr error) {
+	messageReader, err := controller.GetMessageReader(
+		ctx,
+		schemaImage,
+		flags.From,
+		flags.Type,
+		buffetch.MessageEncodingBinpb,
+		fromFunctionOptions...,
+	)
+	if err != nil {
+		return fmt.Errorf("--%s: %w", fromFlagName, err)
+	}
+	defer func() {
+		retErr = errors.Join(retErr, messageReader.Close())
+	}()
+	var messageFilter bufconvert.MessageFilter
+	if flags.Filter != "" {
+		messageDescriptor, err := schemaImage.Resolver().FindMessageByName(protoreflect.FullName(flags.Type))
+		if err != nil {
+			return err
+		}
+		messageFilter, err = bufconvert.NewMessageFilter(messageDescriptor.Descriptor(), flags.Filter)
+		if err != nil {
+			return fmt.Errorf("--%s: %w", filterFlagName, err)
+		}
+	}
+	defaultToMessageEncoding, err := inverseEncoding(messageReader.MessageEncoding())
+	if err != nil {
+		return err
+	}
+	messageWriter, err := controller.GetMessageWriter(
+		ctx,
+		schemaImage,
+		flags.To,
+		defaultToMessageEncoding,
+	)
+	if err != nil {
+		return fmt.Errorf("--%s: %w", toFlagName, err)
+	}
+	defer func() {
+		if err := messageWriter.Close(); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("--%s: %w", toFlagName, err))
+		}
+	}()
+	for {
+		message, err := messageReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("--%s: %w", fromFlagName, err)
+		}
+		if messageFilter != nil {
+			matches, err := messageFilter.Matches(message)
+			if err != nil {
+				return fmt.Errorf("--%s: %w", filterFlagName, err)
+			}
+			if !matches {
+				continue
+			}
+		}
+		if err := messageWriter.Write(message); err != nil {
+			return fmt.Errorf("--%s: %w", toFlagName, err)
+		}
+	}
+}
+
 // inverseEncoding returns the opposite encoding of the provided encoding,
 // which will be the default output encoding for a given payload encoding.
 func inverseEncoding(encoding buffetch.MessageEncoding) (buffetch.MessageEncoding, error) {
@@ -423,6 +423,133 @@ func TestConvertWKTImport(t *testing.T) {
 	)
 }
 
+func TestConvertStreamJSONToYAML(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(0),
+		appcmdtesting.WithExpectedStdout("one: \"55\"\n---\none: \"7\""),
+		appcmdtesting.WithStdin(strings.NewReader("{\"one\":\"55\"}\n\n{\"one\":7}\n")),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=-#format=json",
+			"--to=-#format=yaml",
+			"--stream",
+		),
+	)
+}
+
+func TestConvertStreamBinpbToJSON(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(0),
+		appcmdtesting.WithExpectedStdout("{\"one\":\"55\"}\n{}\n{\"one\":\"60\"}"),
+		// Three size-delimited messages, the second of which is empty.
+		appcmdtesting.WithStdin(strings.NewReader("\x02\x08\x37\x00\x02\x08\x3c")),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=-#format=binpb",
+			"--stream",
+		),
+	)
+}
+
+func TestConvertStreamFilter(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(0),
+		appcmdtesting.WithExpectedStdout("one: 55\n---\none: 60"),
+		appcmdtesting.WithStdin(strings.NewReader("---\none: 55\n---\none: 7\n---\none: 60\n")),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=-#format=yaml",
+			"--to=-#format=txtpb",
+			"--stream",
+			"--filter=this.one > 50",
+		),
+	)
+}
+
+func TestConvertStreamInvalidMessage(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(1),
+		appcmdtesting.WithExpectedStdout("{\"one\":\"55\"}"),
+		appcmdtesting.WithExpectedStderrPartials("--from: message 2:"),
+		appcmdtesting.WithStdin(strings.NewReader("{\"one\":\"55\"}\n{\"one\":\"x\"}\n")),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=-#format=json",
+			"--to=-#format=json",
+			"--stream",
+		),
+	)
+}
+
+func TestConvertStreamBinpbTruncated(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(1),
+		appcmdtesting.WithExpectedStdout("{\"one\":\"55\"}"),
+		appcmdtesting.WithExpectedStderrPartials("--from: message 2: invalid size 100: only 2 bytes remaining"),
+		// The second message claims a size of 100 bytes but only 2 bytes follow.
+		appcmdtesting.WithStdin(strings.NewReader("\x02\x08\x37\x64\x08\x3c")),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=-#format=binpb",
+			"--stream",
+		),
+	)
+}
+
+func TestConvertStreamBinpbOversized(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(1),
+		appcmdtesting.WithExpectedStderrPartials("--from: message 1: invalid size 1099511627776"),
+		// A size prefix of 1 TiB, as can be read from a binary message that is not size-delimited.
+		appcmdtesting.WithStdin(strings.NewReader("\x80\x80\x80\x80\x80\x20\x08\x37")),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=-#format=binpb",
+			"--stream",
+		),
+	)
+}
+
+func TestConvertFilterWithoutStream(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.Run(
+		t,
+		testNewCommand,
+		appcmdtesting.WithExpectedExitCode(1),
+		appcmdtesting.WithExpectedStderrPartials("--filter requires --stream"),
+		appcmdtesting.WithArgs(
+			"testdata/convert/bin_json/buf.proto",
+			"--type=buf.Foo",
+			"--from=testdata/convert/bin_json/payload.json",
+			"--filter=this.one > 50",
+		),
+	)
+}
+
 func testNewCommand(use string) *appcmd.Command {
 	return NewCommand("convert", appext.NewBuilder("convert"))
 }
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
@@ -35,9 +36,11 @@ import (
 	"buf.build/go/app"
 	"buf.build/go/app/appcmd"
 	"buf.build/go/app/appext"
+	"buf.build/go/protovalidate"
 	"connectrpc.com/connect"
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufcurl"
+	"github.com/bufbuild/buf/private/buf/buffetch"
 	"github.com/bufbuild/buf/private/pkg/netrc"
 	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
 	"github.com/bufbuild/buf/private/pkg/verbose"
@@ -45,6 +48,7 @@ import (
 	"github.com/quic-go/quic-go/http3"
 	"github.com/spf13/pflag"
 	"golang.org/x/net/http2"
+	"golang.org/x/term"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -62,6 +66,7 @@ const (
 	unixSocketFlagName          = "unix-socket"
 	http2PriorKnowledgeFlagName = "http2-prior-knowledge"
 	http3FlagName               = "http3"
+	httpTranscodingFlagName     = "http-transcoding"
 
 	// TLS flags
 	keyFlagName           = "key"
@@ -75,6 +80,13 @@ const (
 	// Action flags
 	listServicesFlagName = "list-services"
 	listMethodsFlagName  = "list-methods"
+	describeFlagName     = "describe"
+	healthFlagName       = "health"
+	healthWatchFlagName  = "health-watch"
+
+	// The value of --health when no service is given, to check the
+	// health of the server as a whole.
+	healthServerFlagValue = "<server>"
 
 	// Timeout flags
 	noKeepAliveFlagName    = "no-keepalive"
@@ -93,12 +105,31 @@ const (
 	headerFlagShortName    = "H"
 	dataFlagName           = "data"
 	dataFlagShortName      = "d"
+	dataFormatFlagName     = "data-format"
+	interactiveFlagName    = "interactive"
 
 	// Output flags
 	outputFlagName       = "output"
 	outputFlagShortName  = "o"
+	outputFormatFlagName = "output-format"
 	emitDefaultsFlagName = "emit-defaults"
 
+	// Validation flags
+	validateFlagName          = "validate"
+	validateResponsesFlagName = "validate-responses"
+
+	// Load testing flags
+	loadRequestsFlagName     = "load-requests"
+	loadDurationFlagName     = "load-duration"
+	loadConcurrencyFlagName  = "load-concurrency"
+	loadRateFlagName         = "load-rate"
+	loadReportFormatFlagName = "load-report-format"
+
+	// Record and replay flags
+	recordFlagName       = "record"
+	replayFlagName       = "replay"
+	replayIgnoreFlagName = "replay-ignore"
+
 	verboseFlagName      = "verbose"
 	verboseFlagShortName = "v"
 )
@@ -129,6 +160,16 @@ during protocol negotiation and HTTP 1.1 used only if the server does not suppor
 The default RPC protocol used will be Connect. To use a different protocol (gRPC or gRPC-Web),
 use the --protocol flag. Note that the gRPC protocol cannot be used with HTTP 1.1.
 
+Methods with a google.api.http option can instead be invoked as REST endpoints, as served by
+gRPC-Gateway or Envoy's gRPC-JSON transcoder, using the --http-transcoding flag. The request is
+then sent with the HTTP method and path of the option: fields bound in the path template are
+sent in the path, the field named by the body of the option is sent as the JSON request body,
+and all other populated fields are sent as query parameters. The JSON response is mapped back
+to the response message, and printed like the response of an RPC. For example:
+
+    $ buf curl --schema . --http-transcoding --data '{"name": "shelves/1/books/2"}'  \
+         http://localhost:8080/example.library.v1.LibraryService/GetBook
+
 The input request is specified via the -d or --data flag. If absent, an empty request is sent. If
 the flag value starts with an at-sign (@), then the rest of the flag value is interpreted as a
 filename from which to read the request body. If that filename is just a dash (-), then the request
@@ -138,6 +179,16 @@ consist of multiple JSON values, appended to one another. Multiple JSON document
 separated by whitespace, though this is not strictly required unless the request message type has a
 custom JSON representation that is not a JSON object.
 
+The request body may instead be in YAML, Protobuf text format or Protobuf binary format, using the
+--data-format flag. Multiple YAML or text format messages are separated by lines that consist of
+"---". Multiple binary messages are size-delimited: each message is prefixed by its size, encoded as
+a varint. Similarly, the --output-format flag selects the format responses are printed in, with
+binary responses of a server-streaming method being size-delimited.
+
+Request messages can be validated with protovalidate rules before they are sent with the --validate
+flag, and response messages when they are received with the --validate-responses flag. Violations
+are reported with the paths of the fields that violate the rules.
+
 Request metadata (i.e. headers) are defined using -H or --header flags. The flag value is in
 "name: value" format. But if it starts with an at-sign (@), the rest of the value is interpreted as
 a filename from which headers are read, each on a separate line. If the filename is just a dash (-),
@@ -180,6 +231,44 @@ headers and request data are both in a heredoc:
     {"sentence": "If you were a fish, what of fish would you be?."}
     EOM
 
+Load testing mode is enabled by the --load-requests or --load-duration flags. Instead of invoking
+the RPC once and printing the response, the RPC is invoked repeatedly with the same request by
+--load-concurrency workers, optionally limited to a total of --load-rate requests per second. Once
+the limit on requests or duration is reached, a report of throughput, latency percentiles, a latency
+histogram and the status codes of the responses is printed, as text or JSON depending on the
+--load-report-format flag. For example:
+
+    $ buf curl --data '{"sentence": "Hello."}' --load-requests 1000 --load-concurrency 10  \
+         https://demo.connectrpc.com/connectrpc.eliza.v1.ElizaService/Say
+
+A call can be recorded to a file with the --record flag, and later replayed with the --replay
+flag, which sends the recorded requests again and compares the status and responses against the
+recording. Fields that are expected to change between calls, like timestamps or IDs, can be
+ignored with the --replay-ignore flag. This makes recordings usable as golden files for API
+regression tests. For example:
+
+    $ buf curl --data '{"sentence": "Hello."}' --record say.json  \
+         https://demo.connectrpc.com/connectrpc.eliza.v1.ElizaService/Say
+    $ buf curl --replay say.json --replay-ignore sentence  \
+         https://demo.connectrpc.com/connectrpc.eliza.v1.ElizaService/Say
+
+The request messages of a client-streaming or bidirectional-streaming method can be typed
+interactively with the --interactive flag. Messages are typed in JSON, with line editing,
+history, and completion of field names with Tab, and response messages are printed as they
+arrive. Ctrl-D closes the request stream, and Ctrl-C cancels the RPC. For example:
+
+    $ buf curl --interactive  \
+         https://demo.connectrpc.com/connectrpc.eliza.v1.ElizaService/Converse
+
+The schema of a server can be explored with the --list-services and --list-methods flags, and the
+definition of a service, method, message or enum printed in .proto syntax with the --describe
+flag. The health of a server or service can be checked with the --health flag, which calls the
+standard grpc.health.v1.Health service and exits with a non-zero code unless the status is
+SERVING, which makes it usable in liveness and readiness probes. For example:
+
+    $ buf curl --describe connectrpc.eliza.v1.SayRequest https://demo.connectrpc.com
+    $ buf curl --protocol grpc --health=foo.bar.v1.FooService https://localhost:20202
+
 Note that server reflection (i.e. use of the --reflect flag) does not work with HTTP 1.1 since the
 protocol relies on bidirectional streaming. If server reflection is used, the assumed URL for the
 reflection service is the same as the given URL, but with the last two elements removed and
@@ -213,6 +302,7 @@ type flags struct {
 	UnixSocket          string
 	HTTP2PriorKnowledge bool
 	HTTP3               bool
+	HTTPTranscoding     bool
 
 	// TLS
 	Key, Cert, CACert, ServerName string
@@ -221,6 +311,9 @@ type flags struct {
 
 	// Actions
 	ListServices, ListMethods bool
+	Describe                  string
+	Health                    string
+	HealthWatch               bool
 
 	// Timeouts
 	NoKeepAlive           bool
@@ -228,17 +321,36 @@ type flags struct {
 	ConnectTimeoutSeconds float64
 
 	// Handling request and response data and metadata
-	UserAgent string
-	User      string
-	Netrc     bool
-	NetrcFile string
-	Headers   []string
-	Data      string
+	UserAgent   string
+	User        string
+	Netrc       bool
+	NetrcFile   string
+	Headers     []string
+	Data        string
+	DataFormat  string
+	Interactive bool
 
 	// Output options
 	Output       string
+	OutputFormat string
 	EmitDefaults bool
 
+	// Validation
+	Validate          bool
+	ValidateResponses bool
+
+	// Load testing
+	LoadRequests        int
+	LoadDurationSeconds float64
+	LoadConcurrency     int
+	LoadRate            float64
+	LoadReportFormat    string
+
+	// Record and replay
+	Record       string
+	Replay       string
+	ReplayIgnore []string
+
 	Verbose bool
 
 	// so we can inquire about which flags present on command-line
@@ -315,6 +427,14 @@ respectively`,
 		connect.ProtocolConnect,
 		`The RPC protocol to use. This can be one of "grpc", "grpcweb", or "connect"`,
 	)
+	flagSet.BoolVar(
+		&f.HTTPTranscoding,
+		httpTranscodingFlagName,
+		false,
+		`If true, the method is invoked as a REST endpoint, as described by its google.api.http
+option, instead of with an RPC protocol. The path of the option is appended to the URL with
+the service and method name removed. Only unary methods are supported`,
+	)
 	flagSet.StringVar(
 		&f.UnixSocket,
 		unixSocketFlagName,
@@ -432,6 +552,35 @@ to provide the RPC schema, then the given URL must be a base URL, not including
 or method name. If the schema source is not server reflection, the URL is not used and
 may be omitted.`,
 	)
+	flagSet.StringVar(
+		&f.Describe,
+		describeFlagName,
+		"",
+		`When set, the command prints the definition of the given element, such as a service,
+method, message or enum, in .proto syntax and then exits. The element must be named by its
+fully-qualified name, and a method may also be named as in a URL, as "<service>/<method>".
+If server reflection is used to provide the RPC schema, then the given URL must be a base
+URL, not including a service or method name. If the schema source is not server reflection,
+the URL is not used and may be omitted.`,
+	)
+	flagSet.StringVar(
+		&f.Health,
+		healthFlagName,
+		"",
+		`When set, the command checks the health of the given service with the grpc.health.v1.Health
+service, prints its status, and exits with a non-zero code if the status is not SERVING. If
+no service is given, as in "--health", the health of the server as a whole is checked. The
+given URL must be a base URL, not including a service or method name. The schema is not
+needed to check health, so server reflection is not used.`,
+	)
+	flagSet.Lookup(healthFlagName).NoOptDefVal = healthServerFlagValue
+	flagSet.BoolVar(
+		&f.HealthWatch,
+		healthWatchFlagName,
+		false,
+		`If true, the health is watched with the Watch method instead of checked with the Check
+method, and every status is printed until the status is SERVING.`,
+	)
 
 	flagSet.StringVarP(
 		&f.UserAgent,
@@ -501,15 +650,34 @@ to be provided via stdin as a file descriptor set or image`,
 		dataFlagShortName,
 		"",
 		fmt.Sprintf(`Request data. This should be zero or more JSON documents, each indicating a request
-message. For unary RPCs, there should be exactly one JSON document. A special value of
+message, unless the --%s flag indicates another format. For unary RPCs, there should be
+exactly one message. A special value of
 '@<path>' means to read the data from the file at <path>. If the path is "-" then the
 request data is read from stdin. If the same file is indicated as used with the request
 headers flags (--%s or -%s), the file must contain all headers, then a blank line, and
 then the request body. It is not allowed to indicate stdin if the schema is expected to be
 provided via stdin as a file descriptor set or image`,
-			headerFlagName, headerFlagShortName,
+			dataFormatFlagName, headerFlagName, headerFlagShortName,
+		),
+	)
+	flagSet.StringVar(
+		&f.DataFormat,
+		dataFormatFlagName,
+		buffetch.MessageEncodingJSON.String(),
+		fmt.Sprintf(
+			`The format of the request data. Must be one of %s`,
+			buffetch.MessageFormatsString,
 		),
 	)
+	flagSet.BoolVar(
+		&f.Interactive,
+		interactiveFlagName,
+		false,
+		`If true, the request messages of a client-streaming or bidirectional-streaming method
+are typed interactively in the terminal, in JSON, and response messages are printed as they
+arrive. Press Tab to complete field names, Ctrl-D to close the request stream, and Ctrl-C to
+cancel the RPC. Stdin must be a terminal`,
+	)
 	flagSet.StringVarP(
 		&f.Output,
 		outputFlagName,
@@ -517,11 +685,103 @@ provided via stdin as a file descriptor set or image`,
 		"",
 		`Path to output file to create with response data. If absent, response is printed to stdout`,
 	)
+	flagSet.StringVar(
+		&f.OutputFormat,
+		outputFormatFlagName,
+		buffetch.MessageEncodingJSON.String(),
+		fmt.Sprintf(
+			`The format to print responses in. Must be one of %s`,
+			buffetch.MessageFormatsString,
+		),
+	)
 	flagSet.BoolVar(
 		&f.EmitDefaults,
 		emitDefaultsFlagName,
 		false,
-		`Emit default values for JSON-encoded responses.`,
+		`Emit default values for JSON- and YAML-encoded responses.`,
+	)
+
+	flagSet.BoolVar(
+		&f.Validate,
+		validateFlagName,
+		false,
+		`Validate each request message by applying protovalidate rules to it before it is sent.
+The RPC is not sent, or a stream is aborted, if a request message is invalid. See
+https://github.com/bufbuild/protovalidate for more details.`,
+	)
+	flagSet.BoolVar(
+		&f.ValidateResponses,
+		validateResponsesFlagName,
+		false,
+		`Validate each response message by applying protovalidate rules to it when it is received.
+An invalid response message is printed, and then the command fails with the violations.`,
+	)
+
+	flagSet.IntVar(
+		&f.LoadRequests,
+		loadRequestsFlagName,
+		0,
+		fmt.Sprintf(`The number of requests to send in load testing mode. If --%s is also
+set, the load test stops at whichever limit is reached first`,
+			loadDurationFlagName,
+		),
+	)
+	flagSet.Float64Var(
+		&f.LoadDurationSeconds,
+		loadDurationFlagName,
+		0,
+		fmt.Sprintf(`The duration, in seconds, to send requests for in load testing mode. If --%s
+is also set, the load test stops at whichever limit is reached first`,
+			loadRequestsFlagName,
+		),
+	)
+	flagSet.IntVar(
+		&f.LoadConcurrency,
+		loadConcurrencyFlagName,
+		1,
+		`The number of workers that concurrently send requests in load testing mode`,
+	)
+	flagSet.Float64Var(
+		&f.LoadRate,
+		loadRateFlagName,
+		0,
+		`The maximum number of requests per second to send, across all workers, in load testing
+mode. There is no limit if this flag is not present`,
+	)
+	flagSet.StringVar(
+		&f.LoadReportFormat,
+		loadReportFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			`The format of the report printed at the end of a load test. Must be one of %s`,
+			xstrings.SliceToString(bufcurl.AllLoadTestReportFormatStrings),
+		),
+	)
+
+	flagSet.StringVar(
+		&f.Record,
+		recordFlagName,
+		"",
+		`The path of a file to record the call to, in JSON format. The recording contains the
+request headers and messages, the response headers, messages and trailers, and the status
+of the call. Credentials in the authorization header are not recorded`,
+	)
+	flagSet.StringVar(
+		&f.Replay,
+		replayFlagName,
+		"",
+		`The path of a file with a recording of a call, as written by --record. The recorded
+requests are sent again, with the recorded request headers, and the status and responses
+are compared against the recording. If they differ, the differences are printed and an
+error is returned`,
+	)
+	flagSet.StringSliceVar(
+		&f.ReplayIgnore,
+		replayIgnoreFlagName,
+		nil,
+		`The path of a response field to ignore when comparing responses in replay mode, such
+as "book.create_time". The path applies to every element of the repeated and map fields it
+traverses. May be specified multiple times`,
 	)
 
 	flagSet.BoolVarP(
@@ -534,15 +794,23 @@ provided via stdin as a file descriptor set or image`,
 }
 
 func (f *flags) validate(hasURL, isSecure bool) error {
-	if len(f.Schemas) > 0 && f.Reflect && !f.flagSet.Changed(reflectFlagName) {
-		// Reflect just has default value; unset it since we're going to use --schema instead.
+	if f.isHealthCheck() {
+		if len(f.Schemas) > 0 || f.flagSet.Changed(reflectFlagName) {
+			return fmt.Errorf("--%s and --%s flags should not be used with --%s, the schema of the health service is known", schemaFlagName, reflectFlagName, healthFlagName)
+		}
+		// The schema is not needed to check health.
 		f.Reflect = false
-	}
-	if !f.Reflect && len(f.Schemas) == 0 {
-		return fmt.Errorf("must specify --%s if --%s is false", schemaFlagName, reflectFlagName)
+	} else {
+		if len(f.Schemas) > 0 && f.Reflect && !f.flagSet.Changed(reflectFlagName) {
+			// Reflect just has default value; unset it since we're going to use --schema instead.
+			f.Reflect = false
+		}
+		if !f.Reflect && len(f.Schemas) == 0 {
+			return fmt.Errorf("must specify --%s if --%s is false", schemaFlagName, reflectFlagName)
+		}
 	}
 
-	if !hasURL && ((!f.ListServices && !f.ListMethods) || f.Reflect) {
+	if !hasURL && ((!f.ListServices && !f.ListMethods && f.Describe == "") || f.Reflect) {
 		// If we are trying to use reflection for anything or if we are invoking an RPC (which
 		// means we aren't listing services, listing methods, or describing an element), then
 		// a URL is required.
@@ -552,6 +820,22 @@ func (f *flags) validate(hasURL, isSecure bool) error {
 	if f.ListServices && f.ListMethods {
 		return fmt.Errorf("flags --%s and --%s are mutually exclusive", listServicesFlagName, listMethodsFlagName)
 	}
+	if f.Describe != "" || f.isHealthCheck() {
+		if (f.ListServices || f.ListMethods) || (f.Describe != "" && f.isHealthCheck()) {
+			return fmt.Errorf(
+				"flags --%s, --%s, --%s, and --%s are mutually exclusive",
+				listServicesFlagName, listMethodsFlagName, describeFlagName, healthFlagName)
+		}
+		if f.Data != "" || f.isLoadTest() || f.Record != "" || f.Replay != "" || f.Interactive || f.HTTPTranscoding {
+			return fmt.Errorf(
+				"--%s and --%s cannot be used with --%s, --%s, --%s, --%s, --%s, --%s, or --%s",
+				describeFlagName, healthFlagName, dataFlagName, loadRequestsFlagName, loadDurationFlagName,
+				recordFlagName, replayFlagName, interactiveFlagName, httpTranscodingFlagName)
+		}
+	}
+	if f.HealthWatch && !f.isHealthCheck() {
+		return fmt.Errorf("--%s should not be used unless --%s is set", healthWatchFlagName, healthFlagName)
+	}
 
 	if (f.Key != "" || f.Cert != "" || f.CACert != "" || f.ServerName != "" || f.flagSet.Changed(insecureFlagName)) &&
 		!isSecure {
@@ -618,6 +902,14 @@ func (f *flags) validate(hasURL, isSecure bool) error {
 			"--%s value must be one of %q, %q, or %q",
 			protocolFlagName, connect.ProtocolConnect, connect.ProtocolGRPC, connect.ProtocolGRPCWeb)
 	}
+	if f.HTTPTranscoding {
+		if f.flagSet.Changed(protocolFlagName) {
+			return fmt.Errorf("--%s and --%s flags are mutually exclusive; they may not both be specified", protocolFlagName, httpTranscodingFlagName)
+		}
+		if f.ListServices || f.ListMethods {
+			return fmt.Errorf("--%s cannot be used with --%s or --%s", httpTranscodingFlagName, listServicesFlagName, listMethodsFlagName)
+		}
+	}
 
 	if f.NoKeepAlive && f.flagSet.Changed(keepAliveFlagName) {
 		return fmt.Errorf("--%s should not be specified if keepalive is disabled", keepAliveFlagName)
@@ -630,6 +922,87 @@ func (f *flags) validate(hasURL, isSecure bool) error {
 		return fmt.Errorf("--%s value must be positive", connectTimeoutFlagName)
 	}
 
+	if _, err := buffetch.ParseMessageEncoding(f.DataFormat); err != nil {
+		return fmt.Errorf(
+			"--%s value must be one of %s",
+			dataFormatFlagName,
+			buffetch.MessageFormatsString,
+		)
+	}
+	if _, err := buffetch.ParseMessageEncoding(f.OutputFormat); err != nil {
+		return fmt.Errorf(
+			"--%s value must be one of %s",
+			outputFormatFlagName,
+			buffetch.MessageFormatsString,
+		)
+	}
+
+	if f.LoadRequests < 0 || (f.LoadRequests == 0 && f.flagSet.Changed(loadRequestsFlagName)) {
+		return fmt.Errorf("--%s value must be positive", loadRequestsFlagName)
+	}
+	if f.LoadDurationSeconds < 0 || (f.LoadDurationSeconds == 0 && f.flagSet.Changed(loadDurationFlagName)) {
+		return fmt.Errorf("--%s value must be positive", loadDurationFlagName)
+	}
+	if f.isLoadTest() {
+		if f.ListServices || f.ListMethods {
+			return fmt.Errorf(
+				"load testing flags (--%s, --%s) cannot be used with --%s or --%s",
+				loadRequestsFlagName, loadDurationFlagName, listServicesFlagName, listMethodsFlagName)
+		}
+		if f.LoadConcurrency <= 0 {
+			return fmt.Errorf("--%s value must be positive", loadConcurrencyFlagName)
+		}
+		if f.LoadRate < 0 || (f.LoadRate == 0 && f.flagSet.Changed(loadRateFlagName)) {
+			return fmt.Errorf("--%s value must be positive", loadRateFlagName)
+		}
+		if !slices.Contains(bufcurl.AllLoadTestReportFormatStrings, f.LoadReportFormat) {
+			return fmt.Errorf(
+				"--%s value must be one of %s",
+				loadReportFormatFlagName,
+				xstrings.SliceToHumanStringOrQuoted(bufcurl.AllLoadTestReportFormatStrings),
+			)
+		}
+	} else if f.flagSet.Changed(loadConcurrencyFlagName) || f.flagSet.Changed(loadRateFlagName) || f.flagSet.Changed(loadReportFormatFlagName) {
+		return fmt.Errorf(
+			"load testing flags (--%s, --%s, --%s) should not be used unless --%s or --%s is set",
+			loadConcurrencyFlagName, loadRateFlagName, loadReportFormatFlagName, loadRequestsFlagName, loadDurationFlagName)
+	}
+
+	if f.Record != "" || f.Replay != "" {
+		if f.Record != "" && f.Replay != "" {
+			return fmt.Errorf("--%s and --%s flags are mutually exclusive; they may not both be specified", recordFlagName, replayFlagName)
+		}
+		if f.ListServices || f.ListMethods || f.isLoadTest() || f.HTTPTranscoding {
+			return fmt.Errorf(
+				"--%s and --%s cannot be used with --%s, --%s, --%s, --%s, or --%s",
+				recordFlagName, replayFlagName, listServicesFlagName, listMethodsFlagName,
+				loadRequestsFlagName, loadDurationFlagName, httpTranscodingFlagName)
+		}
+	}
+	if f.Replay != "" && f.Data != "" {
+		return fmt.Errorf("--%s cannot be used with --%s, the recorded requests are sent", dataFlagName, replayFlagName)
+	}
+	if len(f.ReplayIgnore) > 0 && f.Replay == "" {
+		return fmt.Errorf("--%s should not be used unless --%s is set", replayIgnoreFlagName, replayFlagName)
+	}
+
+	if f.Interactive {
+		if f.Data != "" || f.flagSet.Changed(dataFormatFlagName) || f.Output != "" {
+			return fmt.Errorf(
+				"--%s cannot be used with --%s, --%s, or --%s",
+				interactiveFlagName, dataFlagName, dataFormatFlagName, outputFlagName)
+		}
+		if f.ListServices || f.ListMethods || f.isLoadTest() || f.Record != "" || f.Replay != "" || f.HTTPTranscoding {
+			return fmt.Errorf(
+				"--%s cannot be used with --%s, --%s, --%s, --%s, --%s, --%s, or --%s",
+				interactiveFlagName, listServicesFlagName, listMethodsFlagName, loadRequestsFlagName,
+				loadDurationFlagName, recordFlagName, replayFlagName, httpTranscodingFlagName)
+		}
+		if schemaIsStdin {
+			return fmt.Errorf("--%s cannot be used when --%s indicates reading from stdin", interactiveFlagName, schemaFlagName)
+		}
+	}
+
 	var dataFile string
 	if strings.HasPrefix(f.Data, "@") {
 		dataFile = strings.TrimPrefix(f.Data, "@")
@@ -645,6 +1018,9 @@ func (f *flags) validate(hasURL, isSecure bool) error {
 	if err := validateHeaders(f.Headers, headerFlagName, schemaIsStdin, false, headerFiles); err != nil {
 		return err
 	}
+	if _, ok := headerFiles["-"]; ok && f.Interactive {
+		return fmt.Errorf("--%s cannot be used when --%s indicates reading from stdin", interactiveFlagName, headerFlagName)
+	}
 	reflectHeaderFiles := map[string]struct{}{}
 	if err := validateHeaders(f.ReflectHeaders, reflectHeaderFlagName, schemaIsStdin, true, reflectHeaderFiles); err != nil {
 		return err
@@ -658,6 +1034,25 @@ func (f *flags) validate(hasURL, isSecure bool) error {
 	return nil
 }
 
+// isHealthCheck returns true if the flags enable health checking mode.
+func (f *flags) isHealthCheck() bool {
+	return f.flagSet.Changed(healthFlagName)
+}
+
+// healthService returns the name of the service to check the health of, or
+// the empty string to check the health of the server as a whole.
+func (f *flags) healthService() string {
+	if f.Health == healthServerFlagValue {
+		return ""
+	}
+	return f.Health
+}
+
+// isLoadTest returns true if the flags enable load testing mode.
+func (f *flags) isLoadTest() bool {
+	return f.LoadRequests > 0 || f.LoadDurationSeconds > 0
+}
+
 func (f *flags) determineCredentials(
 	ctx context.Context,
 	container app.Container,
@@ -869,7 +1264,7 @@ func run(ctx context.Context, container appext.Container, f *flags) (err error)
 	}
 	var service, method, baseURL string
 	switch {
-	case f.ListServices || f.ListMethods:
+	case f.ListServices || f.ListMethods || f.Describe != "" || f.isHealthCheck():
 		baseURL = urlArg
 	default:
 		service, method, baseURL, err = parseEndpointURL(urlArg)
@@ -878,9 +1273,23 @@ func run(ctx context.Context, container appext.Container, f *flags) (err error)
 		return err
 	}
 
+	var terminal *os.File
+	if f.Interactive {
+		file, ok := container.Stdin().(*os.File)
+		if !ok || !term.IsTerminal(int(file.Fd())) {
+			return fmt.Errorf("--%s requires stdin to be a terminal", interactiveFlagName)
+		}
+		terminal = file
+	}
+
 	var verbosePrinter verbose.Printer = verbose.NopPrinter
 	if f.Verbose {
-		verbosePrinter = verbose.NewPrinter(container.Stderr(), container.AppName())
+		var verboseOutput io.Writer = container.Stderr()
+		if f.Interactive {
+			// The terminal is in raw mode while the RPC is invoked.
+			verboseOutput = &rawTerminalWriter{writer: verboseOutput}
+		}
+		verbosePrinter = verbose.NewPrinter(verboseOutput, container.AppName())
 	}
 
 	var clientOptions []connect.ClientOption
@@ -980,6 +1389,14 @@ func run(ctx context.Context, container appext.Container, f *flags) (err error)
 		}
 	}
 
+	if f.isHealthCheck() {
+		transport, err := makeTransportOnce()
+		if err != nil {
+			return err
+		}
+		return runHealthCheck(ctx, f, transport, clientOptions, baseURL, requestHeaders, output)
+	}
+
 	resolvers := make([]bufcurl.Resolver, 0, len(f.Schemas)+2)
 	if f.Reflect {
 		reflectHeaders, _, err := bufcurl.LoadHeaders(f.ReflectHeaders, "", requestHeaders)
@@ -1065,6 +1482,13 @@ func run(ctx context.Context, container appext.Container, f *flags) (err error)
 			}
 		}
 		return nil
+	case f.Describe != "":
+		definition, err := bufcurl.DescribeSymbol(res, f.Describe)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(output, definition)
+		return err
 	default:
 		// Invoke RPC
 		methodDescriptor, err := bufcurl.ResolveMethodDescriptor(res, service, method)
@@ -1075,9 +1499,345 @@ func run(ctx context.Context, container appext.Container, f *flags) (err error)
 		if err != nil {
 			return err
 		}
-		invoker := bufcurl.NewInvoker(container, verbosePrinter, methodDescriptor, res, f.EmitDefaults, transport, clientOptions, urlArg, output)
-		return invoker.Invoke(ctx, dataSource, dataReader, requestHeaders)
+		dataFormat, err := buffetch.ParseMessageEncoding(f.DataFormat)
+		if err != nil {
+			return err
+		}
+		outputFormat, err := buffetch.ParseMessageEncoding(f.OutputFormat)
+		if err != nil {
+			return err
+		}
+		invokerOptions := []bufcurl.InvokerOption{
+			bufcurl.InvokerWithDataFormat(dataFormat),
+		}
+		if f.Validate || f.ValidateResponses {
+			validator, err := protovalidate.New(protovalidate.WithExtensionTypeResolver(res))
+			if err != nil {
+				return err
+			}
+			if f.Validate {
+				invokerOptions = append(invokerOptions, bufcurl.InvokerWithRequestValidation(validator))
+			}
+			if f.ValidateResponses {
+				invokerOptions = append(invokerOptions, bufcurl.InvokerWithResponseValidation(validator))
+			}
+		}
+		if f.HTTPTranscoding {
+			invokerOptions = append(invokerOptions, bufcurl.InvokerWithHTTPTranscoding(baseURL))
+		}
+		if f.Interactive {
+			return runInteractive(
+				ctx,
+				container,
+				f,
+				terminal,
+				verbosePrinter,
+				methodDescriptor,
+				res,
+				transport,
+				clientOptions,
+				urlArg,
+				requestHeaders,
+				append(invokerOptions, bufcurl.InvokerWithOutputFormat(outputFormat)),
+			)
+		}
+		if f.isLoadTest() {
+			return runLoadTest(
+				ctx,
+				container,
+				f,
+				methodDescriptor,
+				res,
+				transport,
+				clientOptions,
+				urlArg,
+				dataSource,
+				dataReader,
+				requestHeaders,
+				output,
+				invokerOptions,
+			)
+		}
+		if f.Replay != "" {
+			return runReplay(
+				ctx,
+				container,
+				f,
+				methodDescriptor,
+				res,
+				transport,
+				clientOptions,
+				urlArg,
+				requestHeaders,
+				output,
+				invokerOptions,
+			)
+		}
+		var recorder *bufcurl.Recorder
+		if f.Record != "" {
+			recorder = bufcurl.NewRecorder(methodDescriptor, res)
+			clientOptions = append(clientOptions, connect.WithInterceptors(recorder))
+		}
+		invoker := bufcurl.NewInvoker(
+			container,
+			verbosePrinter,
+			methodDescriptor,
+			res,
+			f.EmitDefaults,
+			transport,
+			clientOptions,
+			urlArg,
+			output,
+			append(invokerOptions, bufcurl.InvokerWithOutputFormat(outputFormat))...,
+		)
+		err = invoker.Invoke(ctx, dataSource, dataReader, requestHeaders)
+		if recorder != nil {
+			// The recording is written even if the RPC failed, as its status is recorded.
+			err = errors.Join(err, writeRecording(f.Record, recorder.Recording()))
+		}
+		return err
+	}
+}
+
+func runReplay(
+	ctx context.Context,
+	container appext.Container,
+	f *flags,
+	methodDescriptor protoreflect.MethodDescriptor,
+	res bufcurl.Resolver,
+	transport connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	urlArg string,
+	requestHeaders http.Header,
+	output io.Writer,
+	invokerOptions []bufcurl.InvokerOption,
+) error {
+	recordingFile, err := os.Open(f.Replay)
+	if err != nil {
+		return bufcurl.ErrorHasFilename(err, f.Replay)
+	}
+	defer recordingFile.Close()
+	expected, err := bufcurl.ReadRecording(recordingFile)
+	if err != nil {
+		return bufcurl.ErrorHasFilename(err, f.Replay)
+	}
+	if expected.Method != string(methodDescriptor.FullName()) {
+		return fmt.Errorf("%s is a recording of %s, not of %s", f.Replay, expected.Method, methodDescriptor.FullName())
+	}
+	// Headers given on the command line take precedence over recorded headers.
+	headers := expected.RequestHeaders.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	maps.Copy(headers, requestHeaders)
+	var data bytes.Buffer
+	for _, request := range expected.Requests {
+		data.Write(request)
+		data.WriteByte('\n')
+	}
+	recorder := bufcurl.NewRecorder(methodDescriptor, res)
+	invoker := bufcurl.NewInvoker(
+		container,
+		verbose.NopPrinter,
+		methodDescriptor,
+		res,
+		f.EmitDefaults,
+		transport,
+		append(slices.Clone(clientOptions), connect.WithInterceptors(recorder)),
+		urlArg,
+		io.Discard,
+		append(
+			invokerOptions,
+			// Requests are recorded in JSON.
+			bufcurl.InvokerWithDataFormat(buffetch.MessageEncodingJSON),
+			// RPC errors are compared against the recorded status.
+			bufcurl.InvokerWithRPCErrors(),
+		)...,
+	)
+	if err := invoker.Invoke(ctx, f.Replay, &data, headers); err != nil {
+		var connErr *connect.Error
+		if !errors.As(err, &connErr) {
+			return err
+		}
+	}
+	diff, err := bufcurl.DiffRecordings(expected, recorder.Recording(), methodDescriptor, res, f.ReplayIgnore)
+	if err != nil {
+		return err
+	}
+	if diff == nil {
+		_, err := fmt.Fprintf(output, "Responses of %s match %s\n", methodDescriptor.FullName(), f.Replay)
+		return err
+	}
+	if _, err := output.Write(diff); err != nil {
+		return err
+	}
+	return fmt.Errorf("responses of %s do not match %s", methodDescriptor.FullName(), f.Replay)
+}
+
+func runHealthCheck(
+	ctx context.Context,
+	f *flags,
+	transport connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	baseURL string,
+	requestHeaders http.Header,
+	output io.Writer,
+) error {
+	service := f.healthService()
+	subject := "server"
+	if service != "" {
+		subject = fmt.Sprintf("service %q", service)
+	}
+	if !f.HealthWatch {
+		status, err := bufcurl.CheckHealth(ctx, transport, clientOptions, baseURL, service, requestHeaders)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(output, status); err != nil {
+			return err
+		}
+		if status != bufcurl.HealthStatusServing {
+			return fmt.Errorf("%s is %s", subject, status)
+		}
+		return nil
+	}
+	var lastStatus bufcurl.HealthStatus
+	var writeErr error
+	if err := bufcurl.WatchHealth(ctx, transport, clientOptions, baseURL, service, requestHeaders, func(status bufcurl.HealthStatus) bool {
+		lastStatus = status
+		if _, writeErr = fmt.Fprintln(output, status); writeErr != nil {
+			return false
+		}
+		return status != bufcurl.HealthStatusServing
+	}); err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
 	}
+	if lastStatus != bufcurl.HealthStatusServing {
+		return fmt.Errorf("%s is %s", subject, lastStatus)
+	}
+	return nil
+}
+
+func runInteractive(
+	ctx context.Context,
+	container appext.Container,
+	f *flags,
+	terminal *os.File,
+	verbosePrinter verbose.Printer,
+	methodDescriptor protoreflect.MethodDescriptor,
+	res bufcurl.Resolver,
+	transport connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	urlArg string,
+	requestHeaders http.Header,
+	invokerOptions []bufcurl.InvokerOption,
+) (retErr error) {
+	if !methodDescriptor.IsStreamingClient() {
+		return fmt.Errorf("--%s requires a client-streaming or bidirectional-streaming method, but %s is not", interactiveFlagName, methodDescriptor.FullName())
+	}
+	fd := int(terminal.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, term.Restore(fd, state))
+	}()
+	repl := bufcurl.NewREPL(terminal, container.Stdout(), methodDescriptor, res)
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 && height > 0 {
+		if err := repl.SetSize(width, height); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(repl, "Invoking %s.\n%s", methodDescriptor.FullName(), bufcurl.REPLHelp); err != nil {
+		return err
+	}
+	invoker := bufcurl.NewInvoker(
+		container,
+		verbosePrinter,
+		methodDescriptor,
+		res,
+		f.EmitDefaults,
+		transport,
+		clientOptions,
+		urlArg,
+		repl,
+		append(invokerOptions, bufcurl.InvokerWithREPL(repl))...,
+	)
+	return invoker.Invoke(ctx, "", nil, requestHeaders)
+}
+
+func writeRecording(path string, recording *bufcurl.Recording) (retErr error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return bufcurl.ErrorHasFilename(err, path)
+	}
+	defer func() {
+		retErr = errors.Join(retErr, file.Close())
+	}()
+	return bufcurl.WriteRecording(file, recording)
+}
+
+func runLoadTest(
+	ctx context.Context,
+	container appext.Container,
+	f *flags,
+	methodDescriptor protoreflect.MethodDescriptor,
+	res bufcurl.Resolver,
+	transport connect.HTTPClient,
+	clientOptions []connect.ClientOption,
+	urlArg string,
+	dataSource string,
+	dataReader io.Reader,
+	requestHeaders http.Header,
+	output io.Writer,
+	invokerOptions []bufcurl.InvokerOption,
+) error {
+	// Every request is sent with the same data, so we read it upfront.
+	var data []byte
+	if dataReader != nil {
+		var err error
+		data, err = io.ReadAll(dataReader)
+		if err != nil {
+			return bufcurl.ErrorHasFilename(err, dataSource)
+		}
+	}
+	invokerOptions = append(slices.Clone(invokerOptions), bufcurl.InvokerWithRPCErrors())
+	newInvoker := func() bufcurl.Invoker {
+		return bufcurl.NewInvoker(
+			container,
+			// Verbose output for every request would drown out everything else.
+			verbose.NopPrinter,
+			methodDescriptor,
+			res,
+			f.EmitDefaults,
+			transport,
+			clientOptions,
+			urlArg,
+			io.Discard,
+			invokerOptions...,
+		)
+	}
+	loadTestOptions := []bufcurl.LoadTestOption{
+		bufcurl.LoadTestWithConcurrency(f.LoadConcurrency),
+	}
+	if f.LoadRequests > 0 {
+		loadTestOptions = append(loadTestOptions, bufcurl.LoadTestWithRequests(f.LoadRequests))
+	}
+	if f.LoadDurationSeconds > 0 {
+		loadTestOptions = append(loadTestOptions, bufcurl.LoadTestWithDuration(secondsToDuration(f.LoadDurationSeconds)))
+	}
+	if f.LoadRate > 0 {
+		loadTestOptions = append(loadTestOptions, bufcurl.LoadTestWithRate(f.LoadRate))
+	}
+	report, err := bufcurl.RunLoadTest(ctx, newInvoker, dataSource, data, requestHeaders, loadTestOptions...)
+	if err != nil {
+		return err
+	}
+	return bufcurl.PrintLoadTestReport(output, report, f.LoadReportFormat)
 }
 
 func makeHTTPRoundTripper(f *flags, isSecure bool, authority string, printer verbose.Printer) (http.RoundTripper, error) {
@@ -1208,3 +1968,16 @@ func makeHTTP3RoundTripper(f *flags, authority string, printer verbose.Printer)
 func secondsToDuration(secs float64) time.Duration {
 	return time.Duration(float64(time.Second) * secs)
 }
+
+// rawTerminalWriter writes to a terminal in raw mode, in which a newline does
+// not return the cursor to the start of the line.
+type rawTerminalWriter struct {
+	writer io.Writer
+}
+
+func (w *rawTerminalWriter) Write(data []byte) (int, error) {
+	if _, err := w.writer.Write(bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+)
+
+const baselineVersionV1 = "v1"
+
+// baselineKey identifies a violation in a baseline.
+//
+// Violations are identified by their rule, file and symbol rather than their location,
+// so that the baseline stays valid when unrelated parts of the file are edited.
+type baselineKey struct {
+	Rule   string `json:"rule"`
+	File   string `json:"file"`
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// baselineEntry is an entry of a baseline.
+//
+// Count is the number of violations with the key, as several violations can have the
+// same rule, file and symbol. A Count of zero is the same as one, so that the count is
+// only written for keys with several violations.
+type baselineEntry struct {
+	baselineKey
+	Count int `json:"count,omitempty"`
+}
+
+// fixedBaselineEntry is an entry of a baseline whose violations are fixed, in full or in part.
+type fixedBaselineEntry struct {
+	baselineKey
+	// Count is the number of violations of the entry.
+	Count int
+	// FixedCount is the number of violations of the entry that are fixed.
+	FixedCount int
+}
+
+// externalBaselineFileV1 is the JSON representation of a baseline file.
+type externalBaselineFileV1 struct {
+	Version string          `json:"version"`
+	Entries []baselineEntry `json:"entries"`
+}
+
+func newBaselineKey(fileAnnotation bufanalysis.FileAnnotation) baselineKey {
+	var file string
+	if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+		file = fileInfo.Path()
+	}
+	return baselineKey{
+		Rule:   fileAnnotation.Type(),
+		File:   file,
+		Symbol: fileAnnotation.Symbol(),
+	}
+}
+
+func compareBaselineKeys(one baselineKey, two baselineKey) int {
+	return cmp.Or(
+		cmp.Compare(one.File, two.File),
+		cmp.Compare(one.Rule, two.Rule),
+		cmp.Compare(one.Symbol, two.Symbol),
+	)
+}
+
+// writeBaseline writes a baseline of the FileAnnotations to the file at the path.
+func writeBaseline(path string, fileAnnotations []bufanalysis.FileAnnotation) error {
+	keyToCount := make(map[baselineKey]int, len(fileAnnotations))
+	for _, fileAnnotation := range fileAnnotations {
+		keyToCount[newBaselineKey(fileAnnotation)]++
+	}
+	// Always write an array, even if there are no entries.
+	entries := make([]baselineEntry, 0, len(keyToCount))
+	for key, count := range keyToCount {
+		entry := baselineEntry{baselineKey: key}
+		if count > 1 {
+			entry.Count = count
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(one baselineEntry, two baselineEntry) int {
+		return compareBaselineKeys(one.baselineKey, two.baselineKey)
+	})
+	externalBaselineFile := &externalBaselineFileV1{
+		Version: baselineVersionV1,
+		Entries: entries,
+	}
+	data, err := json.MarshalIndent(externalBaselineFile, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// readBaseline reads the baseline file at the path, and returns the number of violations
+// of each key of the baseline.
+func readBaseline(path string) (map[baselineKey]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var externalBaselineFile externalBaselineFileV1
+	if err := json.Unmarshal(data, &externalBaselineFile); err != nil {
+		return nil, fmt.Errorf("could not parse baseline file %s: %w", path, err)
+	}
+	if externalBaselineFile.Version != baselineVersionV1 {
+		return nil, fmt.Errorf("unknown version %q for baseline file %s", externalBaselineFile.Version, path)
+	}
+	keyToCount := make(map[baselineKey]int, len(externalBaselineFile.Entries))
+	for _, entry := range externalBaselineFile.Entries {
+		if entry.Count < 0 {
+			return nil, fmt.Errorf("invalid count %d for entry %s: %s of baseline file %s", entry.Count, entry.File, entry.Rule, path)
+		}
+		keyToCount[entry.baselineKey] += max(entry.Count, 1)
+	}
+	return keyToCount, nil
+}
+
+// applyBaseline removes the FileAnnotations that are in the baseline, and returns the
+// remaining FileAnnotations along with the entries of the baseline that are fixed.
+//
+// Each key of the baseline only removes as many FileAnnotations as its count. The
+// FileAnnotations with the key beyond that are new violations, and are kept.
+//
+// Only entries for the linted files are considered fixed, as the violations in the other
+// files are not known.
+func applyBaseline(
+	fileAnnotations []bufanalysis.FileAnnotation,
+	keyToCount map[baselineKey]int,
+	lintedPathSet map[string]struct{},
+) ([]bufanalysis.FileAnnotation, []fixedBaselineEntry) {
+	var remainingFileAnnotations []bufanalysis.FileAnnotation
+	keyToMatchedCount := make(map[baselineKey]int)
+	for _, fileAnnotation := range fileAnnotations {
+		key := newBaselineKey(fileAnnotation)
+		if keyToMatchedCount[key] < keyToCount[key] {
+			keyToMatchedCount[key]++
+			continue
+		}
+		remainingFileAnnotations = append(remainingFileAnnotations, fileAnnotation)
+	}
+	var fixedEntries []fixedBaselineEntry
+	for key, count := range keyToCount {
+		matchedCount := keyToMatchedCount[key]
+		if matchedCount == count {
+			continue
+		}
+		if _, ok := lintedPathSet[key.File]; ok || key.File == "" {
+			fixedEntries = append(
+				fixedEntries,
+				fixedBaselineEntry{
+					baselineKey: key,
+					Count:       count,
+					FixedCount:  count - matchedCount,
+				},
+			)
+		}
+	}
+	slices.SortFunc(fixedEntries, func(one fixedBaselineEntry, two fixedBaselineEntry) int {
+		return compareBaselineKeys(one.baselineKey, two.baselineKey)
+	})
+	return remainingFileAnnotations, fixedEntries
+}
+
+// printFixedBaselineEntries prints the entries of the baseline that are fixed.
+func printFixedBaselineEntries(writer io.Writer, path string, fixedEntries []fixedBaselineEntry) error {
+	if _, err := fmt.Fprintf(
+		writer,
+		"The following entries of the baseline %s are fixed, and can be removed by writing the baseline again with --%s:\n",
+		path,
+		writeBaselineFlagName,
+	); err != nil {
+		return err
+	}
+	for _, fixedEntry := range fixedEntries {
+		symbol := fixedEntry.Symbol
+		if symbol == "" {
+			symbol = "-"
+		}
+		var suffix string
+		if fixedEntry.FixedCount < fixedEntry.Count {
+			suffix = fmt.Sprintf(" (%d of %d violations fixed)", fixedEntry.FixedCount, fixedEntry.Count)
+		}
+		if _, err := fmt.Fprintf(writer, "  %s: %s %s%s\n", fixedEntry.File, fixedEntry.Rule, symbol, suffix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaselineCount(t *testing.T) {
+	t.Parallel()
+	// File option violations all have the "option" symbol, so they share a key.
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		newTestFileAnnotation("a.proto", 5, "FILE_OPTION", "option"),
+		newTestFileAnnotation("a.proto", 6, "FILE_OPTION", "option"),
+		newTestFileAnnotation("a.proto", 10, "FIELD_LOWER_SNAKE_CASE", "a.Foo.fooBar"),
+	}
+	baselinePath := filepath.Join(t.TempDir(), "buf.lint-baseline.json")
+	require.NoError(t, writeBaseline(baselinePath, fileAnnotations))
+	keyToCount, err := readBaseline(baselinePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		map[baselineKey]int{
+			{Rule: "FILE_OPTION", File: "a.proto", Symbol: "option"}:                  2,
+			{Rule: "FIELD_LOWER_SNAKE_CASE", File: "a.proto", Symbol: "a.Foo.fooBar"}: 1,
+		},
+		keyToCount,
+	)
+	lintedPathSet := map[string]struct{}{"a.proto": {}}
+
+	// The same violations are all in the baseline.
+	remainingFileAnnotations, fixedEntries := applyBaseline(fileAnnotations, keyToCount, lintedPathSet)
+	assert.Empty(t, remainingFileAnnotations)
+	assert.Empty(t, fixedEntries)
+
+	// A new violation with the same key as a baseline entry is reported.
+	newFileAnnotation := newTestFileAnnotation("a.proto", 7, "FILE_OPTION", "option")
+	remainingFileAnnotations, fixedEntries = applyBaseline(
+		append(fileAnnotations, newFileAnnotation),
+		keyToCount,
+		lintedPathSet,
+	)
+	assert.Equal(t, []bufanalysis.FileAnnotation{newFileAnnotation}, remainingFileAnnotations)
+	assert.Empty(t, fixedEntries)
+
+	// One of the two violations of an entry is fixed.
+	remainingFileAnnotations, fixedEntries = applyBaseline(fileAnnotations[1:], keyToCount, lintedPathSet)
+	assert.Empty(t, remainingFileAnnotations)
+	assert.Equal(
+		t,
+		[]fixedBaselineEntry{
+			{
+				baselineKey: baselineKey{Rule: "FILE_OPTION", File: "a.proto", Symbol: "option"},
+				Count:       2,
+				FixedCount:  1,
+			},
+		},
+		fixedEntries,
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, printFixedBaselineEntries(buffer, baselinePath, fixedEntries))
+	assert.Contains(t, buffer.String(), "  a.proto: FILE_OPTION option (1 of 2 violations fixed)\n")
+}
+
+func newTestFileAnnotation(path string, line int, rule string, symbol string) bufanalysis.FileAnnotation {
+	return bufanalysis.NewFileAnnotation(
+		&testFileInfo{path: path},
+		line,
+		1,
+		line,
+		1,
+		rule,
+		"message",
+		"",
+		bufanalysis.FileAnnotationWithSymbol(symbol),
+	)
+}
+
+type testFileInfo struct {
+	path string
+}
+
+func (f *testFileInfo) Path() string {
+	return f.path
+}
+
+func (f *testFileInfo) ExternalPath() string {
+	return f.path
+}
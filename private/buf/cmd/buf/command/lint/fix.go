@@ -0,0 +1,323 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bufbuild/buf/private/buf/bufformat"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+)
+
+// editedFile is a file along with the edits to apply to it.
+type editedFile struct {
+	path         string
+	externalPath string
+	edits        []bufanalysis.Edit
+}
+
+// fixFileAnnotations selects the edits to apply to fix the FileAnnotations, and returns
+// the edits by file along with the FileAnnotations that could not be fixed.
+//
+// The edits of a FileAnnotation are only selected if none of them conflict with the edits
+// already selected for other FileAnnotations, as all of them need to be applied together.
+func fixFileAnnotations(
+	fileAnnotations []bufanalysis.FileAnnotation,
+) ([]*editedFile, []bufanalysis.FileAnnotation) {
+	var unfixedFileAnnotations []bufanalysis.FileAnnotation
+	externalPathToEditedFile := make(map[string]*editedFile)
+	var editedFiles []*editedFile
+	for _, fileAnnotation := range fileAnnotations {
+		edits := fileAnnotation.Edits()
+		if len(edits) == 0 || !canSelectEdits(externalPathToEditedFile, edits) {
+			unfixedFileAnnotations = append(unfixedFileAnnotations, fileAnnotation)
+			continue
+		}
+		for _, edit := range edits {
+			externalPath := edit.FileInfo().ExternalPath()
+			file, ok := externalPathToEditedFile[externalPath]
+			if !ok {
+				file = &editedFile{
+					path:         edit.FileInfo().Path(),
+					externalPath: externalPath,
+				}
+				externalPathToEditedFile[externalPath] = file
+				editedFiles = append(editedFiles, file)
+			}
+			if !slices.ContainsFunc(file.edits, func(selectedEdit bufanalysis.Edit) bool {
+				return editsEqual(edit, selectedEdit)
+			}) {
+				file.edits = append(file.edits, edit)
+			}
+		}
+	}
+	return editedFiles, unfixedFileAnnotations
+}
+
+// canSelectEdits returns true if the edits do not conflict with each other, nor with
+// the edits already selected. Edits that are equal do not conflict.
+func canSelectEdits(externalPathToEditedFile map[string]*editedFile, edits []bufanalysis.Edit) bool {
+	for i, edit := range edits {
+		if edit.FileInfo() == nil || edit.FileInfo().ExternalPath() == "" {
+			return false
+		}
+		otherEdits := edits[:i]
+		if file, ok := externalPathToEditedFile[edit.FileInfo().ExternalPath()]; ok {
+			otherEdits = append(slices.Clone(otherEdits), file.edits...)
+		}
+		for _, otherEdit := range otherEdits {
+			if edit.FileInfo().ExternalPath() == otherEdit.FileInfo().ExternalPath() &&
+				!editsEqual(edit, otherEdit) &&
+				editsConflict(edit, otherEdit) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func editsEqual(one bufanalysis.Edit, two bufanalysis.Edit) bool {
+	return one.FileInfo().ExternalPath() == two.FileInfo().ExternalPath() &&
+		one.StartLine() == two.StartLine() &&
+		one.StartColumn() == two.StartColumn() &&
+		one.EndLine() == two.EndLine() &&
+		one.EndColumn() == two.EndColumn() &&
+		one.NewText() == two.NewText()
+}
+
+// editsConflict returns true if the ranges of the edits overlap, or if both edits are
+// insertions at the same position, in which case the order of the edits would matter.
+//
+// An insertion at the start of the range of another edit does not conflict with it, as
+// insertions are applied first.
+func editsConflict(one bufanalysis.Edit, two bufanalysis.Edit) bool {
+	oneStart, oneEnd := editStartAndEnd(one)
+	twoStart, twoEnd := editStartAndEnd(two)
+	if oneStart == oneEnd && twoStart == twoEnd {
+		return oneStart == twoStart
+	}
+	return comparePositions(oneStart, twoEnd) < 0 && comparePositions(twoStart, oneEnd) < 0
+}
+
+func editStartAndEnd(edit bufanalysis.Edit) ([2]int, [2]int) {
+	return [2]int{edit.StartLine(), edit.StartColumn()}, [2]int{edit.EndLine(), edit.EndColumn()}
+}
+
+func comparePositions(one [2]int, two [2]int) int {
+	if one[0] != two[0] {
+		return one[0] - two[0]
+	}
+	return one[1] - two[1]
+}
+
+// applyEdits applies the edits to the data of the file, and then formats the parts of
+// the file that were edited.
+func applyEdits(editedFile *editedFile, data []byte) ([]byte, error) {
+	type offsetEdit struct {
+		start   int
+		end     int
+		newText string
+	}
+	offsetEdits := make([]offsetEdit, 0, len(editedFile.edits))
+	for _, edit := range editedFile.edits {
+		start, err := positionToOffset(data, edit.StartLine(), edit.StartColumn())
+		if err != nil {
+			return nil, err
+		}
+		end, err := positionToOffset(data, edit.EndLine(), edit.EndColumn())
+		if err != nil {
+			return nil, err
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid edit of %s: end %d:%d is before start %d:%d", editedFile.externalPath, edit.EndLine(), edit.EndColumn(), edit.StartLine(), edit.StartColumn())
+		}
+		if edit.NewText() == "" {
+			start, end = extendDeletionToLine(data, start, end)
+		}
+		offsetEdits = append(offsetEdits, offsetEdit{start: start, end: end, newText: edit.NewText()})
+	}
+	slices.SortFunc(offsetEdits, func(one offsetEdit, two offsetEdit) int {
+		if one.start != two.start {
+			return one.start - two.start
+		}
+		// Insertions go before the edit of the range that starts at the same offset.
+		return (one.end - one.start) - (two.end - two.start)
+	})
+	var buffer bytes.Buffer
+	// The range of the result that was edited, which is the range to format.
+	formatStart, formatEnd := -1, -1
+	var offset int
+	for _, offsetEdit := range offsetEdits {
+		// Edits are selected so that they do not conflict, but deletions extended to
+		// whole lines may still overlap other edits on those lines.
+		if offsetEdit.start < offset {
+			return nil, fmt.Errorf("could not fix %s: edits overlap at offset %d", editedFile.externalPath, offsetEdit.start)
+		}
+		buffer.Write(data[offset:offsetEdit.start])
+		if formatStart < 0 {
+			formatStart = buffer.Len()
+		}
+		buffer.WriteString(offsetEdit.newText)
+		formatEnd = buffer.Len()
+		offset = offsetEdit.end
+	}
+	buffer.Write(data[offset:])
+	edited := buffer.String()
+	if formatStart < 0 {
+		return []byte(edited), nil
+	}
+	fileNode, err := parser.Parse(editedFile.externalPath, strings.NewReader(edited), reporter.NewHandler(nil))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s after applying fixes: %w", editedFile.externalPath, err)
+	}
+	formatEdits, err := bufformat.FormatFileNodeRange(fileNode, formatStart, formatEnd)
+	if err != nil {
+		return nil, err
+	}
+	buffer.Reset()
+	offset = 0
+	for _, formatEdit := range formatEdits {
+		buffer.WriteString(edited[offset:formatEdit.Start])
+		buffer.WriteString(formatEdit.NewText)
+		offset = formatEdit.End
+	}
+	buffer.WriteString(edited[offset:])
+	return buffer.Bytes(), nil
+}
+
+// extendDeletionToLine extends the deletion of the range [start, end) to its entire line,
+// including the newline, if the rest of the line is only whitespace. This way, deleting a
+// declaration does not leave an empty line behind.
+func extendDeletionToLine(data []byte, start int, end int) (int, int) {
+	lineStart := bytes.LastIndexByte(data[:start], '\n') + 1
+	if len(bytes.TrimSpace(data[lineStart:start])) > 0 {
+		return start, end
+	}
+	lineEnd := len(data)
+	if index := bytes.IndexByte(data[end:], '\n'); index >= 0 {
+		lineEnd = end + index + 1
+	}
+	if len(bytes.TrimSpace(data[end:lineEnd])) > 0 {
+		return start, end
+	}
+	return lineStart, lineEnd
+}
+
+// positionToOffset converts the 1-based line and column to a byte offset into the data.
+//
+// Columns follow the conventions of bufanalysis: a tab advances the column to the next
+// multiple of 8.
+func positionToOffset(data []byte, line int, column int) (int, error) {
+	var offset int
+	for currentLine := 1; currentLine < line; currentLine++ {
+		index := bytes.IndexByte(data[offset:], '\n')
+		if index < 0 {
+			return 0, fmt.Errorf("line %d is out of range", line)
+		}
+		offset += index + 1
+	}
+	for currentColumn := 1; currentColumn < column; {
+		if offset >= len(data) || data[offset] == '\n' {
+			return 0, fmt.Errorf("column %d of line %d is out of range", column, line)
+		}
+		r, size := utf8.DecodeRune(data[offset:])
+		if r == '\t' {
+			currentColumn += 8 - (currentColumn-1)%8
+		} else {
+			currentColumn++
+		}
+		offset += size
+	}
+	return offset, nil
+}
+
+// writeFixes applies the edits to the files and writes them in-place.
+func writeFixes(editedFiles []*editedFile) error {
+	for _, editedFile := range editedFiles {
+		data, err := os.ReadFile(editedFile.externalPath)
+		if err != nil {
+			return err
+		}
+		fixedData, err := applyEdits(editedFile, data)
+		if err != nil {
+			return err
+		}
+		fileInfo, err := os.Stat(editedFile.externalPath)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(editedFile.externalPath, fixedData, fileInfo.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFixesDiff writes the diff between the files and the files with the edits applied.
+func writeFixesDiff(ctx context.Context, writer io.Writer, editedFiles []*editedFile) error {
+	originalReadWriteBucket := storagemem.NewReadWriteBucket()
+	fixedReadWriteBucket := storagemem.NewReadWriteBucket()
+	for _, editedFile := range editedFiles {
+		data, err := os.ReadFile(editedFile.externalPath)
+		if err != nil {
+			return err
+		}
+		fixedData, err := applyEdits(editedFile, data)
+		if err != nil {
+			return err
+		}
+		if err := putFile(ctx, originalReadWriteBucket, editedFile, data); err != nil {
+			return err
+		}
+		if err := putFile(ctx, fixedReadWriteBucket, editedFile, fixedData); err != nil {
+			return err
+		}
+	}
+	_, err := storage.DiffWithFilenames(
+		ctx,
+		writer,
+		originalReadWriteBucket,
+		fixedReadWriteBucket,
+		storage.DiffWithExternalPaths(), // No need to set prefixes as the buckets are from the same location.
+	)
+	return err
+}
+
+func putFile(ctx context.Context, readWriteBucket storage.ReadWriteBucket, editedFile *editedFile, data []byte) (retErr error) {
+	writeObjectCloser, err := readWriteBucket.Put(ctx, editedFile.path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, writeObjectCloser.Close())
+	}()
+	if err := writeObjectCloser.SetExternalPath(editedFile.externalPath); err != nil {
+		return err
+	}
+	_, err = writeObjectCloser.Write(data)
+	return err
+}
@@ -0,0 +1,44 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEditsOverlap(t *testing.T) {
+	t.Parallel()
+	fileInfo := &testFileInfo{path: "a.proto"}
+	data := []byte("syntax = \"proto3\";\n\n  import \"b.proto\";\n")
+	// The deletion of the import is extended to its whole line, which overlaps the
+	// insertion in the indentation of that line.
+	_, err := applyEdits(
+		&editedFile{
+			path:         "a.proto",
+			externalPath: "a.proto",
+			edits: []bufanalysis.Edit{
+				bufanalysis.NewEdit(fileInfo, 3, 3, 3, 20, ""),
+				bufanalysis.NewEdit(fileInfo, 3, 2, 3, 2, "x"),
+			},
+		},
+		data,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not fix a.proto: edits overlap")
+}
@@ -21,8 +21,10 @@ import (
 
 	"buf.build/go/app/appcmd"
 	"buf.build/go/app/appext"
+	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buffetch"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
@@ -36,6 +38,10 @@ const (
 	pathsFlagName           = "path"
 	excludePathsFlagName    = "exclude-path"
 	disableSymlinksFlagName = "disable-symlinks"
+	fixFlagName             = "fix"
+	diffFlagName            = "diff"
+	baselineFlagName        = "baseline"
+	writeBaselineFlagName   = "write-baseline"
 )
 
 // NewCommand returns a new Command.
@@ -47,8 +53,35 @@ func NewCommand(
 	return &appcmd.Command{
 		Use:   name + " <input>",
 		Short: "Run linting on Protobuf files",
-		Long:  bufcli.GetInputLong(`the source, module, or Image to lint`),
-		Args:  appcmd.MaximumNArgs(1),
+		Long: bufcli.GetInputLong(`the source, module, or Image to lint`) + `
+
+Use --fix to rewrite the files in-place to fix the violations that have a mechanical fix,
+such as the casing of names or unused imports. The parts of the files that are rewritten
+are formatted as with buf format. Only violations that can be fixed safely are fixed:
+for example, a type is only renamed if all of its references can be updated. References
+in files outside of the input, such as in other modules of a workspace that are not
+linted, are not updated. The input must be a directory or a proto file.
+
+Use --diff along with --fix to display the diff of the fixes instead of rewriting the files.
+
+The violations that were not fixed are printed as usual.
+
+Use --write-baseline to record the current violations in a baseline file, for example when
+adopting new rules in a codebase with existing violations:
+
+    $ buf lint --write-baseline buf.lint-baseline.json
+
+Use --baseline to only report the violations that are not in the baseline file:
+
+    $ buf lint --baseline buf.lint-baseline.json
+
+Violations are matched by their rule, file and symbol, such as the fully-qualified name of a
+message or field, so that the baseline stays valid when unrelated parts of the files are edited.
+The baseline records how many violations match each entry, and the violations beyond that
+number are reported as new. The entries of the baseline that are already fixed are printed to stderr, so that they can be
+removed by writing the baseline again. Unlike ignore and ignore_only in buf.yaml, a baseline
+does not silence new violations in the same files.`,
+		Args: appcmd.MaximumNArgs(1),
 		Run: builder.NewRunFunc(
 			func(ctx context.Context, container appext.Container) error {
 				return run(ctx, container, flags)
@@ -64,6 +97,10 @@ type flags struct {
 	Paths           []string
 	ExcludePaths    []string
 	DisableSymlinks bool
+	Fix             bool
+	Diff            bool
+	Baseline        string
+	WriteBaseline   string
 	// special
 	InputHashtag string
 }
@@ -92,6 +129,30 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 		"",
 		`The buf.yaml file or data to use for configuration`,
 	)
+	flagSet.BoolVar(
+		&f.Fix,
+		fixFlagName,
+		false,
+		"Fix the violations that have a mechanical fix by rewriting files in-place",
+	)
+	flagSet.BoolVar(
+		&f.Diff,
+		diffFlagName,
+		false,
+		fmt.Sprintf("Display the diff of the fixes instead of rewriting files. Requires --%s. The remaining failures are printed to stderr", fixFlagName),
+	)
+	flagSet.StringVar(
+		&f.Baseline,
+		baselineFlagName,
+		"",
+		"The baseline file of violations to not report",
+	)
+	flagSet.StringVar(
+		&f.WriteBaseline,
+		writeBaselineFlagName,
+		"",
+		"Write the current violations to the given baseline file instead of reporting them",
+	)
 }
 
 func run(
@@ -108,10 +169,38 @@ func run(
 	if controllerErrorFormat == "config-ignore-yaml" {
 		controllerErrorFormat = "text"
 	}
+	if flags.Diff && !flags.Fix {
+		return appcmd.NewInvalidArgumentErrorf("--%s requires --%s", diffFlagName, fixFlagName)
+	}
+	if flags.WriteBaseline != "" {
+		if flags.Baseline != "" {
+			return appcmd.NewInvalidArgumentErrorf("--%s cannot be specified if --%s is specified", writeBaselineFlagName, baselineFlagName)
+		}
+		if flags.Fix {
+			return appcmd.NewInvalidArgumentErrorf("--%s cannot be specified if --%s is specified", writeBaselineFlagName, fixFlagName)
+		}
+	}
 	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
 	if err != nil {
 		return err
 	}
+	if flags.Fix {
+		// We write the fixes to the external paths of the files, so the input must be a
+		// directory or a proto file.
+		if _, err := buffetch.NewDirOrProtoFileRefParser(container.Logger()).GetDirOrProtoFileRef(ctx, input); err != nil {
+			if errors.Is(err, buffetch.ErrModuleFormatDetectedForDirOrProtoFileRef) {
+				return appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: must be a directory or proto file", input, fixFlagName)
+			}
+			return appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: %v", input, fixFlagName, err)
+		}
+	}
+	var baselineKeyToCount map[baselineKey]int
+	if flags.Baseline != "" {
+		baselineKeyToCount, err = readBaseline(flags.Baseline)
+		if err != nil {
+			return err
+		}
+	}
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
@@ -147,11 +236,17 @@ func run(
 		allCheckConfigs = append(allCheckConfigs, imageWithConfig.LintConfig())
 		allCheckConfigs = append(allCheckConfigs, imageWithConfig.BreakingConfig())
 	}
+	// SARIF output describes each rule that was violated, so we gather the
+	// descriptions of all configured rules if it was requested.
+	ruleIDToDescription := make(map[string]string)
 	for _, imageWithConfig := range imageWithConfigs {
 		lintOptions := []bufcheck.LintOption{
 			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
 			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
 		}
+		if flags.Fix {
+			lintOptions = append(lintOptions, bufcheck.LintWithFixes())
+		}
 		if err := checkClient.Lint(
 			ctx,
 			imageWithConfig.LintConfig(),
@@ -165,26 +260,95 @@ func run(
 				return err
 			}
 		}
+		if flags.ErrorFormat == bufanalysis.FormatSARIF.String() {
+			rules, err := checkClient.ConfiguredRules(
+				ctx,
+				check.RuleTypeLint,
+				imageWithConfig.LintConfig(),
+				bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+				bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+			)
+			if err != nil {
+				return err
+			}
+			for _, rule := range rules {
+				ruleIDToDescription[rule.ID()] = rule.Purpose()
+			}
+		}
+	}
+	if flags.WriteBaseline != "" {
+		return writeBaseline(flags.WriteBaseline, allFileAnnotations)
+	}
+	if baselineKeyToCount != nil {
+		lintedPathSet := make(map[string]struct{})
+		for _, imageWithConfig := range imageWithConfigs {
+			for _, imageFile := range imageWithConfig.Files() {
+				if !imageFile.IsImport() {
+					lintedPathSet[imageFile.Path()] = struct{}{}
+				}
+			}
+		}
+		var fixedBaselineEntries []fixedBaselineEntry
+		allFileAnnotations, fixedBaselineEntries = applyBaseline(allFileAnnotations, baselineKeyToCount, lintedPathSet)
+		if len(fixedBaselineEntries) > 0 {
+			if err := printFixedBaselineEntries(container.Stderr(), flags.Baseline, fixedBaselineEntries); err != nil {
+				return err
+			}
+		}
 	}
-	if len(allFileAnnotations) > 0 {
+	if flags.Fix && len(allFileAnnotations) > 0 {
+		// We fix in the order of the FileAnnotationSet so that the fixes are deterministic.
+		editedFiles, unfixedFileAnnotations := fixFileAnnotations(
+			bufanalysis.NewFileAnnotationSet(allFileAnnotations...).FileAnnotations(),
+		)
+		if flags.Diff {
+			if err := writeFixesDiff(ctx, container.Stdout(), editedFiles); err != nil {
+				return err
+			}
+			// Nothing was fixed yet, so we still report an error if there are any errors.
+			if bufanalysis.HasErrors(allFileAnnotations...) {
+				defer func() {
+					if retErr == nil {
+						retErr = bufctl.ErrFileAnnotation
+					}
+				}()
+			}
+		} else if err := writeFixes(editedFiles); err != nil {
+			return err
+		}
+		allFileAnnotations = unfixedFileAnnotations
+	}
+	// With --diff, stdout is reserved for the diff, so that neither the diff nor the
+	// FileAnnotations in a machine-readable format are corrupted by the other.
+	fileAnnotationWriter := container.Stdout()
+	if flags.Diff {
+		fileAnnotationWriter = container.Stderr()
+	}
+	// A SARIF log is printed even if there are no annotations, since consumers such as
+	// code scanning uploads expect one.
+	if len(allFileAnnotations) > 0 || flags.ErrorFormat == bufanalysis.FormatSARIF.String() {
 		allFileAnnotationSet := bufanalysis.NewFileAnnotationSet(allFileAnnotations...)
 		if flags.ErrorFormat == "config-ignore-yaml" {
 			if err := bufcli.PrintFileAnnotationSetLintConfigIgnoreYAMLV1(
-				container.Stdout(),
+				fileAnnotationWriter,
 				allFileAnnotationSet,
 			); err != nil {
 				return err
 			}
 		} else {
 			if err := bufanalysis.PrintFileAnnotationSet(
-				container.Stdout(),
+				fileAnnotationWriter,
 				allFileAnnotationSet,
 				flags.ErrorFormat,
+				bufanalysis.PrintWithRuleDescriptions(ruleIDToDescription),
 			); err != nil {
 				return err
 			}
 		}
-		return bufctl.ErrFileAnnotation
+		// Annotations with a severity other than error are printed, but do not fail.
+		if bufanalysis.HasErrors(allFileAnnotations...) {
+			return bufctl.ErrFileAnnotation
+		}
 	}
 	return nil
 }
@@ -157,6 +157,11 @@ func handle(
 			); err != nil {
 				return err
 			}
+			if !bufanalysis.HasErrors(fileAnnotationSet.FileAnnotations()...) {
+				// Only errors fail the plugin, anything else is just printed.
+				_, err := container.Stderr().Write(buffer.Bytes())
+				return err
+			}
 			responseWriter.AddError(strings.TrimSpace(buffer.String()))
 			return nil
 		}
@@ -34,6 +34,18 @@ const (
 	//
 	// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message.
 	FormatGithubActions
+	// FormatSARIF is the SARIF 2.1.0 format for FileAnnotations.
+	//
+	// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+	FormatSARIF
+	// FormatGitLabCodeQuality is the GitLab Code Quality format for FileAnnotations.
+	//
+	// See https://docs.gitlab.com/ee/ci/testing/code_quality.html#code-quality-report-format.
+	FormatGitLabCodeQuality
+	// FormatCheckstyle is the Checkstyle XML format for FileAnnotations.
+	//
+	// See https://checkstyle.org/.
+	FormatCheckstyle
 )
 
 var (
@@ -46,6 +58,9 @@ var (
 		"msvs",
 		"junit",
 		"github-actions",
+		"sarif",
+		"gitlab-code-quality",
+		"checkstyle",
 	}
 	// AllFormatStringsWithAliases is all format strings with aliases.
 	//
@@ -57,26 +72,91 @@ var (
 		"msvs",
 		"junit",
 		"github-actions",
+		"sarif",
+		"gitlab-code-quality",
+		"checkstyle",
 	}
 
 	stringToFormat = map[string]Format{
 		"text": FormatText,
 		// alias for text
-		"gcc":            FormatText,
-		"json":           FormatJSON,
-		"msvs":           FormatMSVS,
-		"junit":          FormatJUnit,
-		"github-actions": FormatGithubActions,
+		"gcc":                 FormatText,
+		"json":                FormatJSON,
+		"msvs":                FormatMSVS,
+		"junit":               FormatJUnit,
+		"github-actions":      FormatGithubActions,
+		"sarif":               FormatSARIF,
+		"gitlab-code-quality": FormatGitLabCodeQuality,
+		"checkstyle":          FormatCheckstyle,
 	}
 	formatToString = map[Format]string{
-		FormatText:          "text",
-		FormatJSON:          "json",
-		FormatMSVS:          "msvs",
-		FormatJUnit:         "junit",
-		FormatGithubActions: "github-actions",
+		FormatText:              "text",
+		FormatJSON:              "json",
+		FormatMSVS:              "msvs",
+		FormatJUnit:             "junit",
+		FormatGithubActions:     "github-actions",
+		FormatSARIF:             "sarif",
+		FormatGitLabCodeQuality: "gitlab-code-quality",
+		FormatCheckstyle:        "checkstyle",
 	}
 )
 
+const (
+	// SeverityError is the error severity.
+	//
+	// This is the default severity, and the only severity that results in a failure.
+	SeverityError Severity = iota + 1
+	// SeverityWarning is the warning severity.
+	SeverityWarning
+	// SeverityInfo is the info severity.
+	SeverityInfo
+)
+
+var (
+	// AllSeverityStrings is all severity strings.
+	//
+	// Sorted from most to least severe.
+	AllSeverityStrings = []string{
+		"error",
+		"warning",
+		"info",
+	}
+
+	stringToSeverity = map[string]Severity{
+		"error":   SeverityError,
+		"warning": SeverityWarning,
+		"info":    SeverityInfo,
+	}
+	severityToString = map[Severity]string{
+		SeverityError:   "error",
+		SeverityWarning: "warning",
+		SeverityInfo:    "info",
+	}
+)
+
+// Severity is the severity of a FileAnnotation.
+//
+// A lower value is more severe.
+type Severity int
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	str, ok := severityToString[s]
+	if !ok {
+		return strconv.Itoa(int(s))
+	}
+	return str
+}
+
+// ParseSeverity parses the Severity.
+func ParseSeverity(s string) (Severity, error) {
+	severity, ok := stringToSeverity[strings.ToLower(strings.TrimSpace(s))]
+	if ok {
+		return severity, nil
+	}
+	return 0, fmt.Errorf("unknown severity: %q, must be one of %s", s, strings.Join(AllSeverityStrings, ", "))
+}
+
 // Format is a FileAnnotation format.
 type Format int
 
@@ -149,6 +229,25 @@ type FileAnnotation interface {
 	// May be empty if this annotation did not originate from a plugin.
 	// This may be added to the printed message field for certain printers.
 	PluginName() string
+	// Edits are the edits that mechanically fix the annotation.
+	//
+	// All of the edits must be applied together. The edits may be to other files than
+	// the file of the annotation, for example to update the references to a renamed type.
+	//
+	// Empty if the annotation has no mechanical fix.
+	Edits() []Edit
+	// Symbol is a stable identity of the element that the annotation is about, such as
+	// the fully-qualified name of a descriptor.
+	//
+	// Unlike the lines and columns, the symbol does not change when unrelated parts of the
+	// file are edited, so it can be used to match annotations across versions of a file.
+	// May be empty.
+	Symbol() string
+	// Severity is the severity of the annotation.
+	//
+	// This is SeverityError unless another severity was explicitly set. Only annotations
+	// with SeverityError should result in a failure.
+	Severity() Severity
 
 	isFileAnnotation()
 }
@@ -163,7 +262,12 @@ func NewFileAnnotation(
 	typeString string,
 	message string,
 	pluginName string,
+	options ...FileAnnotationOption,
 ) FileAnnotation {
+	fileAnnotationOptions := newFileAnnotationOptions()
+	for _, option := range options {
+		option(fileAnnotationOptions)
+	}
 	return newFileAnnotation(
 		fileInfo,
 		startLine,
@@ -173,6 +277,84 @@ func NewFileAnnotation(
 		typeString,
 		message,
 		pluginName,
+		fileAnnotationOptions.edits,
+		fileAnnotationOptions.symbol,
+		fileAnnotationOptions.severity,
+	)
+}
+
+// FileAnnotationOption is an option for NewFileAnnotation.
+type FileAnnotationOption func(*fileAnnotationOptions)
+
+// FileAnnotationWithEdits returns a new FileAnnotationOption that sets the edits that
+// mechanically fix the FileAnnotation.
+func FileAnnotationWithEdits(edits ...Edit) FileAnnotationOption {
+	return func(fileAnnotationOptions *fileAnnotationOptions) {
+		fileAnnotationOptions.edits = append(fileAnnotationOptions.edits, edits...)
+	}
+}
+
+// FileAnnotationWithSymbol returns a new FileAnnotationOption that sets the symbol of the
+// FileAnnotation.
+func FileAnnotationWithSymbol(symbol string) FileAnnotationOption {
+	return func(fileAnnotationOptions *fileAnnotationOptions) {
+		fileAnnotationOptions.symbol = symbol
+	}
+}
+
+// FileAnnotationWithSeverity returns a new FileAnnotationOption that sets the severity of the
+// FileAnnotation.
+//
+// The default is SeverityError.
+func FileAnnotationWithSeverity(severity Severity) FileAnnotationOption {
+	return func(fileAnnotationOptions *fileAnnotationOptions) {
+		fileAnnotationOptions.severity = severity
+	}
+}
+
+// Edit is an edit to a file.
+//
+// Lines and columns are 1-based, and follow the same conventions as the lines and
+// columns of a FileAnnotation: a tab advances the column to the next multiple of 8.
+// The range of the edit is from the start position up to, but not including, the
+// end position.
+type Edit interface {
+	// FileInfo is the FileInfo for the file to edit.
+	FileInfo() FileInfo
+	// StartLine is the starting line.
+	StartLine() int
+	// StartColumn is the starting column.
+	StartColumn() int
+	// EndLine is the ending line.
+	EndLine() int
+	// EndColumn is the ending column.
+	//
+	// If the end position is the same as the start position, the edit inserts NewText.
+	EndColumn() int
+	// NewText is the text to replace the range with.
+	//
+	// If empty, the edit deletes the range.
+	NewText() string
+
+	isEdit()
+}
+
+// NewEdit returns a new Edit.
+func NewEdit(
+	fileInfo FileInfo,
+	startLine int,
+	startColumn int,
+	endLine int,
+	endColumn int,
+	newText string,
+) Edit {
+	return newEdit(
+		fileInfo,
+		startLine,
+		startColumn,
+		endLine,
+		endColumn,
+		newText,
 	)
 }
 
@@ -200,12 +382,31 @@ func NewFileAnnotationSet(fileAnnotations ...FileAnnotation) FileAnnotationSet {
 	return newFileAnnotationSet(fileAnnotations)
 }
 
+// HasErrors returns true if any of the FileAnnotations has SeverityError.
+func HasErrors(fileAnnotations ...FileAnnotation) bool {
+	for _, fileAnnotation := range fileAnnotations {
+		if fileAnnotation.Severity() == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
 // PrintFileAnnotationSet prints the file annotations separated by newlines.
-func PrintFileAnnotationSet(writer io.Writer, fileAnnotationSet FileAnnotationSet, formatString string) error {
+func PrintFileAnnotationSet(
+	writer io.Writer,
+	fileAnnotationSet FileAnnotationSet,
+	formatString string,
+	options ...PrintFileAnnotationSetOption,
+) error {
 	format, err := ParseFormat(formatString)
 	if err != nil {
 		return err
 	}
+	printFileAnnotationSetOptions := newPrintFileAnnotationSetOptions()
+	for _, option := range options {
+		option(printFileAnnotationSetOptions)
+	}
 
 	switch format {
 	case FormatText:
@@ -218,7 +419,50 @@ func PrintFileAnnotationSet(writer io.Writer, fileAnnotationSet FileAnnotationSe
 		return printAsJUnit(writer, fileAnnotationSet.FileAnnotations())
 	case FormatGithubActions:
 		return printAsGithubActions(writer, fileAnnotationSet.FileAnnotations())
+	case FormatSARIF:
+		return printAsSARIF(writer, fileAnnotationSet.FileAnnotations(), printFileAnnotationSetOptions.ruleIDToDescription)
+	case FormatGitLabCodeQuality:
+		return printAsGitLabCodeQuality(writer, fileAnnotationSet.FileAnnotations())
+	case FormatCheckstyle:
+		return printAsCheckstyle(writer, fileAnnotationSet.FileAnnotations())
 	default:
 		return fmt.Errorf("unknown FileAnnotation Format: %v", format)
 	}
 }
+
+// PrintFileAnnotationSetOption is an option for PrintFileAnnotationSet.
+type PrintFileAnnotationSetOption func(*printFileAnnotationSetOptions)
+
+// PrintWithRuleDescriptions returns a new PrintFileAnnotationSetOption that describes
+// the rules that FileAnnotation types refer to, for formats that include rule metadata,
+// such as SARIF.
+//
+// The map is from FileAnnotation type, i.e. rule ID, to a description of the rule.
+// Types without a description are still printed.
+func PrintWithRuleDescriptions(ruleIDToDescription map[string]string) PrintFileAnnotationSetOption {
+	return func(printFileAnnotationSetOptions *printFileAnnotationSetOptions) {
+		printFileAnnotationSetOptions.ruleIDToDescription = ruleIDToDescription
+	}
+}
+
+// *** PRIVATE ***
+
+type fileAnnotationOptions struct {
+	edits    []Edit
+	symbol   string
+	severity Severity
+}
+
+func newFileAnnotationOptions() *fileAnnotationOptions {
+	return &fileAnnotationOptions{
+		severity: SeverityError,
+	}
+}
+
+type printFileAnnotationSetOptions struct {
+	ruleIDToDescription map[string]string
+}
+
+func newPrintFileAnnotationSetOptions() *printFileAnnotationSetOptions {
+	return &printFileAnnotationSetOptions{}
+}
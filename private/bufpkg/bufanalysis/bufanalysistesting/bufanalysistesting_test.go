@@ -136,4 +136,280 @@ path/to/file.proto(2,1) : error FOO : Hello. (buf-plugin-foo)
 `,
 		sb.String(),
 	)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(
+		sb,
+		bufanalysis.NewFileAnnotationSet(
+			append(
+				fileAnnotations,
+				newFileAnnotation(
+					t,
+					"",
+					0,
+					0,
+					0,
+					0,
+					"BAR",
+					"Goodbye.",
+					"",
+				),
+			)...,
+		),
+		"sarif",
+		bufanalysis.PrintWithRuleDescriptions(map[string]string{"FOO": "Checks for foo."}),
+	)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "buf",
+          "informationUri": "https://buf.build",
+          "rules": [
+            {"id": "BAR"},
+            {"id": "FOO", "shortDescription": {"text": "Checks for foo."}}
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "BAR",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {"text": "Goodbye."}
+        },
+        {
+          "ruleId": "FOO",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {"text": "Hello."},
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {"uri": "path/to/file.proto"},
+                "region": {"startLine": 1, "endLine": 1}
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "FOO",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {"text": "Hello. (buf-plugin-foo)"},
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {"uri": "path/to/file.proto"},
+                "region": {"startLine": 2, "startColumn": 1, "endLine": 2, "endColumn": 1}
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`,
+		sb.String(),
+	)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(
+		sb,
+		bufanalysis.NewFileAnnotationSet(
+			append(
+				fileAnnotations,
+				newFileAnnotation(
+					t,
+					"",
+					0,
+					0,
+					0,
+					0,
+					"BAR",
+					"Goodbye.",
+					"",
+				),
+			)...,
+		),
+		"gitlab-code-quality",
+	)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`[
+  {
+    "description": "Goodbye.",
+    "check_name": "BAR",
+    "fingerprint": "6b90c3a5d660d12a0e4f44bccd1df18f6eb99cc67ffeddcd86bfb5dcb8facefe",
+    "severity": "major",
+    "location": {"path": "<input>", "lines": {"begin": 1, "end": 1}}
+  },
+  {
+    "description": "Hello.",
+    "check_name": "FOO",
+    "fingerprint": "b6c47874862d218a2a3cd82e61fbeebb5a9e29e7c39680aa9581135a64238b61",
+    "severity": "major",
+    "location": {"path": "path/to/file.proto", "lines": {"begin": 1, "end": 1}}
+  },
+  {
+    "description": "Hello. (buf-plugin-foo)",
+    "check_name": "FOO",
+    "fingerprint": "27bacf86505a20bb2dbfef75b0e91ce24674a14dcb460a56b892f14e17c14940",
+    "severity": "major",
+    "location": {"path": "path/to/file.proto", "lines": {"begin": 2, "end": 2}}
+  }
+]`,
+		sb.String(),
+	)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(sb, bufanalysis.NewFileAnnotationSet(fileAnnotations...), "checkstyle")
+	require.NoError(t, err)
+	assert.Equal(t,
+		`<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="path/to/file.proto">
+    <error line="1" severity="error" message="Hello." source="FOO"></error>
+    <error line="2" column="1" severity="error" message="Hello. (buf-plugin-foo)" source="FOO"></error>
+  </file>
+</checkstyle>
+`,
+		sb.String(),
+	)
+}
+
+func TestSARIFArtifactURI(t *testing.T) {
+	t.Parallel()
+	fileAnnotationSet := bufanalysis.NewFileAnnotationSet(
+		newFileAnnotation(t, "path/to/my file.proto", 1, 1, 1, 1, "FOO", "Hello.", ""),
+		newFileAnnotation(t, "/abs/path/to/file#1.proto", 1, 1, 1, 1, "FOO", "Hello.", ""),
+		newFileAnnotation(t, "a:b.proto", 1, 1, 1, 1, "FOO", "Hello.", ""),
+	)
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSet(sb, fileAnnotationSet, "sarif")
+	require.NoError(t, err)
+	assert.Contains(t, sb.String(), `"artifactLocation":{"uri":"file:///abs/path/to/file%231.proto"}`)
+	assert.Contains(t, sb.String(), `"artifactLocation":{"uri":"./a:b.proto"}`)
+	assert.Contains(t, sb.String(), `"artifactLocation":{"uri":"path/to/my%20file.proto"}`)
+}
+
+func TestSARIFEmpty(t *testing.T) {
+	t.Parallel()
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSet(sb, bufanalysis.NewFileAnnotationSet(), "sarif")
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "buf", "informationUri": "https://buf.build"}},
+      "results": []
+    }
+  ]
+}`,
+		sb.String(),
+	)
+}
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+	fileAnnotationSet := bufanalysis.NewFileAnnotationSet(
+		bufanalysis.NewFileAnnotation(
+			newFileInfo("path/to/file.proto"),
+			1,
+			1,
+			1,
+			5,
+			"FOO",
+			"Hello.",
+			"",
+		),
+		bufanalysis.NewFileAnnotation(
+			newFileInfo("path/to/file.proto"),
+			2,
+			1,
+			2,
+			5,
+			"BAR",
+			"Goodbye.",
+			"",
+			bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning),
+		),
+		bufanalysis.NewFileAnnotation(
+			newFileInfo("path/to/file.proto"),
+			3,
+			1,
+			3,
+			5,
+			"BAZ",
+			"Hi.",
+			"",
+			bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityInfo),
+		),
+	)
+	assert.True(t, bufanalysis.HasErrors(fileAnnotationSet.FileAnnotations()...))
+	assert.False(t, bufanalysis.HasErrors(fileAnnotationSet.FileAnnotations()[1:]...))
+	formatToExpected := map[string]string{
+		"text": `path/to/file.proto:1:1:Hello.
+path/to/file.proto:2:1:warning: Goodbye.
+path/to/file.proto:3:1:info: Hi.
+`,
+		"json": `{"path":"path/to/file.proto","start_line":1,"start_column":1,"end_line":1,"end_column":5,"type":"FOO","message":"Hello."}
+{"path":"path/to/file.proto","start_line":2,"start_column":1,"end_line":2,"end_column":5,"type":"BAR","message":"Goodbye.","severity":"warning"}
+{"path":"path/to/file.proto","start_line":3,"start_column":1,"end_line":3,"end_column":5,"type":"BAZ","message":"Hi.","severity":"info"}
+`,
+		"msvs": `path/to/file.proto(1,1) : error FOO : Hello.
+path/to/file.proto(2,1) : warning BAR : Goodbye.
+path/to/file.proto(3,1) : warning BAZ : Hi.
+`,
+		"junit": `<testsuites>
+  <testsuite name="path/to/file" tests="3" failures="1" errors="0">
+    <testcase name="FOO_1_1">
+      <failure message="path/to/file.proto:1:1:Hello." type="FOO"></failure>
+    </testcase>
+    <testcase name="BAR_2_1">
+      <system-out>path/to/file.proto:2:1:warning: Goodbye.</system-out>
+    </testcase>
+    <testcase name="BAZ_3_1">
+      <system-out>path/to/file.proto:3:1:info: Hi.</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		"github-actions": `::error file=path/to/file.proto,line=1,col=1,endLine=1,endColumn=5::Hello.
+::warning file=path/to/file.proto,line=2,col=1,endLine=2,endColumn=5::Goodbye.
+::notice file=path/to/file.proto,line=3,col=1,endLine=3,endColumn=5::Hi.
+`,
+		"checkstyle": `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="path/to/file.proto">
+    <error line="1" column="1" severity="error" message="Hello." source="FOO"></error>
+    <error line="2" column="1" severity="warning" message="Goodbye." source="BAR"></error>
+    <error line="3" column="1" severity="info" message="Hi." source="BAZ"></error>
+  </file>
+</checkstyle>
+`,
+	}
+	for format, expected := range formatToExpected {
+		sb := &strings.Builder{}
+		err := bufanalysis.PrintFileAnnotationSet(sb, fileAnnotationSet, format)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sb.String(), format)
+	}
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSet(sb, fileAnnotationSet, "sarif")
+	require.NoError(t, err)
+	assert.Contains(t, sb.String(), `"ruleId":"FOO","ruleIndex":0,"level":"error"`)
+	assert.Contains(t, sb.String(), `"ruleId":"BAR","ruleIndex":1,"level":"warning"`)
+	assert.Contains(t, sb.String(), `"ruleId":"BAZ","ruleIndex":2,"level":"note"`)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(sb, fileAnnotationSet, "gitlab-code-quality")
+	require.NoError(t, err)
+	assert.Contains(t, sb.String(), `"check_name":"FOO","fingerprint":"b6c47874862d218a2a3cd82e61fbeebb5a9e29e7c39680aa9581135a64238b61","severity":"major"`)
+	assert.Contains(t, sb.String(), `"check_name":"BAR","fingerprint":"9b3d06f5af4290f32158c81eee2ac418eb0032fdfa304d4bc6d215bbb9f7593c","severity":"minor"`)
+	assert.Contains(t, sb.String(), `"check_name":"BAZ","fingerprint":"8d2dffcf0748ba63467af0dd3df612041bdc5be0c199640bf4142dc6f91f0232","severity":"info"`)
 }
@@ -0,0 +1,68 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufanalysis
+
+type edit struct {
+	fileInfo    FileInfo
+	startLine   int
+	startColumn int
+	endLine     int
+	endColumn   int
+	newText     string
+}
+
+func newEdit(
+	fileInfo FileInfo,
+	startLine int,
+	startColumn int,
+	endLine int,
+	endColumn int,
+	newText string,
+) *edit {
+	return &edit{
+		fileInfo:    fileInfo,
+		startLine:   startLine,
+		startColumn: startColumn,
+		endLine:     endLine,
+		endColumn:   endColumn,
+		newText:     newText,
+	}
+}
+
+func (e *edit) FileInfo() FileInfo {
+	return e.fileInfo
+}
+
+func (e *edit) StartLine() int {
+	return e.startLine
+}
+
+func (e *edit) StartColumn() int {
+	return e.startColumn
+}
+
+func (e *edit) EndLine() int {
+	return e.endLine
+}
+
+func (e *edit) EndColumn() int {
+	return e.endColumn
+}
+
+func (e *edit) NewText() string {
+	return e.newText
+}
+
+func (*edit) isEdit() {}
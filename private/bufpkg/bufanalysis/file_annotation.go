@@ -28,6 +28,9 @@ type fileAnnotation struct {
 	typeString  string
 	message     string
 	pluginName  string
+	edits       []Edit
+	symbol      string
+	severity    Severity
 }
 
 func newFileAnnotation(
@@ -39,6 +42,9 @@ func newFileAnnotation(
 	typeString string,
 	message string,
 	pluginName string,
+	edits []Edit,
+	symbol string,
+	severity Severity,
 ) *fileAnnotation {
 	return &fileAnnotation{
 		fileInfo:    fileInfo,
@@ -49,6 +55,9 @@ func newFileAnnotation(
 		typeString:  typeString,
 		message:     message,
 		pluginName:  pluginName,
+		edits:       edits,
+		symbol:      symbol,
+		severity:    severity,
 	}
 }
 
@@ -84,6 +93,18 @@ func (f *fileAnnotation) PluginName() string {
 	return f.pluginName
 }
 
+func (f *fileAnnotation) Edits() []Edit {
+	return f.edits
+}
+
+func (f *fileAnnotation) Symbol() string {
+	return f.symbol
+}
+
+func (f *fileAnnotation) Severity() Severity {
+	return f.severity
+}
+
 func (f *fileAnnotation) String() string {
 	if f == nil {
 		return ""
@@ -109,6 +130,11 @@ func (f *fileAnnotation) String() string {
 	_, _ = buffer.WriteRune(':')
 	_, _ = buffer.WriteString(strconv.Itoa(column))
 	_, _ = buffer.WriteRune(':')
+	if f.severity != SeverityError {
+		// Errors are printed without a prefix, as they always have been.
+		_, _ = buffer.WriteString(f.severity.String())
+		_, _ = buffer.WriteString(": ")
+	}
 	_, _ = buffer.WriteString(message)
 	if f.pluginName != "" {
 		_, _ = buffer.WriteString(" (")
@@ -35,6 +35,10 @@ func newFileAnnotationSet(fileAnnotations []FileAnnotation) *fileAnnotationSet {
 }
 
 func (f *fileAnnotationSet) FileAnnotations() []FileAnnotation {
+	// newFileAnnotationSet returns nil if there are no FileAnnotations.
+	if f == nil {
+		return nil
+	}
 	return f.fileAnnotations
 }
 
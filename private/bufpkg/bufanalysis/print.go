@@ -16,10 +16,14 @@ package bufanalysis
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -71,12 +75,18 @@ func printAsJUnit(writer io.Writer, fileAnnotations []FileAnnotation) error {
 			path = fileInfo.ExternalPath()
 		}
 		path = strings.TrimSuffix(path, ".proto")
+		var failures int
+		for _, annotation := range annotations {
+			if annotation.Severity() == SeverityError {
+				failures++
+			}
+		}
 		testsuite := xml.StartElement{
 			Name: xml.Name{Local: "testsuite"},
 			Attr: []xml.Attr{
 				{Name: xml.Name{Local: "name"}, Value: path},
 				{Name: xml.Name{Local: "tests"}, Value: strconv.Itoa(len(annotations))},
-				{Name: xml.Name{Local: "failures"}, Value: strconv.Itoa(len(annotations))},
+				{Name: xml.Name{Local: "failures"}, Value: strconv.Itoa(failures)},
 				{Name: xml.Name{Local: "errors"}, Value: "0"},
 			},
 		}
@@ -116,6 +126,14 @@ func printFileAnnotationAsJUnit(encoder *xml.Encoder, annotation FileAnnotation)
 	if err := encoder.EncodeToken(testcase); err != nil {
 		return err
 	}
+	if annotation.Severity() != SeverityError {
+		// JUnit has no concept of a warning, so annotations that are not errors are
+		// passing test cases with the annotation as their output.
+		if err := encoder.EncodeElement(annotation.String(), xml.StartElement{Name: xml.Name{Local: "system-out"}}); err != nil {
+			return err
+		}
+		return encoder.EncodeToken(xml.EndElement{Name: testcase.Name})
+	}
 	failure := xml.StartElement{
 		Name: xml.Name{Local: "failure"},
 		Attr: []xml.Attr{
@@ -190,7 +208,13 @@ func printFileAnnotationAsMSVS(buffer *bytes.Buffer, f FileAnnotation) error {
 		_, _ = buffer.WriteRune(',')
 		_, _ = buffer.WriteString(strconv.Itoa(column))
 	}
-	_, _ = buffer.WriteString(") : error ")
+	_, _ = buffer.WriteString(") : ")
+	// MSVS only has the error and warning categories.
+	if f.Severity() == SeverityError {
+		_, _ = buffer.WriteString("error ")
+	} else {
+		_, _ = buffer.WriteString("warning ")
+	}
 	_, _ = buffer.WriteString(typeString)
 	_, _ = buffer.WriteString(" : ")
 	_, _ = buffer.WriteString(message)
@@ -215,7 +239,14 @@ func printFileAnnotationAsGithubActions(buffer *bytes.Buffer, f FileAnnotation)
 	if f == nil {
 		return nil
 	}
-	_, _ = buffer.WriteString("::error ")
+	switch f.Severity() {
+	case SeverityWarning:
+		_, _ = buffer.WriteString("::warning ")
+	case SeverityInfo:
+		_, _ = buffer.WriteString("::notice ")
+	default:
+		_, _ = buffer.WriteString("::error ")
+	}
 
 	// file= is required for GitHub Actions, however it is possible to not have
 	// a path for a FileAnnotation. We still print something, however we need
@@ -259,6 +290,260 @@ func printFileAnnotationAsGithubActions(buffer *bytes.Buffer, f FileAnnotation)
 	return nil
 }
 
+// sarifSchemaURI is the JSON schema of SARIF 2.1.0 logs.
+const sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+
+func printAsSARIF(writer io.Writer, fileAnnotations []FileAnnotation, ruleIDToDescription map[string]string) error {
+	ruleIDToIndex := make(map[string]int)
+	var rules []sarifRule
+	results := make([]sarifResult, 0, len(fileAnnotations))
+	for _, fileAnnotation := range fileAnnotations {
+		ruleID := fileAnnotation.Type()
+		if ruleID == "" {
+			// should never happen but just in case
+			ruleID = "FAILURE"
+		}
+		ruleIndex, ok := ruleIDToIndex[ruleID]
+		if !ok {
+			ruleIndex = len(rules)
+			ruleIDToIndex[ruleID] = ruleIndex
+			rule := sarifRule{ID: ruleID}
+			if description := ruleIDToDescription[ruleID]; description != "" {
+				rule.ShortDescription = &sarifMessage{Text: description}
+			}
+			rules = append(rules, rule)
+		}
+		message := fileAnnotation.Message()
+		if pluginName := fileAnnotation.PluginName(); pluginName != "" {
+			message += " (" + pluginName + ")"
+		}
+		result := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex,
+			Level:     sarifLevel(fileAnnotation.Severity()),
+			Message:   sarifMessage{Text: message},
+		}
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			physicalLocation := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: sarifArtifactURI(fileInfo.ExternalPath())},
+			}
+			// Regions require a start line, and we only print the other positions if we have it.
+			if startLine := fileAnnotation.StartLine(); startLine > 0 {
+				physicalLocation.Region = &sarifRegion{
+					StartLine:   startLine,
+					StartColumn: fileAnnotation.StartColumn(),
+					EndLine:     fileAnnotation.EndLine(),
+					EndColumn:   fileAnnotation.EndColumn(),
+				}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: physicalLocation}}
+		}
+		results = append(results, result)
+	}
+	data, err := json.Marshal(sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "buf",
+						InformationURI: "https://buf.build",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sarifArtifactURI returns the URI of the file at the external path.
+//
+// Relative paths, which are resolved against the root of the repository by consumers
+// such as code scanning, are written as relative references. Absolute paths are written
+// as file URIs.
+func sarifArtifactURI(externalPath string) string {
+	path := filepath.ToSlash(externalPath)
+	if filepath.IsAbs(externalPath) {
+		if !strings.HasPrefix(path, "/") {
+			// Windows paths such as C:/foo.proto are written as file:///C:/foo.proto.
+			path = "/" + path
+		}
+		return (&url.URL{Scheme: "file", Path: path}).String()
+	}
+	return (&url.URL{Path: path}).String()
+}
+
+// sarifLevel returns the SARIF result level for the Severity.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+func printAsGitLabCodeQuality(writer io.Writer, fileAnnotations []FileAnnotation) error {
+	issues := make([]gitLabCodeQualityIssue, 0, len(fileAnnotations))
+	keyToOccurrences := make(map[[3]string]int)
+	for _, fileAnnotation := range fileAnnotations {
+		path := "<input>"
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			path = fileInfo.ExternalPath()
+		}
+		description := fileAnnotation.Message()
+		if pluginName := fileAnnotation.PluginName(); pluginName != "" {
+			description += " (" + pluginName + ")"
+		}
+		key := [3]string{path, fileAnnotation.Type(), fileAnnotation.Message()}
+		occurrence := keyToOccurrences[key]
+		keyToOccurrences[key]++
+		// GitLab requires a beginning line, so we point at the top of the file
+		// if we do not know where the annotation is.
+		beginLine := max(fileAnnotation.StartLine(), 1)
+		endLine := max(fileAnnotation.EndLine(), beginLine)
+		issues = append(issues, gitLabCodeQualityIssue{
+			Description: description,
+			CheckName:   fileAnnotation.Type(),
+			Fingerprint: fileAnnotationFingerprint(path, fileAnnotation, occurrence),
+			Severity:    gitLabCodeQualitySeverity(fileAnnotation.Severity()),
+			Location: gitLabCodeQualityLocation{
+				Path: path,
+				Lines: gitLabCodeQualityLines{
+					Begin: beginLine,
+					End:   endLine,
+				},
+			},
+		})
+	}
+	data, err := json.Marshal(issues)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// gitLabCodeQualitySeverity returns the GitLab Code Quality severity for the Severity.
+func gitLabCodeQualitySeverity(severity Severity) string {
+	switch severity {
+	case SeverityWarning:
+		return "minor"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "major"
+	}
+}
+
+// fileAnnotationFingerprint returns a fingerprint that identifies a FileAnnotation
+// across runs.
+//
+// Positions are deliberately not part of the fingerprint, so that an annotation keeps
+// the same fingerprint when unrelated changes move it around within its file. Instead,
+// occurrence disambiguates annotations that are otherwise identical, as GitLab
+// collapses issues with the same fingerprint.
+func fileAnnotationFingerprint(path string, fileAnnotation FileAnnotation, occurrence int) string {
+	hash := sha256.New()
+	parts := []string{path, fileAnnotation.Type(), fileAnnotation.Message()}
+	if occurrence > 0 {
+		parts = append(parts, strconv.Itoa(occurrence))
+	}
+	for _, part := range parts {
+		_, _ = hash.Write([]byte(part))
+		// Separate the parts so that their boundaries are part of the fingerprint.
+		_, _ = hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func printAsCheckstyle(writer io.Writer, fileAnnotations []FileAnnotation) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	checkstyle := xml.StartElement{
+		Name: xml.Name{Local: "checkstyle"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "version"}, Value: "4.3"},
+		},
+	}
+	if err := encoder.EncodeToken(checkstyle); err != nil {
+		return err
+	}
+	for _, annotations := range groupAnnotationsByPath(fileAnnotations) {
+		path := "<input>"
+		if fileInfo := annotations[0].FileInfo(); fileInfo != nil {
+			path = fileInfo.ExternalPath()
+		}
+		file := xml.StartElement{
+			Name: xml.Name{Local: "file"},
+			Attr: []xml.Attr{
+				{Name: xml.Name{Local: "name"}, Value: path},
+			},
+		}
+		if err := encoder.EncodeToken(file); err != nil {
+			return err
+		}
+		for _, annotation := range annotations {
+			if err := printFileAnnotationAsCheckstyle(encoder, annotation); err != nil {
+				return err
+			}
+		}
+		if err := encoder.EncodeToken(xml.EndElement{Name: file.Name}); err != nil {
+			return err
+		}
+	}
+	if err := encoder.EncodeToken(xml.EndElement{Name: checkstyle.Name}); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	if _, err := writer.Write([]byte("\n")); err != nil {
+		return err
+	}
+	return nil
+}
+
+func printFileAnnotationAsCheckstyle(encoder *xml.Encoder, annotation FileAnnotation) error {
+	message := annotation.Message()
+	if pluginName := annotation.PluginName(); pluginName != "" {
+		message += " (" + pluginName + ")"
+	}
+	errorElement := xml.StartElement{Name: xml.Name{Local: "error"}}
+	if annotation.StartLine() != 0 {
+		errorElement.Attr = append(errorElement.Attr, xml.Attr{Name: xml.Name{Local: "line"}, Value: strconv.Itoa(annotation.StartLine())})
+	}
+	if annotation.StartColumn() != 0 {
+		errorElement.Attr = append(errorElement.Attr, xml.Attr{Name: xml.Name{Local: "column"}, Value: strconv.Itoa(annotation.StartColumn())})
+	}
+	errorElement.Attr = append(
+		errorElement.Attr,
+		xml.Attr{Name: xml.Name{Local: "severity"}, Value: annotation.Severity().String()},
+		xml.Attr{Name: xml.Name{Local: "message"}, Value: message},
+		xml.Attr{Name: xml.Name{Local: "source"}, Value: annotation.Type()},
+	)
+	if err := encoder.EncodeToken(errorElement); err != nil {
+		return err
+	}
+	return encoder.EncodeToken(xml.EndElement{Name: errorElement.Name})
+}
+
 type externalFileAnnotation struct {
 	Path        string `json:"path,omitempty" yaml:"path,omitempty"`
 	StartLine   int    `json:"start_line,omitempty" yaml:"start_line,omitempty"`
@@ -268,6 +553,8 @@ type externalFileAnnotation struct {
 	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
 	Message     string `json:"message,omitempty" yaml:"message,omitempty"`
 	Plugin      string `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	// Severity is empty for errors.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
 }
 
 func newExternalFileAnnotation(f FileAnnotation) externalFileAnnotation {
@@ -275,6 +562,10 @@ func newExternalFileAnnotation(f FileAnnotation) externalFileAnnotation {
 	if f.FileInfo() != nil {
 		path = f.FileInfo().ExternalPath()
 	}
+	severity := ""
+	if f.Severity() != SeverityError {
+		severity = f.Severity().String()
+	}
 	return externalFileAnnotation{
 		Path:        path,
 		StartLine:   atLeast1(f.StartLine()),
@@ -284,6 +575,7 @@ func newExternalFileAnnotation(f FileAnnotation) externalFileAnnotation {
 		Type:        f.Type(),
 		Message:     f.Message(),
 		Plugin:      f.PluginName(),
+		Severity:    severity,
 	}
 }
 
@@ -305,3 +597,83 @@ func printEachAnnotationOnNewLine(
 	}
 	return nil
 }
+
+// The subset of the SARIF 2.1.0 object model that we produce.
+//
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type gitLabCodeQualityIssue struct {
+	Description string                    `json:"description"`
+	CheckName   string                    `json:"check_name"`
+	Fingerprint string                    `json:"fingerprint"`
+	Severity    string                    `json:"severity"`
+	Location    gitLabCodeQualityLocation `json:"location"`
+}
+
+type gitLabCodeQualityLocation struct {
+	Path  string                 `json:"path"`
+	Lines gitLabCodeQualityLines `json:"lines"`
+}
+
+type gitLabCodeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
@@ -16,6 +16,7 @@ package bufcheck
 
 import (
 	"buf.build/go/bufplugin/check"
+	"buf.build/go/bufplugin/descriptor"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/pkg/standard/xslices"
 )
@@ -37,14 +38,21 @@ func (a *annotation) PluginName() string {
 	return a.pluginName
 }
 
+// annotationsToFileAnnotations converts the annotations to bufanalysis.FileAnnotations.
+//
+// The severities of the bufanalysis.FileAnnotations are taken from ruleIDToSeverity, and default
+// to bufanalysis.SeverityError. If fixer is not nil, the bufanalysis.FileAnnotations contain the
+// edits that fix them.
 func annotationsToFileAnnotations(
 	pathToExternalPath map[string]string,
 	annotations []*annotation,
+	ruleIDToSeverity map[string]bufanalysis.Severity,
+	fixer *fixer,
 ) []bufanalysis.FileAnnotation {
 	return xslices.Map(
 		annotations,
 		func(annotation *annotation) bufanalysis.FileAnnotation {
-			return annotationToFileAnnotation(pathToExternalPath, annotation)
+			return annotationToFileAnnotation(pathToExternalPath, annotation, ruleIDToSeverity, fixer)
 		},
 	)
 }
@@ -52,7 +60,13 @@ func annotationsToFileAnnotations(
 func annotationToFileAnnotation(
 	pathToExternalPath map[string]string,
 	annotation *annotation,
+	ruleIDToSeverity map[string]bufanalysis.Severity,
+	fixer *fixer,
 ) bufanalysis.FileAnnotation {
+	var options []bufanalysis.FileAnnotationOption
+	if severity, ok := ruleIDToSeverity[annotation.RuleID()]; ok {
+		options = append(options, bufanalysis.FileAnnotationWithSeverity(severity))
+	}
 	fileLocation := annotation.FileLocation()
 	if fileLocation == nil {
 		// We have to do this or we get a weird fileInfo != nil but it is nil thing.
@@ -65,6 +79,7 @@ func annotationToFileAnnotation(
 			annotation.RuleID(),
 			annotation.Message(),
 			annotation.PluginName(),
+			options...,
 		)
 	}
 	path := fileLocation.FileDescriptor().ProtoreflectFileDescriptor().Path()
@@ -75,6 +90,12 @@ func annotationToFileAnnotation(
 	startColumn := fileLocation.StartColumn() + 1
 	endLine := fileLocation.EndLine() + 1
 	endColumn := fileLocation.EndColumn() + 1
+	options = append(options, bufanalysis.FileAnnotationWithSymbol(symbolForFileLocation(fileLocation)))
+	if fixer != nil {
+		if edits := fixer.edits(annotation); len(edits) > 0 {
+			options = append(options, bufanalysis.FileAnnotationWithEdits(edits...))
+		}
+	}
 	return bufanalysis.NewFileAnnotation(
 		fileInfo,
 		startLine,
@@ -84,5 +105,38 @@ func annotationToFileAnnotation(
 		annotation.RuleID(),
 		annotation.Message(),
 		annotation.PluginName(),
+		options...,
 	)
 }
+
+// symbolForFileLocation returns a stable identity for the element of the file at the location.
+//
+// This is the full name of the innermost descriptor that contains the location, or for locations
+// outside of any descriptor, the kind of file-level element that contains it.
+func symbolForFileLocation(fileLocation descriptor.FileLocation) string {
+	file := fileLocation.FileDescriptor().ProtoreflectFileDescriptor()
+	sourcePath := fileLocation.SourcePath()
+	if descriptor := descriptorForSourcePath(file, sourcePath); descriptor != file {
+		return string(descriptor.FullName())
+	}
+	if len(sourcePath) == 0 {
+		return ""
+	}
+	switch sourcePath[0] {
+	case packageTypeTag:
+		return "package"
+	case dependenciesTypeTag:
+		if len(sourcePath) > 1 && int(sourcePath[1]) < file.Imports().Len() {
+			return "import " + file.Imports().Get(int(sourcePath[1])).Path()
+		}
+		return "import"
+	case syntaxTypeTag:
+		return "syntax"
+	case editionTypeTag:
+		return "edition"
+	case fileOptionsTypeTag:
+		return "option"
+	default:
+		return ""
+	}
+}
@@ -1014,6 +1014,30 @@ func TestRunBreakingOneofNoDelete(t *testing.T) {
 	)
 }
 
+func TestRunBreakingProtovalidateRules(t *testing.T) {
+	t.Parallel()
+	testBreaking(
+		t,
+		"breaking_protovalidate_rules",
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 13, 3, 17, 5, "MESSAGE_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 18, 23, 18, 62, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 19, 23, 19, 62, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 20, 24, 20, 60, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 23, 7, 23, 10, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 29, 7, 29, 10, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 33, 17, 33, 51, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 34, 17, 34, 50, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 35, 19, 39, 4, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 40, 23, 40, 65, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 41, 31, 41, 85, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 42, 3, 42, 64, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 43, 22, 43, 62, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 46, 5, 46, 19, "FIELD_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 49, 5, 49, 49, "ONEOF_NO_PROTOVALIDATE_TIGHTENING"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 79, 1, 81, 2, "MESSAGE_NO_PROTOVALIDATE_TIGHTENING"),
+	)
+}
+
 func TestRunBreakingPackageExtensionNoDelete(t *testing.T) {
 	t.Parallel()
 	testBreaking(
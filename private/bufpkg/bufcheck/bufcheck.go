@@ -110,6 +110,18 @@ type LintOption interface {
 	applyToLint(*lintOptions)
 }
 
+// LintWithFixes returns a new LintOption that says to compute the edits that mechanically
+// fix the FileAnnotations of the builtin Rules, where such a fix exists.
+//
+// The edits are available via FileAnnotation.Edits. Fixes are only computed when they can be
+// applied safely, that is when all the files that need to be edited are non-import files of
+// the Image.
+//
+// The default is to not compute fixes.
+func LintWithFixes() LintOption {
+	return &fixesOption{}
+}
+
 // BreakingOption is an option for Breaking.
 type BreakingOption interface {
 	applyToBreaking(*breakingOptions)
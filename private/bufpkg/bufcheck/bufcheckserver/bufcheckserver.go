@@ -349,6 +349,18 @@ var (
 			bufcheckserverbuild.BreakingFieldWireCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE"}),
 			bufcheckserverbuild.BreakingFieldWireCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE"}),
 			bufcheckserverbuild.BreakingMessageSameMessageSetWireFormatRuleSpecBuilder.Build(false, []string{}),
+			bufcheckserverbuild.BreakingFieldNoProtovalidateTighteningRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_RULES"}),
+			bufcheckserverbuild.BreakingMessageNoProtovalidateTighteningRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_RULES"}),
+			bufcheckserverbuild.BreakingOneofNoProtovalidateTighteningRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_RULES"}),
+			bufcheckserverbuild.LintAIPFieldBehaviorConflictRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPFieldBehaviorRequiredRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPListPaginationRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPResourceAnnotationRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPResourceNameFieldRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPStandardMethodRequestFieldsRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPStandardMethodRequestNameRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPStandardMethodResponseNameRuleSpecBuilder.Build(false, []string{"AIP"}),
+			bufcheckserverbuild.LintAIPUpdateMaskRuleSpecBuilder.Build(false, []string{"AIP"}),
 			bufcheckserverbuild.LintCommentEnumRuleSpecBuilder.Build(false, []string{"COMMENTS"}),
 			bufcheckserverbuild.LintCommentEnumValueRuleSpecBuilder.Build(false, []string{"COMMENTS"}),
 			bufcheckserverbuild.LintCommentFieldRuleSpecBuilder.Build(false, []string{"COMMENTS"}),
@@ -402,6 +414,8 @@ var (
 			bufcheckserverbuild.PackageCategorySpec,
 			bufcheckserverbuild.WireCategorySpec,
 			bufcheckserverbuild.WireJSONCategorySpec,
+			bufcheckserverbuild.ProtovalidateRulesCategorySpec,
+			bufcheckserverbuild.AIPCategorySpec,
 			bufcheckserverbuild.BasicCategorySpec,
 			bufcheckserverbuild.CommentsCategorySpec,
 			bufcheckserverbuild.DefaultCategorySpec,
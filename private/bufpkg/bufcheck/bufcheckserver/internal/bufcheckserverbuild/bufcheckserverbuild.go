@@ -107,6 +107,13 @@ var (
 		Type:    check.RuleTypeBreaking,
 		Handler: bufcheckserverhandle.HandleBreakingFieldNoDeleteUnlessNumberReserved,
 	}
+	// BreakingFieldNoProtovalidateTighteningRuleSpecBuilder is a rule spec builder.
+	BreakingFieldNoProtovalidateTighteningRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "FIELD_NO_PROTOVALIDATE_TIGHTENING",
+		Purpose: "Checks that protovalidate rules on fields are not tightened.",
+		Type:    check.RuleTypeBreaking,
+		Handler: bufcheckserverhandle.HandleBreakingFieldNoProtovalidateTightening,
+	}
 	// BreakingFieldSameCardinalityRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameCardinalityRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
 		ID:      "FIELD_SAME_CARDINALITY",
@@ -413,6 +420,13 @@ var (
 		Type:    check.RuleTypeBreaking,
 		Handler: bufcheckserverhandle.HandleBreakingMessageNoDelete,
 	}
+	// BreakingMessageNoProtovalidateTighteningRuleSpecBuilder is a rule spec builder.
+	BreakingMessageNoProtovalidateTighteningRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "MESSAGE_NO_PROTOVALIDATE_TIGHTENING",
+		Purpose: "Checks that protovalidate rules on messages are not tightened.",
+		Type:    check.RuleTypeBreaking,
+		Handler: bufcheckserverhandle.HandleBreakingMessageNoProtovalidateTightening,
+	}
 	// BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder is a rule spec builder.
 	BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
 		ID:      "MESSAGE_NO_REMOVE_STANDARD_DESCRIPTOR_ACCESSOR",
@@ -456,6 +470,13 @@ var (
 		Type:    check.RuleTypeBreaking,
 		Handler: bufcheckserverhandle.HandleBreakingOneofNoDelete,
 	}
+	// BreakingOneofNoProtovalidateTighteningRuleSpecBuilder is a rule spec builder.
+	BreakingOneofNoProtovalidateTighteningRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "ONEOF_NO_PROTOVALIDATE_TIGHTENING",
+		Purpose: "Checks that protovalidate rules on oneofs are not tightened.",
+		Type:    check.RuleTypeBreaking,
+		Handler: bufcheckserverhandle.HandleBreakingOneofNoProtovalidateTightening,
+	}
 	// BreakingPackageEnumNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingPackageEnumNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
 		ID:      "PACKAGE_ENUM_NO_DELETE",
@@ -554,6 +575,69 @@ var (
 		Type:    check.RuleTypeBreaking,
 		Handler: bufcheckserverhandle.HandleBreakingServiceNoDelete,
 	}
+	// LintAIPFieldBehaviorConflictRuleSpecBuilder is a rule spec builder.
+	LintAIPFieldBehaviorConflictRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_FIELD_BEHAVIOR_CONFLICT",
+		Purpose: "Checks that fields are not annotated with conflicting google.api.field_behavior values.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPFieldBehaviorConflict,
+	}
+	// LintAIPFieldBehaviorRequiredRuleSpecBuilder is a rule spec builder.
+	LintAIPFieldBehaviorRequiredRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_FIELD_BEHAVIOR_REQUIRED",
+		Purpose: "Checks that the name, parent, and resource fields of AIP standard method requests are annotated as REQUIRED.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPFieldBehaviorRequired,
+	}
+	// LintAIPListPaginationRuleSpecBuilder is a rule spec builder.
+	LintAIPListPaginationRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_LIST_PAGINATION",
+		Purpose: "Checks that AIP List methods have page_size, page_token, and next_page_token fields.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPListPagination,
+	}
+	// LintAIPResourceAnnotationRuleSpecBuilder is a rule spec builder.
+	LintAIPResourceAnnotationRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_RESOURCE_ANNOTATION",
+		Purpose: "Checks that google.api.resource annotations have a type matching the message name and at least one pattern.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPResourceAnnotation,
+	}
+	// LintAIPResourceNameFieldRuleSpecBuilder is a rule spec builder.
+	LintAIPResourceNameFieldRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_RESOURCE_NAME_FIELD",
+		Purpose: "Checks that messages with a google.api.resource annotation have a string name field.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPResourceNameField,
+	}
+	// LintAIPStandardMethodRequestFieldsRuleSpecBuilder is a rule spec builder.
+	LintAIPStandardMethodRequestFieldsRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_STANDARD_METHOD_REQUEST_FIELDS",
+		Purpose: "Checks that AIP standard method requests have the name or resource field for their method.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPStandardMethodRequestFields,
+	}
+	// LintAIPStandardMethodRequestNameRuleSpecBuilder is a rule spec builder.
+	LintAIPStandardMethodRequestNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_STANDARD_METHOD_REQUEST_NAME",
+		Purpose: "Checks that AIP standard method request types are named MethodNameRequest.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPStandardMethodRequestName,
+	}
+	// LintAIPStandardMethodResponseNameRuleSpecBuilder is a rule spec builder.
+	LintAIPStandardMethodResponseNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_STANDARD_METHOD_RESPONSE_NAME",
+		Purpose: "Checks that AIP standard methods return the resource, a ListResourcesResponse, or google.protobuf.Empty as appropriate.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPStandardMethodResponseName,
+	}
+	// LintAIPUpdateMaskRuleSpecBuilder is a rule spec builder.
+	LintAIPUpdateMaskRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:      "AIP_UPDATE_MASK",
+		Purpose: "Checks that AIP Update method requests have a google.protobuf.FieldMask update_mask field.",
+		Type:    check.RuleTypeLint,
+		Handler: bufcheckserverhandle.HandleLintAIPUpdateMask,
+	}
 	// LintCommentEnumRuleSpecBuilder is a rule spec builder.
 	LintCommentEnumRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
 		ID:      "COMMENT_ENUM",
@@ -906,6 +990,11 @@ var (
 		ID:      "PACKAGE",
 		Purpose: "Checks that there are no source-code breaking changes at the per-package level.",
 	}
+	// ProtovalidateRulesCategorySpec is a category spec.
+	ProtovalidateRulesCategorySpec = &check.CategorySpec{
+		ID:      "PROTOVALIDATE_RULES",
+		Purpose: "Checks that protovalidate rules are not tightened to reject values that were previously accepted.",
+	}
 	// WireCategorySpec is a category spec.
 	WireCategorySpec = &check.CategorySpec{
 		ID:      "WIRE",
@@ -917,6 +1006,11 @@ var (
 		Purpose: "Checks that there are no wire breaking changes for the binary or JSON encodings.",
 	}
 
+	// AIPCategorySpec is a category spec.
+	AIPCategorySpec = &check.CategorySpec{
+		ID:      "AIP",
+		Purpose: "Checks that Google API Improvement Proposals (AIPs) are followed.",
+	}
 	// BasicCategorySpec is a category spec.
 	BasicCategorySpec = &check.CategorySpec{
 		ID:      "BASIC",
@@ -0,0 +1,257 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheckserverhandle
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
+	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
+	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	aipStandardMethodTypeGet aipStandardMethodType = iota + 1
+	aipStandardMethodTypeList
+	aipStandardMethodTypeCreate
+	aipStandardMethodTypeUpdate
+	aipStandardMethodTypeDelete
+)
+
+var (
+	// aipStandardMethodTypes are the standard method types in the order
+	// their prefixes are matched against method names.
+	aipStandardMethodTypes = []aipStandardMethodType{
+		aipStandardMethodTypeGet,
+		aipStandardMethodTypeList,
+		aipStandardMethodTypeCreate,
+		aipStandardMethodTypeUpdate,
+		aipStandardMethodTypeDelete,
+	}
+	aipStandardMethodTypeToPrefix = map[aipStandardMethodType]string{
+		aipStandardMethodTypeGet:    "Get",
+		aipStandardMethodTypeList:   "List",
+		aipStandardMethodTypeCreate: "Create",
+		aipStandardMethodTypeUpdate: "Update",
+		aipStandardMethodTypeDelete: "Delete",
+	}
+	// aipConflictingFieldBehaviors are the pairs of google.api.field_behavior
+	// values that cannot be applied to the same field.
+	//
+	// IDENTIFIER is handled separately, as it cannot be combined with any other value.
+	aipConflictingFieldBehaviors = [][2]annotations.FieldBehavior{
+		{annotations.FieldBehavior_REQUIRED, annotations.FieldBehavior_OPTIONAL},
+		{annotations.FieldBehavior_REQUIRED, annotations.FieldBehavior_OUTPUT_ONLY},
+		{annotations.FieldBehavior_OPTIONAL, annotations.FieldBehavior_OUTPUT_ONLY},
+		{annotations.FieldBehavior_INPUT_ONLY, annotations.FieldBehavior_OUTPUT_ONLY},
+	}
+)
+
+// aipStandardMethodType is the type of an AIP standard method.
+//
+// See https://google.aip.dev/130.
+type aipStandardMethodType int
+
+// aipStandardMethod is a method that follows the naming of an AIP standard method.
+type aipStandardMethod struct {
+	method     bufprotosource.Method
+	methodType aipStandardMethodType
+	// resourceName is the method name without the standard method prefix.
+	//
+	// This is plural for List methods, and singular otherwise.
+	resourceName string
+	// request is nil if the request message could not be found.
+	request bufprotosource.Message
+	// response is nil if the response message could not be found.
+	response bufprotosource.Message
+}
+
+// forEachAIPStandardMethod calls f for each unary method in the files that is
+// named like an AIP standard method.
+//
+// Request and response messages are resolved against all files in the request,
+// including imports.
+func forEachAIPStandardMethod(
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+	f func(*aipStandardMethod) error,
+) error {
+	fullNameToMessage, err := bufprotosource.FullNameToMessage(request.ProtosourceFiles()...)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		for _, service := range file.Services() {
+			for _, method := range service.Methods() {
+				standardMethod := newAIPStandardMethod(method, fullNameToMessage)
+				if standardMethod == nil {
+					continue
+				}
+				if err := f(standardMethod); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newAIPStandardMethod returns nil if the method is not an AIP standard method.
+func newAIPStandardMethod(
+	method bufprotosource.Method,
+	fullNameToMessage map[string]bufprotosource.Message,
+) *aipStandardMethod {
+	if method.ClientStreaming() || method.ServerStreaming() {
+		return nil
+	}
+	for _, methodType := range aipStandardMethodTypes {
+		resourceName, ok := strings.CutPrefix(method.Name(), aipStandardMethodTypeToPrefix[methodType])
+		if !ok || resourceName == "" || !unicode.IsUpper([]rune(resourceName)[0]) {
+			continue
+		}
+		return &aipStandardMethod{
+			method:       method,
+			methodType:   methodType,
+			resourceName: resourceName,
+			request:      fullNameToMessage[method.InputTypeName()],
+			response:     fullNameToMessage[method.OutputTypeName()],
+		}
+	}
+	return nil
+}
+
+// resourceFieldName returns the name of the field that contains the resource
+// on Create and Update requests.
+func (m *aipStandardMethod) resourceFieldName() string {
+	return xstrings.ToLowerSnakeCase(m.resourceName)
+}
+
+// checkAIPMessageField adds an annotation if the message does not have a field
+// with the given name that matches isValidField.
+//
+// typeDescription describes the expected field type in the annotation message.
+func checkAIPMessageField(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	standardMethod *aipStandardMethod,
+	message bufprotosource.Message,
+	fieldName string,
+	typeDescription string,
+	isValidField func(bufprotosource.Field) bool,
+) {
+	field := getAIPField(message, fieldName)
+	if field != nil && isValidField(field) {
+		return
+	}
+	location := message.Location()
+	if field != nil {
+		location = field.TypeLocation()
+	}
+	responseWriter.AddProtosourceAnnotation(
+		location,
+		nil,
+		message.File().Path(),
+		"Message %q used by standard method %q should have a field %q of type %s.",
+		message.Name(),
+		standardMethod.method.Name(),
+		fieldName,
+		typeDescription,
+	)
+}
+
+// getAIPField returns nil if the message has no field with the given name.
+func getAIPField(message bufprotosource.Message, fieldName string) bufprotosource.Field {
+	for _, field := range message.Fields() {
+		if field.Name() == fieldName {
+			return field
+		}
+	}
+	return nil
+}
+
+func isAIPSingularFieldOfType(field bufprotosource.Field, fieldType descriptorpb.FieldDescriptorProto_Type) bool {
+	return field.Label() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED && field.Type() == fieldType
+}
+
+func isAIPSingularMessageFieldOfTypeName(field bufprotosource.Field, typeName string) bool {
+	return isAIPSingularFieldOfType(field, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE) &&
+		(field.TypeName() == typeName || aipSimpleName(field.TypeName()) == typeName)
+}
+
+// aipSimpleName returns the last component of a fully-qualified name.
+func aipSimpleName(fullName string) string {
+	if index := strings.LastIndexByte(fullName, '.'); index >= 0 {
+		return fullName[index+1:]
+	}
+	return fullName
+}
+
+// getAIPResourceDescriptor returns the google.api.resource option of the message,
+// or nil if it is not set.
+func getAIPResourceDescriptor(message bufprotosource.Message) (*annotations.ResourceDescriptor, error) {
+	messageDescriptor, err := message.AsDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	options := &descriptorpb.MessageOptions{}
+	if err := reparseAIPOptions(messageDescriptor.Options(), options); err != nil {
+		return nil, err
+	}
+	if !proto.HasExtension(options, annotations.E_Resource) {
+		return nil, nil
+	}
+	resourceDescriptor, ok := proto.GetExtension(options, annotations.E_Resource).(*annotations.ResourceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for google.api.resource option of message %s", message.FullName())
+	}
+	return resourceDescriptor, nil
+}
+
+// getAIPFieldBehaviors returns the google.api.field_behavior values of the field.
+func getAIPFieldBehaviors(field bufprotosource.Field) ([]annotations.FieldBehavior, error) {
+	fieldDescriptor, err := field.AsDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	options := &descriptorpb.FieldOptions{}
+	if err := reparseAIPOptions(fieldDescriptor.Options(), options); err != nil {
+		return nil, err
+	}
+	if !proto.HasExtension(options, annotations.E_FieldBehavior) {
+		return nil, nil
+	}
+	fieldBehaviors, ok := proto.GetExtension(options, annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for google.api.field_behavior option of field %s", field.FullName())
+	}
+	return fieldBehaviors, nil
+}
+
+// reparseAIPOptions round-trips the options through the wire format into target.
+//
+// The google.api extensions may have been parsed as unknown fields or as dynamic
+// extensions, depending on where the descriptor came from. Round-tripping makes
+// them the generated types from the annotations package.
+func reparseAIPOptions(options proto.Message, target proto.Message) error {
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, target)
+}
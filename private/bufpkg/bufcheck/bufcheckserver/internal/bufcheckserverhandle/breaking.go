@@ -24,6 +24,7 @@ import (
 
 	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/buflintvalidate"
 	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
 	"github.com/bufbuild/buf/private/gen/proto/go/google/protobuf"
 	"github.com/bufbuild/buf/private/pkg/standard/xslices"
@@ -2395,3 +2396,96 @@ func handleBreakingFieldWireCompatibleCardinality(
 	}
 	return nil
 }
+
+// HandleBreakingFieldNoProtovalidateTightening is a check function.
+var HandleBreakingFieldNoProtovalidateTightening = bufcheckserverutil.NewBreakingFieldPairRuleHandler(handleBreakingFieldNoProtovalidateTightening)
+
+func handleBreakingFieldNoProtovalidateTightening(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+) error {
+	return buflintvalidate.CheckFieldNotTightened(
+		func(location bufprotosource.Location, againstLocation bufprotosource.Location, description string) {
+			responseWriter.AddProtosourceAnnotation(
+				withBackupLocation(location, field.Location()),
+				withBackupLocation(againstLocation, previousField.Location()),
+				field.File().Path(),
+				`%s %s.`,
+				fieldDescription(field),
+				description,
+			)
+		},
+		previousField,
+		field,
+	)
+}
+
+// HandleBreakingMessageNoProtovalidateTightening is a check function.
+var HandleBreakingMessageNoProtovalidateTightening = bufcheckserverutil.NewBreakingMessagePairRuleHandler(handleBreakingMessageNoProtovalidateTightening)
+
+func handleBreakingMessageNoProtovalidateTightening(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	message bufprotosource.Message,
+	previousMessage bufprotosource.Message,
+) error {
+	return buflintvalidate.CheckMessageNotTightened(
+		func(location bufprotosource.Location, againstLocation bufprotosource.Location, description string) {
+			responseWriter.AddProtosourceAnnotation(
+				withBackupLocation(location, message.Location()),
+				withBackupLocation(againstLocation, previousMessage.Location()),
+				message.File().Path(),
+				`Message %q %s.`,
+				message.Name(),
+				description,
+			)
+		},
+		previousMessage,
+		message,
+	)
+}
+
+// HandleBreakingOneofNoProtovalidateTightening is a check function.
+var HandleBreakingOneofNoProtovalidateTightening = bufcheckserverutil.NewBreakingMessagePairRuleHandler(handleBreakingOneofNoProtovalidateTightening)
+
+func handleBreakingOneofNoProtovalidateTightening(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	message bufprotosource.Message,
+	previousMessage bufprotosource.Message,
+) error {
+	previousNameToOneof, err := bufprotosource.NameToMessageOneof(previousMessage)
+	if err != nil {
+		return err
+	}
+	nameToOneof, err := bufprotosource.NameToMessageOneof(message)
+	if err != nil {
+		return err
+	}
+	for previousName, previousOneof := range previousNameToOneof {
+		oneof, ok := nameToOneof[previousName]
+		if !ok {
+			continue
+		}
+		if err := buflintvalidate.CheckOneofNotTightened(
+			func(location bufprotosource.Location, againstLocation bufprotosource.Location, description string) {
+				responseWriter.AddProtosourceAnnotation(
+					withBackupLocation(location, oneof.Location()),
+					withBackupLocation(againstLocation, previousOneof.Location()),
+					message.File().Path(),
+					`Oneof %q on message %q %s.`,
+					oneof.Name(),
+					message.Name(),
+					description,
+				)
+			},
+			previousOneof,
+			oneof,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -32,10 +33,387 @@ import (
 	"github.com/bufbuild/buf/private/pkg/protoversion"
 	"github.com/bufbuild/buf/private/pkg/standard/xslices"
 	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
+	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
+var (
+	// HandleLintAIPFieldBehaviorConflict is a handle function.
+	HandleLintAIPFieldBehaviorConflict = bufcheckserverutil.NewLintFieldRuleHandler(handleLintAIPFieldBehaviorConflict)
+	// HandleLintAIPFieldBehaviorRequired is a handle function.
+	HandleLintAIPFieldBehaviorRequired = bufcheckserverutil.NewLintFilesRuleHandler(handleLintAIPFieldBehaviorRequired)
+	// HandleLintAIPListPagination is a handle function.
+	HandleLintAIPListPagination = bufcheckserverutil.NewLintFilesRuleHandler(handleLintAIPListPagination)
+	// HandleLintAIPResourceAnnotation is a handle function.
+	HandleLintAIPResourceAnnotation = bufcheckserverutil.NewLintMessageRuleHandler(handleLintAIPResourceAnnotation)
+	// HandleLintAIPResourceNameField is a handle function.
+	HandleLintAIPResourceNameField = bufcheckserverutil.NewLintMessageRuleHandler(handleLintAIPResourceNameField)
+	// HandleLintAIPStandardMethodRequestFields is a handle function.
+	HandleLintAIPStandardMethodRequestFields = bufcheckserverutil.NewLintFilesRuleHandler(handleLintAIPStandardMethodRequestFields)
+	// HandleLintAIPStandardMethodRequestName is a handle function.
+	HandleLintAIPStandardMethodRequestName = bufcheckserverutil.NewLintFilesRuleHandler(handleLintAIPStandardMethodRequestName)
+	// HandleLintAIPStandardMethodResponseName is a handle function.
+	HandleLintAIPStandardMethodResponseName = bufcheckserverutil.NewLintFilesRuleHandler(handleLintAIPStandardMethodResponseName)
+	// HandleLintAIPUpdateMask is a handle function.
+	HandleLintAIPUpdateMask = bufcheckserverutil.NewLintFilesRuleHandler(handleLintAIPUpdateMask)
+)
+
+func handleLintAIPFieldBehaviorConflict(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	field bufprotosource.Field,
+) error {
+	fieldBehaviors, err := getAIPFieldBehaviors(field)
+	if err != nil {
+		return err
+	}
+	fieldBehaviorSet := make(map[annotations.FieldBehavior]struct{}, len(fieldBehaviors))
+	for _, fieldBehavior := range fieldBehaviors {
+		fieldBehaviorSet[fieldBehavior] = struct{}{}
+	}
+	addConflict := func(first annotations.FieldBehavior, second annotations.FieldBehavior) {
+		responseWriter.AddProtosourceAnnotation(
+			field.OptionExtensionLocation(annotations.E_FieldBehavior),
+			nil,
+			field.File().Path(),
+			"Field %q has conflicting google.api.field_behavior values %s and %s.",
+			field.Name(),
+			first.String(),
+			second.String(),
+		)
+	}
+	if _, ok := fieldBehaviorSet[annotations.FieldBehavior_IDENTIFIER]; ok {
+		for _, fieldBehavior := range fieldBehaviors {
+			if fieldBehavior != annotations.FieldBehavior_IDENTIFIER {
+				addConflict(annotations.FieldBehavior_IDENTIFIER, fieldBehavior)
+			}
+		}
+	}
+	for _, conflictingFieldBehaviors := range aipConflictingFieldBehaviors {
+		_, hasFirst := fieldBehaviorSet[conflictingFieldBehaviors[0]]
+		_, hasSecond := fieldBehaviorSet[conflictingFieldBehaviors[1]]
+		if hasFirst && hasSecond {
+			addConflict(conflictingFieldBehaviors[0], conflictingFieldBehaviors[1])
+		}
+	}
+	return nil
+}
+
+func handleLintAIPFieldBehaviorRequired(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	return forEachAIPStandardMethod(
+		request,
+		files,
+		func(standardMethod *aipStandardMethod) error {
+			if standardMethod.request == nil {
+				return nil
+			}
+			var fieldNames []string
+			switch standardMethod.methodType {
+			case aipStandardMethodTypeGet, aipStandardMethodTypeDelete:
+				fieldNames = []string{"name"}
+			case aipStandardMethodTypeList:
+				fieldNames = []string{"parent"}
+			case aipStandardMethodTypeCreate:
+				fieldNames = []string{"parent", standardMethod.resourceFieldName()}
+			case aipStandardMethodTypeUpdate:
+				fieldNames = []string{standardMethod.resourceFieldName()}
+			}
+			for _, fieldName := range fieldNames {
+				// Missing fields are reported by AIP_STANDARD_METHOD_REQUEST_FIELDS.
+				field := getAIPField(standardMethod.request, fieldName)
+				if field == nil {
+					continue
+				}
+				fieldBehaviors, err := getAIPFieldBehaviors(field)
+				if err != nil {
+					return err
+				}
+				if slices.Contains(fieldBehaviors, annotations.FieldBehavior_REQUIRED) {
+					continue
+				}
+				responseWriter.AddProtosourceAnnotation(
+					field.Location(),
+					nil,
+					field.File().Path(),
+					"Field %q of message %q used by standard method %q should be annotated with (google.api.field_behavior) = REQUIRED.",
+					field.Name(),
+					standardMethod.request.Name(),
+					standardMethod.method.Name(),
+				)
+			}
+			return nil
+		},
+	)
+}
+
+func handleLintAIPListPagination(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	return forEachAIPStandardMethod(
+		request,
+		files,
+		func(standardMethod *aipStandardMethod) error {
+			if standardMethod.methodType != aipStandardMethodTypeList {
+				return nil
+			}
+			if standardMethod.request != nil {
+				checkAIPMessageField(
+					responseWriter,
+					standardMethod,
+					standardMethod.request,
+					"page_size",
+					"int32",
+					func(field bufprotosource.Field) bool {
+						return isAIPSingularFieldOfType(field, descriptorpb.FieldDescriptorProto_TYPE_INT32)
+					},
+				)
+				checkAIPMessageField(
+					responseWriter,
+					standardMethod,
+					standardMethod.request,
+					"page_token",
+					"string",
+					func(field bufprotosource.Field) bool {
+						return isAIPSingularFieldOfType(field, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+					},
+				)
+			}
+			if standardMethod.response != nil {
+				checkAIPMessageField(
+					responseWriter,
+					standardMethod,
+					standardMethod.response,
+					"next_page_token",
+					"string",
+					func(field bufprotosource.Field) bool {
+						return isAIPSingularFieldOfType(field, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+					},
+				)
+			}
+			return nil
+		},
+	)
+}
+
+func handleLintAIPResourceAnnotation(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	message bufprotosource.Message,
+) error {
+	resourceDescriptor, err := getAIPResourceDescriptor(message)
+	if err != nil {
+		return err
+	}
+	if resourceDescriptor == nil {
+		return nil
+	}
+	serviceName, kind, ok := strings.Cut(resourceDescriptor.GetType(), "/")
+	if !ok || serviceName == "" || kind != message.Name() {
+		responseWriter.AddProtosourceAnnotation(
+			// 1 is the field number of type on google.api.ResourceDescriptor.
+			message.OptionExtensionLocation(annotations.E_Resource, 1),
+			nil,
+			message.File().Path(),
+			"Resource type %q of message %q should be of the form \"{Service Name}/%s\".",
+			resourceDescriptor.GetType(),
+			message.Name(),
+			message.Name(),
+		)
+	}
+	if len(resourceDescriptor.GetPattern()) == 0 {
+		responseWriter.AddProtosourceAnnotation(
+			message.OptionExtensionLocation(annotations.E_Resource),
+			nil,
+			message.File().Path(),
+			"Resource annotation of message %q should have at least one pattern.",
+			message.Name(),
+		)
+	}
+	return nil
+}
+
+func handleLintAIPResourceNameField(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	message bufprotosource.Message,
+) error {
+	resourceDescriptor, err := getAIPResourceDescriptor(message)
+	if err != nil {
+		return err
+	}
+	if resourceDescriptor == nil {
+		return nil
+	}
+	nameFieldName := resourceDescriptor.GetNameField()
+	if nameFieldName == "" {
+		nameFieldName = "name"
+	}
+	nameField := getAIPField(message, nameFieldName)
+	if nameField == nil {
+		responseWriter.AddProtosourceAnnotation(
+			message.Location(),
+			nil,
+			message.File().Path(),
+			"Resource message %q should have a field %q of type string.",
+			message.Name(),
+			nameFieldName,
+		)
+		return nil
+	}
+	if !isAIPSingularFieldOfType(nameField, descriptorpb.FieldDescriptorProto_TYPE_STRING) {
+		responseWriter.AddProtosourceAnnotation(
+			nameField.TypeLocation(),
+			nil,
+			nameField.File().Path(),
+			"Field %q of resource message %q should be of type string.",
+			nameField.Name(),
+			message.Name(),
+		)
+	}
+	return nil
+}
+
+func handleLintAIPStandardMethodRequestFields(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	return forEachAIPStandardMethod(
+		request,
+		files,
+		func(standardMethod *aipStandardMethod) error {
+			if standardMethod.request == nil {
+				return nil
+			}
+			switch standardMethod.methodType {
+			case aipStandardMethodTypeGet, aipStandardMethodTypeDelete:
+				checkAIPMessageField(
+					responseWriter,
+					standardMethod,
+					standardMethod.request,
+					"name",
+					"string",
+					func(field bufprotosource.Field) bool {
+						return isAIPSingularFieldOfType(field, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+					},
+				)
+			case aipStandardMethodTypeCreate, aipStandardMethodTypeUpdate:
+				checkAIPMessageField(
+					responseWriter,
+					standardMethod,
+					standardMethod.request,
+					standardMethod.resourceFieldName(),
+					strconv.Quote(standardMethod.resourceName),
+					func(field bufprotosource.Field) bool {
+						return isAIPSingularMessageFieldOfTypeName(field, standardMethod.resourceName)
+					},
+				)
+			}
+			return nil
+		},
+	)
+}
+
+func handleLintAIPStandardMethodRequestName(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	return forEachAIPStandardMethod(
+		request,
+		files,
+		func(standardMethod *aipStandardMethod) error {
+			expectedRequestName := standardMethod.method.Name() + "Request"
+			if aipSimpleName(standardMethod.method.InputTypeName()) != expectedRequestName {
+				responseWriter.AddProtosourceAnnotation(
+					standardMethod.method.InputTypeLocation(),
+					nil,
+					standardMethod.method.File().Path(),
+					"Standard method %q should have request type %q but has %q.",
+					standardMethod.method.Name(),
+					expectedRequestName,
+					standardMethod.method.InputTypeName(),
+				)
+			}
+			return nil
+		},
+	)
+}
+
+func handleLintAIPStandardMethodResponseName(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	return forEachAIPStandardMethod(
+		request,
+		files,
+		func(standardMethod *aipStandardMethod) error {
+			var expectedResponseNames []string
+			switch standardMethod.methodType {
+			case aipStandardMethodTypeGet:
+				expectedResponseNames = []string{standardMethod.resourceName}
+			case aipStandardMethodTypeList:
+				expectedResponseNames = []string{standardMethod.method.Name() + "Response"}
+			case aipStandardMethodTypeCreate, aipStandardMethodTypeUpdate:
+				expectedResponseNames = []string{standardMethod.resourceName, "google.longrunning.Operation"}
+			case aipStandardMethodTypeDelete:
+				expectedResponseNames = []string{"google.protobuf.Empty", standardMethod.resourceName, "google.longrunning.Operation"}
+			}
+			outputTypeName := standardMethod.method.OutputTypeName()
+			for _, expectedResponseName := range expectedResponseNames {
+				if outputTypeName == expectedResponseName || (!strings.Contains(expectedResponseName, ".") && aipSimpleName(outputTypeName) == expectedResponseName) {
+					return nil
+				}
+			}
+			responseWriter.AddProtosourceAnnotation(
+				standardMethod.method.OutputTypeLocation(),
+				nil,
+				standardMethod.method.File().Path(),
+				"Standard method %q should have response type %s but has %q.",
+				standardMethod.method.Name(),
+				xstrings.SliceToHumanStringOrQuoted(expectedResponseNames),
+				outputTypeName,
+			)
+			return nil
+		},
+	)
+}
+
+func handleLintAIPUpdateMask(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	return forEachAIPStandardMethod(
+		request,
+		files,
+		func(standardMethod *aipStandardMethod) error {
+			if standardMethod.methodType != aipStandardMethodTypeUpdate || standardMethod.request == nil {
+				return nil
+			}
+			checkAIPMessageField(
+				responseWriter,
+				standardMethod,
+				standardMethod.request,
+				"update_mask",
+				`"google.protobuf.FieldMask"`,
+				func(field bufprotosource.Field) bool {
+					return isAIPSingularMessageFieldOfTypeName(field, "google.protobuf.FieldMask")
+				},
+			)
+			return nil
+		},
+	)
+}
+
 var (
 	// HandleLintCommentEnum is a handle function.
 	HandleLintCommentEnum = bufcheckserverutil.NewLintEnumRuleHandler(handleLintCommentEnum)
@@ -95,15 +95,21 @@ func (a *adder) fieldName() string {
 }
 
 func (a *adder) getFieldRuleName(path ...int32) string {
-	name := "(buf.validate.field)"
-	fields := fieldRulesDescriptor.Fields()
 	combinedPath := path
 	if len(a.basePath) > 0 {
 		combinedPath = make([]int32, len(a.basePath), len(a.basePath)+len(path))
 		copy(combinedPath, a.basePath)
 		combinedPath = append(combinedPath, path...)
 	}
-	for _, fieldNumber := range combinedPath {
+	return getFieldRuleNameForPath(combinedPath)
+}
+
+// getFieldRuleNameForPath returns the name of the rule at the path within
+// (buf.validate.field), such as (buf.validate.field).string.max_len.
+func getFieldRuleNameForPath(path []int32) string {
+	name := "(buf.validate.field)"
+	fields := fieldRulesDescriptor.Fields()
+	for _, fieldNumber := range path {
 		subField := fields.ByNumber(protowire.Number(fieldNumber))
 		if subField == nil {
 			return name
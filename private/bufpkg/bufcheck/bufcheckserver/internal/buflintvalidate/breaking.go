@@ -0,0 +1,522 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflintvalidate
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"buf.build/go/protovalidate/resolve"
+	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// https://buf.build/bufbuild/protovalidate/docs/main:buf.validate#buf.validate.OneofRules
+const requiredFieldNumberInOneofRules = 1
+
+var (
+	durationFullName  = (&durationpb.Duration{}).ProtoReflect().Descriptor().FullName()
+	timestampFullName = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+)
+
+// CheckFieldNotTightened checks that the rules on the field do not reject any value
+// accepted by the rules on the previous field.
+//
+// Rules are tightened if, for example, a max_len is lowered, required is added,
+// values are removed from an in list, or a CEL expression is added. Rules that cannot
+// be ordered, such as pattern or const, are tightened whenever they are added or changed.
+//
+// The description passed to addAnnotationFunc starts with a verb, such as
+// "added (buf.validate.field).required", and is meant to follow a description
+// of the field.
+func CheckFieldNotTightened(
+	addAnnotationFunc func(bufprotosource.Location, bufprotosource.Location, string),
+	previousField bufprotosource.Field,
+	field bufprotosource.Field,
+) error {
+	previousFieldDescriptor, err := previousField.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	fieldDescriptor, err := field.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	previousFieldRules, err := resolve.FieldRules(previousFieldDescriptor)
+	if err != nil {
+		return err
+	}
+	fieldRules, err := resolve.FieldRules(fieldDescriptor)
+	if err != nil {
+		return err
+	}
+	checker := &tighteningChecker{
+		add: func(path []int32, format string, args ...any) {
+			addAnnotationFunc(
+				field.OptionExtensionLocation(validate.E_Field, path...),
+				previousField.OptionExtensionLocation(validate.E_Field, path...),
+				fmt.Sprintf(format, args...),
+			)
+		},
+	}
+	checker.checkFieldRules(nil, previousFieldRules, fieldRules)
+	return nil
+}
+
+// CheckMessageNotTightened checks that the rules on the message do not reject any value
+// accepted by the rules on the previous message.
+//
+// Rules are tightened if a CEL expression is added or changed, or if
+// (buf.validate.message).disabled is removed.
+//
+// The description passed to addAnnotationFunc starts with a verb and is meant to
+// follow a description of the message.
+func CheckMessageNotTightened(
+	addAnnotationFunc func(bufprotosource.Location, bufprotosource.Location, string),
+	previousMessage bufprotosource.Message,
+	message bufprotosource.Message,
+) error {
+	previousMessageDescriptor, err := previousMessage.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	messageDescriptor, err := message.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	previousMessageRules, err := resolve.MessageRules(previousMessageDescriptor)
+	if err != nil {
+		return err
+	}
+	messageRules, err := resolve.MessageRules(messageDescriptor)
+	if err != nil {
+		return err
+	}
+	checker := &tighteningChecker{
+		add: func(path []int32, format string, args ...any) {
+			addAnnotationFunc(
+				message.OptionExtensionLocation(validate.E_Message, path...),
+				previousMessage.OptionExtensionLocation(validate.E_Message, path...),
+				fmt.Sprintf(format, args...),
+			)
+		},
+	}
+	if messageRules.GetDisabled() {
+		// None of the rules are applied.
+		return nil
+	}
+	if previousMessageRules.GetDisabled() {
+		checker.add(
+			[]int32{disabledFieldNumberInMessageRules},
+			"removed (buf.validate.message).disabled",
+		)
+		return nil
+	}
+	checker.checkCEL(
+		[]int32{celFieldNumberInMessageRules},
+		previousMessageRules.GetCel(),
+		messageRules.GetCel(),
+	)
+	return nil
+}
+
+// CheckOneofNotTightened checks that (buf.validate.oneof).required is not added to the oneof.
+//
+// The description passed to addAnnotationFunc starts with a verb and is meant to
+// follow a description of the oneof.
+func CheckOneofNotTightened(
+	addAnnotationFunc func(bufprotosource.Location, bufprotosource.Location, string),
+	previousOneof bufprotosource.Oneof,
+	oneof bufprotosource.Oneof,
+) error {
+	previousOneofDescriptor, err := previousOneof.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	oneofDescriptor, err := oneof.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	previousOneofRules, err := resolve.OneofRules(previousOneofDescriptor)
+	if err != nil {
+		return err
+	}
+	oneofRules, err := resolve.OneofRules(oneofDescriptor)
+	if err != nil {
+		return err
+	}
+	if oneofRules.GetRequired() && !previousOneofRules.GetRequired() {
+		addAnnotationFunc(
+			oneof.OptionExtensionLocation(validate.E_Oneof, requiredFieldNumberInOneofRules),
+			previousOneof.OptionExtensionLocation(validate.E_Oneof),
+			"added (buf.validate.oneof).required",
+		)
+	}
+	return nil
+}
+
+// tighteningChecker compares the rules of a descriptor against its previous rules.
+type tighteningChecker struct {
+	// add adds an annotation for the rule at the path within the rules message.
+	add func(path []int32, format string, args ...any)
+}
+
+func (c *tighteningChecker) checkFieldRules(
+	path []int32,
+	previousFieldRules *validate.FieldRules,
+	fieldRules *validate.FieldRules,
+) {
+	if fieldRules.GetRequired() && !previousFieldRules.GetRequired() {
+		requiredPath := appendPath(path, requiredFieldNumber)
+		c.add(requiredPath, "added %s", getFieldRuleNameForPath(requiredPath))
+	}
+	// The ignore values are ordered from the value that ignores the fewest values to the
+	// value that ignores the most values.
+	if fieldRules.GetIgnore() < previousFieldRules.GetIgnore() {
+		ignorePath := appendPath(path, ignoreFieldNumber)
+		c.add(
+			ignorePath,
+			"changed %s from %s to %s",
+			getFieldRuleNameForPath(ignorePath),
+			previousFieldRules.GetIgnore().String(),
+			fieldRules.GetIgnore().String(),
+		)
+	}
+	c.checkCEL(
+		appendPath(path, celFieldNumberInFieldRules),
+		previousFieldRules.GetCel(),
+		fieldRules.GetCel(),
+	)
+	previousFieldRulesMessage := previousFieldRules.ProtoReflect()
+	fieldRulesMessage := fieldRules.ProtoReflect()
+	typeRulesFieldDescriptor := fieldRulesMessage.WhichOneof(typeOneofDescriptor)
+	if typeRulesFieldDescriptor == nil {
+		return
+	}
+	typeRulesPath := appendPath(path, int32(typeRulesFieldDescriptor.Number()))
+	previousTypeRulesFieldDescriptor := previousFieldRulesMessage.WhichOneof(typeOneofDescriptor)
+	if previousTypeRulesFieldDescriptor == nil || previousTypeRulesFieldDescriptor.Number() != typeRulesFieldDescriptor.Number() {
+		c.add(typeRulesPath, "added %s", getFieldRuleNameForPath(typeRulesPath))
+		return
+	}
+	c.checkTypeRules(
+		typeRulesPath,
+		previousFieldRulesMessage.Get(previousTypeRulesFieldDescriptor).Message(),
+		fieldRulesMessage.Get(typeRulesFieldDescriptor).Message(),
+	)
+}
+
+// checkTypeRules compares type rules, such as StringRules, of the same type.
+func (c *tighteningChecker) checkTypeRules(
+	path []int32,
+	previousTypeRules protoreflect.Message,
+	typeRules protoreflect.Message,
+) {
+	oneofs := typeRules.Descriptor().Oneofs()
+	for i := range oneofs.Len() {
+		switch oneof := oneofs.Get(i); oneof.Name() {
+		case "less_than":
+			c.checkBound(path, oneof, previousTypeRules, typeRules, true)
+		case "greater_than":
+			c.checkBound(path, oneof, previousTypeRules, typeRules, false)
+		}
+	}
+	fields := typeRules.Descriptor().Fields()
+	for i := range fields.Len() {
+		fieldDescriptor := fields.Get(i)
+		if oneof := fieldDescriptor.ContainingOneof(); oneof != nil && (oneof.Name() == "less_than" || oneof.Name() == "greater_than") {
+			continue
+		}
+		c.checkTypeRulesField(path, fieldDescriptor, previousTypeRules, typeRules)
+	}
+	// Predefined rules are extensions of the type rules. They are only known fields
+	// if the image was reparsed with the extensions, so both cases are checked.
+	typeRules.Range(func(fieldDescriptor protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fieldDescriptor.IsExtension() {
+			c.checkTypeRulesField(path, fieldDescriptor, previousTypeRules, typeRules)
+		}
+		return true
+	})
+	if unknown := typeRules.GetUnknown(); len(unknown) > 0 && !bytes.Equal(unknown, previousTypeRules.GetUnknown()) {
+		c.add(path, "changed predefined rules of %s", getFieldRuleNameForPath(path))
+	}
+}
+
+func (c *tighteningChecker) checkTypeRulesField(
+	path []int32,
+	fieldDescriptor protoreflect.FieldDescriptor,
+	previousTypeRules protoreflect.Message,
+	typeRules protoreflect.Message,
+) {
+	if fieldDescriptor.Name() == exampleName {
+		// Examples are not rules.
+		return
+	}
+	fieldPath := appendPath(path, int32(fieldDescriptor.Number()))
+	ruleName := getFieldRuleNameForPath(fieldPath)
+	if fieldDescriptor.IsExtension() {
+		ruleName = fmt.Sprintf("%s.(%s)", getFieldRuleNameForPath(path), fieldDescriptor.FullName())
+	}
+	if fieldDescriptor.Kind() == protoreflect.BoolKind && !fieldDescriptor.IsList() {
+		// Unset bool rules have their default value, which may be true.
+		if typeRules.Get(fieldDescriptor).Bool() && !previousTypeRules.Get(fieldDescriptor).Bool() {
+			c.add(fieldPath, "added %s", ruleName)
+		}
+		return
+	}
+	if !typeRules.Has(fieldDescriptor) {
+		// Removing a rule only loosens the rules.
+		return
+	}
+	value := typeRules.Get(fieldDescriptor)
+	previousValue := previousTypeRules.Get(fieldDescriptor)
+	switch {
+	case fieldDescriptor.Message() != nil && fieldDescriptor.Message().FullName() == fieldRulesDescriptor.FullName():
+		// The items of repeated rules, and the keys and values of map rules.
+		previousFieldRules, _ := previousValue.Message().Interface().(*validate.FieldRules)
+		fieldRules, _ := value.Message().Interface().(*validate.FieldRules)
+		c.checkFieldRules(fieldPath, previousFieldRules, fieldRules)
+	case !previousTypeRules.Has(fieldDescriptor):
+		c.add(fieldPath, "added %s", ruleName)
+	case fieldDescriptor.IsList() && fieldDescriptor.Name() == "in":
+		if removedValues := listDifference(fieldDescriptor, previousValue.List(), value.List()); len(removedValues) > 0 {
+			c.add(fieldPath, "removed %s from %s", strings.Join(removedValues, ", "), ruleName)
+		}
+	case fieldDescriptor.IsList():
+		if addedValues := listDifference(fieldDescriptor, value.List(), previousValue.List()); len(addedValues) > 0 {
+			c.add(fieldPath, "added %s to %s", strings.Join(addedValues, ", "), ruleName)
+		}
+	case strings.HasPrefix(string(fieldDescriptor.Name()), "max_"):
+		if comparison, ok := compareRuleValues(fieldDescriptor, previousValue, value); !ok || comparison < 0 {
+			c.addChanged(fieldPath, ruleName, fieldDescriptor, previousValue, value)
+		}
+	case strings.HasPrefix(string(fieldDescriptor.Name()), "min_"):
+		if comparison, ok := compareRuleValues(fieldDescriptor, previousValue, value); !ok || comparison > 0 {
+			c.addChanged(fieldPath, ruleName, fieldDescriptor, previousValue, value)
+		}
+	case !previousValue.Equal(value):
+		c.addChanged(fieldPath, ruleName, fieldDescriptor, previousValue, value)
+	}
+}
+
+// checkBound compares the less_than or greater_than oneofs of numeric, duration,
+// and timestamp rules.
+func (c *tighteningChecker) checkBound(
+	path []int32,
+	oneof protoreflect.OneofDescriptor,
+	previousTypeRules protoreflect.Message,
+	typeRules protoreflect.Message,
+	isUpperBound bool,
+) {
+	fieldDescriptor := typeRules.WhichOneof(oneof)
+	if fieldDescriptor == nil {
+		return
+	}
+	fieldPath := appendPath(path, int32(fieldDescriptor.Number()))
+	ruleName := getFieldRuleNameForPath(fieldPath)
+	previousFieldDescriptor := previousTypeRules.WhichOneof(oneof)
+	if previousFieldDescriptor == nil {
+		c.add(fieldPath, "added %s", ruleName)
+		return
+	}
+	value := typeRules.Get(fieldDescriptor)
+	previousValue := previousTypeRules.Get(previousFieldDescriptor)
+	if fieldDescriptor.Kind() == protoreflect.BoolKind || previousFieldDescriptor.Kind() == protoreflect.BoolKind {
+		// lt_now and gt_now cannot be compared to fixed bounds.
+		if fieldDescriptor.Number() != previousFieldDescriptor.Number() {
+			c.add(
+				fieldPath,
+				"changed %s to %s",
+				getFieldRuleNameForPath(appendPath(path, int32(previousFieldDescriptor.Number()))),
+				ruleName,
+			)
+		}
+		return
+	}
+	// lte and gte include the bound, lt and gt do not.
+	isInclusive := fieldDescriptor.Name() == "lte" || fieldDescriptor.Name() == "gte"
+	wasInclusive := previousFieldDescriptor.Name() == "lte" || previousFieldDescriptor.Name() == "gte"
+	comparison, ok := compareRuleValues(fieldDescriptor, previousValue, value)
+	if ok {
+		if !isUpperBound {
+			comparison = -comparison
+		}
+		if comparison > 0 || (comparison == 0 && (isInclusive || !wasInclusive)) {
+			return
+		}
+	}
+	if fieldDescriptor.Number() == previousFieldDescriptor.Number() {
+		c.addChanged(fieldPath, ruleName, fieldDescriptor, previousValue, value)
+		return
+	}
+	c.add(
+		fieldPath,
+		"changed %s = %s to %s = %s",
+		getFieldRuleNameForPath(appendPath(path, int32(previousFieldDescriptor.Number()))),
+		formatRuleValue(previousFieldDescriptor, previousValue),
+		ruleName,
+		formatRuleValue(fieldDescriptor, value),
+	)
+}
+
+// checkCEL checks that no CEL rules were added or changed. CEL rules are matched
+// by ID, or by expression if they have no ID.
+func (c *tighteningChecker) checkCEL(
+	path []int32,
+	previousRules []*validate.Rule,
+	rules []*validate.Rule,
+) {
+	previousIDToExpression := make(map[string]string, len(previousRules))
+	previousExpressions := make(map[string]struct{}, len(previousRules))
+	for _, previousRule := range previousRules {
+		if previousRule.GetId() != "" {
+			previousIDToExpression[previousRule.GetId()] = previousRule.GetExpression()
+		}
+		previousExpressions[previousRule.GetExpression()] = struct{}{}
+	}
+	for index, rule := range rules {
+		rulePath := appendPath(path, int32(index))
+		if rule.GetId() == "" {
+			if _, ok := previousExpressions[rule.GetExpression()]; !ok {
+				c.add(rulePath, "added CEL expression %q", rule.GetExpression())
+			}
+			continue
+		}
+		previousExpression, ok := previousIDToExpression[rule.GetId()]
+		if !ok {
+			c.add(rulePath, "added CEL rule %q", rule.GetId())
+			continue
+		}
+		if previousExpression != rule.GetExpression() {
+			c.add(rulePath, "changed the expression of CEL rule %q", rule.GetId())
+		}
+	}
+}
+
+func (c *tighteningChecker) addChanged(
+	path []int32,
+	ruleName string,
+	fieldDescriptor protoreflect.FieldDescriptor,
+	previousValue protoreflect.Value,
+	value protoreflect.Value,
+) {
+	c.add(
+		path,
+		"changed %s from %s to %s",
+		ruleName,
+		formatRuleValue(fieldDescriptor, previousValue),
+		formatRuleValue(fieldDescriptor, value),
+	)
+}
+
+// compareRuleValues returns -1 if value is less than previousValue, 0 if they are equal,
+// and 1 if value is greater than previousValue.
+//
+// Returns false if the values cannot be ordered.
+func compareRuleValues(
+	fieldDescriptor protoreflect.FieldDescriptor,
+	previousValue protoreflect.Value,
+	value protoreflect.Value,
+) (int, bool) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return cmp.Compare(value.Int(), previousValue.Int()), true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return cmp.Compare(value.Uint(), previousValue.Uint()), true
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return cmp.Compare(value.Float(), previousValue.Float()), true
+	case protoreflect.MessageKind:
+		fullName := fieldDescriptor.Message().FullName()
+		if fullName != durationFullName && fullName != timestampFullName {
+			return 0, false
+		}
+		// Durations and timestamps both have seconds as field 1 and nanos as field 2.
+		fields := fieldDescriptor.Message().Fields()
+		secondsField, nanosField := fields.ByNumber(1), fields.ByNumber(2)
+		previousMessage, message := previousValue.Message(), value.Message()
+		if comparison := cmp.Compare(message.Get(secondsField).Int(), previousMessage.Get(secondsField).Int()); comparison != 0 {
+			return comparison, true
+		}
+		return cmp.Compare(message.Get(nanosField).Int(), previousMessage.Get(nanosField).Int()), true
+	default:
+		return 0, false
+	}
+}
+
+// listDifference returns the formatted values of list that are not in otherList.
+func listDifference(
+	fieldDescriptor protoreflect.FieldDescriptor,
+	list protoreflect.List,
+	otherList protoreflect.List,
+) []string {
+	otherValues := listValues(otherList)
+	var difference []string
+	for i := range list.Len() {
+		value := list.Get(i)
+		if !slices.ContainsFunc(
+			otherValues,
+			func(otherValue protoreflect.Value) bool { return otherValue.Equal(value) },
+		) {
+			difference = append(difference, formatRuleValue(fieldDescriptor, value))
+		}
+	}
+	return difference
+}
+
+func listValues(list protoreflect.List) []protoreflect.Value {
+	values := make([]protoreflect.Value, list.Len())
+	for i := range list.Len() {
+		values[i] = list.Get(i)
+	}
+	return values
+}
+
+func formatRuleValue(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(value.Bytes()))
+	case protoreflect.EnumKind:
+		if enumValue := fieldDescriptor.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		data, err := protojson.Marshal(value.Message().Interface())
+		if err != nil {
+			return value.String()
+		}
+		return string(data)
+	default:
+		return value.String()
+	}
+}
+
+// appendPath returns a new path with the field numbers appended, without
+// modifying path.
+func appendPath(path []int32, fieldNumbers ...int32) []int32 {
+	return append(slices.Clone(path), fieldNumbers...)
+}
@@ -143,7 +143,11 @@ func (c *client) Lint(
 	if err != nil {
 		return err
 	}
-	return annotationsToFilteredFileAnnotationSetOrError(config, image, annotations)
+	var fixFiles []descriptor.FileDescriptor
+	if lintOptions.fixes {
+		fixFiles = files
+	}
+	return annotationsToFilteredFileAnnotationSetOrError(config, image, annotations, fixFiles)
 }
 
 func (c *client) Breaking(
@@ -215,7 +219,7 @@ func (c *client) Breaking(
 	if err != nil {
 		return err
 	}
-	return annotationsToFilteredFileAnnotationSetOrError(config, image, annotations)
+	return annotationsToFilteredFileAnnotationSetOrError(config, image, annotations, nil)
 }
 
 func (c *client) ConfiguredRules(
@@ -436,10 +440,15 @@ func (c *client) getPlugins(ctx context.Context, pluginConfigs []bufconfig.Plugi
 	return plugins, nil
 }
 
+// annotationsToFilteredFileAnnotationSetOrError filters the annotations and returns them as a
+// bufanalysis.FileAnnotationSet, or nil if there are no annotations left.
+//
+// If fixFiles is not empty, the edits that fix the annotations are computed against these files.
 func annotationsToFilteredFileAnnotationSetOrError(
 	config *config,
 	image bufimage.Image,
 	annotations []*annotation,
+	fixFiles []descriptor.FileDescriptor,
 ) error {
 	if len(annotations) == 0 {
 		return nil
@@ -451,15 +460,23 @@ func annotationsToFilteredFileAnnotationSetOrError(
 	if len(annotations) == 0 {
 		return nil
 	}
+	pathToExternalPath := imageToPathToExternalPath(image)
+	var fixer *fixer
+	if len(fixFiles) > 0 {
+		fixer, err = newFixer(fixFiles, pathToExternalPath, config, annotations)
+		if err != nil {
+			return err
+		}
+	}
 	// Note that NewFileAnnotationSet does its own sorting and deduplication.
 	// The bufplugin SDK does this as well, but we don't need to worry about the sort
 	// order being different.
 	return bufanalysis.NewFileAnnotationSet(
 		annotationsToFileAnnotations(
-			imageToPathToExternalPath(
-				image,
-			),
+			pathToExternalPath,
 			annotations,
+			config.RuleIDToSeverity,
+			fixer,
 		)...,
 	)
 }
@@ -579,6 +596,7 @@ func checkCommentLineForCheckIgnore(
 type lintOptions struct {
 	pluginConfigs       []bufconfig.PluginConfig
 	relatedCheckConfigs []bufconfig.CheckConfig
+	fixes               bool
 }
 
 func newLintOptions() *lintOptions {
@@ -641,6 +659,12 @@ func (e *excludeImportsOption) applyToBreaking(breakingOptions *breakingOptions)
 	breakingOptions.excludeImports = true
 }
 
+type fixesOption struct{}
+
+func (f *fixesOption) applyToLint(lintOptions *lintOptions) {
+	lintOptions.fixes = true
+}
+
 type pluginConfigsOption struct {
 	pluginConfigs []bufconfig.PluginConfig
 }
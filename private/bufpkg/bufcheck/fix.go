@@ -0,0 +1,732 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"slices"
+	"strings"
+
+	"buf.build/go/bufplugin/descriptor"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/internal/bufcheckopt"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/protoversion"
+	"github.com/bufbuild/buf/private/pkg/standard/xstrings"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	enumValuePrefixRuleID         = "ENUM_VALUE_PREFIX"
+	enumValueUpperSnakeCaseRuleID = "ENUM_VALUE_UPPER_SNAKE_CASE"
+	enumZeroValueSuffixRuleID     = "ENUM_ZERO_VALUE_SUFFIX"
+	fieldLowerSnakeCaseRuleID     = "FIELD_LOWER_SNAKE_CASE"
+	importUsedRuleID              = "IMPORT_USED"
+	messagePascalCaseRuleID       = "MESSAGE_PASCAL_CASE"
+	packageVersionSuffixRuleID    = "PACKAGE_VERSION_SUFFIX"
+	syntaxSpecifiedRuleID         = "SYNTAX_SPECIFIED"
+)
+
+// fixer computes the edits that mechanically fix the annotations of the builtin lint rules.
+//
+// A fix is only computed if it is safe: the files must still compile once the edits are
+// applied, and all of the files that need to be edited must be non-import files. Files
+// that are not part of the image, such as files of other modules that import the image,
+// are not taken into account.
+type fixer struct {
+	files               []descriptor.FileDescriptor
+	pathToExternalPath  map[string]string
+	enumZeroValueSuffix string
+	// enumValueNameToRuleIDs are the IDs of the enum value rules that each enum value violates.
+	// The new name of an enum value has to satisfy all of them at once.
+	enumValueNameToRuleIDs map[protoreflect.FullName]map[string]struct{}
+	// fullNames are the full names of all descriptors in the files, along with the new full
+	// names of the descriptors that have been renamed by fixes.
+	fullNames map[protoreflect.FullName]struct{}
+	// optionTypeNames are the full names of the messages and enums that may be used in the
+	// values of options. Their fields and enum values are not renamed, as options are not edited.
+	optionTypeNames map[protoreflect.FullName]struct{}
+	// keyToEdits caches fixes that are shared by multiple annotations.
+	keyToEdits map[string][]bufanalysis.Edit
+}
+
+func newFixer(
+	files []descriptor.FileDescriptor,
+	pathToExternalPath map[string]string,
+	config *config,
+	annotations []*annotation,
+) (*fixer, error) {
+	enumZeroValueSuffix, err := bufcheckopt.GetEnumZeroValueSuffix(config.DefaultOptions)
+	if err != nil {
+		return nil, err
+	}
+	fixer := &fixer{
+		files:                  files,
+		pathToExternalPath:     pathToExternalPath,
+		enumZeroValueSuffix:    enumZeroValueSuffix,
+		enumValueNameToRuleIDs: make(map[protoreflect.FullName]map[string]struct{}),
+		fullNames:              make(map[protoreflect.FullName]struct{}),
+		optionTypeNames:        make(map[protoreflect.FullName]struct{}),
+		keyToEdits:             make(map[string][]bufanalysis.Edit),
+	}
+	for _, annotation := range annotations {
+		switch annotation.RuleID() {
+		case enumValuePrefixRuleID, enumValueUpperSnakeCaseRuleID, enumZeroValueSuffixRuleID:
+		default:
+			continue
+		}
+		fileLocation := annotation.FileLocation()
+		if annotation.PluginName() != "" || fileLocation == nil {
+			continue
+		}
+		enumValue, ok := descriptorForSourcePath(
+			fileLocation.FileDescriptor().ProtoreflectFileDescriptor(),
+			fileLocation.SourcePath(),
+		).(protoreflect.EnumValueDescriptor)
+		if !ok {
+			continue
+		}
+		ruleIDs, ok := fixer.enumValueNameToRuleIDs[enumValue.FullName()]
+		if !ok {
+			ruleIDs = make(map[string]struct{})
+			fixer.enumValueNameToRuleIDs[enumValue.FullName()] = ruleIDs
+		}
+		ruleIDs[annotation.RuleID()] = struct{}{}
+	}
+	var optionTypes []protoreflect.MessageDescriptor
+	addOptionType := func(field protoreflect.FieldDescriptor) {
+		var fullName protoreflect.FullName
+		if message := field.Message(); message != nil {
+			fullName = message.FullName()
+			if _, ok := fixer.optionTypeNames[fullName]; !ok {
+				optionTypes = append(optionTypes, message)
+			}
+		} else if enum := field.Enum(); enum != nil {
+			fullName = enum.FullName()
+		} else {
+			return
+		}
+		fixer.optionTypeNames[fullName] = struct{}{}
+	}
+	for _, file := range files {
+		forEachDescriptor(file.ProtoreflectFileDescriptor(), func(descriptor protoreflect.Descriptor) {
+			fixer.fullNames[descriptor.FullName()] = struct{}{}
+			// Any extension may be used in an option, so we treat all of them as options.
+			if field, ok := descriptor.(protoreflect.FieldDescriptor); ok && field.IsExtension() {
+				addOptionType(field)
+			}
+		})
+	}
+	for len(optionTypes) > 0 {
+		fields := optionTypes[0].Fields()
+		optionTypes = optionTypes[1:]
+		for i := range fields.Len() {
+			addOptionType(fields.Get(i))
+		}
+	}
+	return fixer, nil
+}
+
+// edits returns the edits that fix the annotation, or nil if the annotation has no fix.
+func (f *fixer) edits(annotation *annotation) []bufanalysis.Edit {
+	if annotation.PluginName() != "" {
+		return nil
+	}
+	fileLocation := annotation.FileLocation()
+	if fileLocation == nil || fileLocation.FileDescriptor().IsImport() {
+		return nil
+	}
+	file := fileLocation.FileDescriptor().ProtoreflectFileDescriptor()
+	switch annotation.RuleID() {
+	case enumValuePrefixRuleID, enumValueUpperSnakeCaseRuleID, enumZeroValueSuffixRuleID:
+		enumValue, ok := descriptorForSourcePath(file, fileLocation.SourcePath()).(protoreflect.EnumValueDescriptor)
+		if !ok {
+			return nil
+		}
+		return f.cachedEdits(string(enumValue.FullName()), func() []bufanalysis.Edit {
+			return f.enumValueEdits(enumValue)
+		})
+	case fieldLowerSnakeCaseRuleID:
+		field, ok := descriptorForSourcePath(file, fileLocation.SourcePath()).(protoreflect.FieldDescriptor)
+		if !ok {
+			return nil
+		}
+		return f.cachedEdits(string(field.FullName()), func() []bufanalysis.Edit {
+			return f.fieldEdits(field)
+		})
+	case messagePascalCaseRuleID:
+		message, ok := descriptorForSourcePath(file, fileLocation.SourcePath()).(protoreflect.MessageDescriptor)
+		if !ok {
+			return nil
+		}
+		return f.cachedEdits(string(message.FullName()), func() []bufanalysis.Edit {
+			return f.messageEdits(message)
+		})
+	case importUsedRuleID:
+		// The location of the annotation is the entire import statement.
+		return []bufanalysis.Edit{
+			f.newEdit(
+				file,
+				fileLocation.StartLine(),
+				fileLocation.StartColumn(),
+				fileLocation.EndLine(),
+				fileLocation.EndColumn(),
+				"",
+			),
+		}
+	case syntaxSpecifiedRuleID:
+		return f.syntaxSpecifiedEdits(file)
+	case packageVersionSuffixRuleID:
+		return f.cachedEdits("package "+string(file.Package()), func() []bufanalysis.Edit {
+			return f.packageEdits(file)
+		})
+	default:
+		return nil
+	}
+}
+
+func (f *fixer) cachedEdits(key string, getEdits func() []bufanalysis.Edit) []bufanalysis.Edit {
+	edits, ok := f.keyToEdits[key]
+	if !ok {
+		edits = getEdits()
+		f.keyToEdits[key] = edits
+	}
+	return edits
+}
+
+func (f *fixer) enumValueEdits(enumValue protoreflect.EnumValueDescriptor) []bufanalysis.Edit {
+	enum, ok := enumValue.Parent().(protoreflect.EnumDescriptor)
+	if !ok || f.isOptionType(enum) {
+		return nil
+	}
+	ruleIDs := f.enumValueNameToRuleIDs[enumValue.FullName()]
+	enumPrefix := xstrings.ToUpperSnakeCase(string(enum.Name()))
+	newName := string(enumValue.Name())
+	if _, ok := ruleIDs[enumValueUpperSnakeCaseRuleID]; ok {
+		newName = xstrings.ToUpperSnakeCase(newName)
+	}
+	if _, ok := ruleIDs[enumValuePrefixRuleID]; ok && !strings.HasPrefix(newName, enumPrefix+"_") {
+		newName = enumPrefix + "_" + newName
+	}
+	if _, ok := ruleIDs[enumZeroValueSuffixRuleID]; ok && !strings.HasSuffix(newName, f.enumZeroValueSuffix) {
+		newName = enumPrefix + f.enumZeroValueSuffix
+	}
+	// Enum values are scoped to the parent of their enum.
+	if !f.claimNewName(enumValue, enum.Parent().FullName(), newName) {
+		return nil
+	}
+	nameEdit, ok := f.nameEdit(enumValue, newName)
+	if !ok {
+		return nil
+	}
+	edits := []bufanalysis.Edit{nameEdit}
+	// The enum value may be the default value of fields.
+	oldName := string(enumValue.Name())
+	for _, file := range f.files {
+		protoreflectFile := file.ProtoreflectFileDescriptor()
+		safe := true
+		forEachField(protoreflectFile, func(field protoreflect.FieldDescriptor, sourcePath protoreflect.SourcePath) {
+			if !field.HasDefault() || field.Enum() == nil || field.DefaultEnumValue().FullName() != enumValue.FullName() {
+				return
+			}
+			if file.IsImport() {
+				safe = false
+				return
+			}
+			// The location of the default value is the entire "default = VALUE" option.
+			location, ok := sourceLocationForPath(protoreflectFile, appendSourcePath(sourcePath, defaultValueTypeTag))
+			if !ok || location.StartLine != location.EndLine || location.EndColumn-location.StartColumn < len(oldName) {
+				safe = false
+				return
+			}
+			edits = append(
+				edits,
+				f.newEdit(
+					protoreflectFile,
+					location.EndLine,
+					location.EndColumn-len(oldName),
+					location.EndLine,
+					location.EndColumn,
+					newName,
+				),
+			)
+		})
+		if !safe {
+			return nil
+		}
+	}
+	return edits
+}
+
+func (f *fixer) fieldEdits(field protoreflect.FieldDescriptor) []bufanalysis.Edit {
+	// Extensions may be referenced by options, and the names of group fields are derived from
+	// their message, neither of which we edit.
+	if field.IsExtension() || field.Kind() == protoreflect.GroupKind {
+		return nil
+	}
+	message := field.ContainingMessage()
+	if message.IsMapEntry() || f.isOptionType(message) {
+		return nil
+	}
+	newName := xstrings.ToLowerSnakeCase(string(field.Name()))
+	if !f.claimNewName(field, message.FullName(), newName) {
+		return nil
+	}
+	nameEdit, ok := f.nameEdit(field, newName)
+	if !ok {
+		return nil
+	}
+	return []bufanalysis.Edit{nameEdit}
+}
+
+func (f *fixer) messageEdits(message protoreflect.MessageDescriptor) []bufanalysis.Edit {
+	// Extensions declared within the message may be referenced by options, which we do not edit.
+	if message.IsMapEntry() || containsExtensions(message) {
+		return nil
+	}
+	newName := xstrings.ToPascalCase(string(message.Name()))
+	if !f.claimNewName(message, message.Parent().FullName(), newName) {
+		return nil
+	}
+	nameEdit, ok := f.nameEdit(message, newName)
+	if !ok {
+		return nil
+	}
+	edits := []bufanalysis.Edit{nameEdit}
+	// References to the message and to the types nested within it need to be updated.
+	componentIndex := strings.Count(string(message.FullName()), ".")
+	for _, file := range f.files {
+		protoreflectFile := file.ProtoreflectFileDescriptor()
+		safe := true
+		forEachTypeReference(protoreflectFile, func(target protoreflect.Descriptor, fromGroup bool, sourcePath protoreflect.SourcePath) {
+			if target.FullName() != message.FullName() && !strings.HasPrefix(string(target.FullName()), string(message.FullName())+".") {
+				return
+			}
+			if file.IsImport() || (fromGroup && target.FullName() == message.FullName()) {
+				safe = false
+				return
+			}
+			location, ok := sourceLocationForPath(protoreflectFile, sourcePath)
+			if !ok {
+				safe = false
+				return
+			}
+			startColumn, endColumn, included, ok := referenceComponentColumns(location, target.FullName(), componentIndex)
+			if !ok {
+				safe = false
+				return
+			}
+			if included {
+				edits = append(edits, f.newEdit(protoreflectFile, location.StartLine, startColumn, location.StartLine, endColumn, newName))
+			}
+		})
+		if !safe {
+			return nil
+		}
+	}
+	return edits
+}
+
+// packageEdits returns the edits that add a version suffix to the package of the file.
+//
+// This is only done if the file is in a directory that matches its package with a version
+// suffix, for example for a file in foo/bar/v1 with package foo.bar. All files in the package
+// need to be in the same directory, which is the case if PACKAGE_DIRECTORY_MATCH is satisfied,
+// so that the entire package is moved.
+func (f *fixer) packageEdits(file protoreflect.FileDescriptor) []bufanalysis.Edit {
+	pkg := string(file.Package())
+	dirPath := normalpath.Dir(file.Path())
+	newPkg := strings.ReplaceAll(dirPath, "/", ".")
+	version, ok := strings.CutPrefix(newPkg, pkg+".")
+	if pkg == "" || !ok || strings.Contains(version, ".") {
+		return nil
+	}
+	if _, ok := protoversion.NewPackageVersionForComponent(version); !ok {
+		return nil
+	}
+	if _, ok := f.fullNames[protoreflect.FullName(newPkg)]; ok {
+		return nil
+	}
+	var edits []bufanalysis.Edit
+	for _, packageFile := range f.files {
+		protoreflectFile := packageFile.ProtoreflectFileDescriptor()
+		if string(protoreflectFile.Package()) != pkg {
+			continue
+		}
+		// Extensions may be referenced by options, which we do not edit.
+		if packageFile.IsImport() || normalpath.Dir(protoreflectFile.Path()) != dirPath || containsExtensions(protoreflectFile) {
+			return nil
+		}
+		for _, descriptor := range packageScopedDescriptors(protoreflectFile) {
+			if !f.claimNewName(descriptor, protoreflect.FullName(newPkg), string(descriptor.Name())) {
+				return nil
+			}
+		}
+		// The location of the package is the entire package statement.
+		location, ok := sourceLocationForPath(protoreflectFile, protoreflect.SourcePath{packageTypeTag})
+		if !ok {
+			return nil
+		}
+		edits = append(
+			edits,
+			f.newEdit(
+				protoreflectFile,
+				location.StartLine,
+				location.StartColumn,
+				location.EndLine,
+				location.EndColumn,
+				"package "+newPkg+";",
+			),
+		)
+	}
+	// References to the types in the package need to be updated if they include the package.
+	componentIndex := strings.Count(pkg, ".")
+	lastComponent := pkg[strings.LastIndex(pkg, ".")+1:]
+	for _, file := range f.files {
+		protoreflectFile := file.ProtoreflectFileDescriptor()
+		safe := true
+		forEachTypeReference(protoreflectFile, func(target protoreflect.Descriptor, _ bool, sourcePath protoreflect.SourcePath) {
+			if string(target.ParentFile().Package()) != pkg {
+				return
+			}
+			if file.IsImport() {
+				safe = false
+				return
+			}
+			location, ok := sourceLocationForPath(protoreflectFile, sourcePath)
+			if !ok {
+				safe = false
+				return
+			}
+			startColumn, endColumn, included, ok := referenceComponentColumns(location, target.FullName(), componentIndex)
+			if !ok || (!included && string(protoreflectFile.Package()) != pkg) {
+				// References from other packages that are relative to a parent package cannot
+				// be updated by editing the package component.
+				safe = false
+				return
+			}
+			if included {
+				edits = append(
+					edits,
+					f.newEdit(protoreflectFile, location.StartLine, startColumn, location.StartLine, endColumn, lastComponent+"."+version),
+				)
+			}
+		})
+		if !safe {
+			return nil
+		}
+	}
+	return edits
+}
+
+// syntaxSpecifiedEdits returns the edits that explicitly specify the proto2 syntax, which is the
+// syntax of files that do not specify one.
+func (f *fixer) syntaxSpecifiedEdits(file protoreflect.FileDescriptor) []bufanalysis.Edit {
+	// The syntax is added before the first declaration of the file, unless that declaration
+	// has leading comments, in which case it is added at the top of the file so that the
+	// comments stay attached to the declaration.
+	var line, column int
+	hasLeadingComments := false
+	first := true
+	locations := file.SourceLocations()
+	for i := range locations.Len() {
+		location := locations.Get(i)
+		if len(location.Path) == 0 {
+			continue
+		}
+		switch {
+		case first || location.StartLine < line || (location.StartLine == line && location.StartColumn < column):
+			line, column = location.StartLine, location.StartColumn
+			hasLeadingComments = location.LeadingComments != ""
+			first = false
+		case location.StartLine == line && location.StartColumn == column:
+			hasLeadingComments = hasLeadingComments || location.LeadingComments != ""
+		}
+	}
+	if first || hasLeadingComments {
+		line, column = 0, 0
+	}
+	return []bufanalysis.Edit{
+		f.newEdit(file, line, column, line, column, "syntax = \"proto2\";\n\n"),
+	}
+}
+
+// claimNewName claims the new name for the descriptor in the given scope, returning false if
+// the new name is not a valid name, or if there is already a descriptor with this name.
+func (f *fixer) claimNewName(
+	descriptor protoreflect.Descriptor,
+	scope protoreflect.FullName,
+	newName string,
+) bool {
+	if !protoreflect.Name(newName).IsValid() {
+		return false
+	}
+	newFullName := scope.Append(protoreflect.Name(newName))
+	if newFullName == descriptor.FullName() {
+		return false
+	}
+	if _, ok := f.fullNames[newFullName]; ok {
+		return false
+	}
+	f.fullNames[newFullName] = struct{}{}
+	return true
+}
+
+// nameEdit returns the edit that replaces the name in the declaration of the descriptor.
+func (f *fixer) nameEdit(descriptor protoreflect.Descriptor, newName string) (bufanalysis.Edit, bool) {
+	file := descriptor.ParentFile()
+	descriptorLocation := file.SourceLocations().ByDescriptor(descriptor)
+	if descriptorLocation.Path == nil {
+		return nil, false
+	}
+	location, ok := sourceLocationForPath(file, appendSourcePath(descriptorLocation.Path, nameTypeTag))
+	if !ok || location.StartLine != location.EndLine || location.EndColumn-location.StartColumn != len(descriptor.Name()) {
+		return nil, false
+	}
+	return f.newEdit(file, location.StartLine, location.StartColumn, location.EndLine, location.EndColumn, newName), true
+}
+
+func (f *fixer) isOptionType(descriptor protoreflect.Descriptor) bool {
+	_, ok := f.optionTypeNames[descriptor.FullName()]
+	return ok
+}
+
+// newEdit returns a new bufanalysis.Edit for the given 0-based lines and columns.
+func (f *fixer) newEdit(
+	file protoreflect.FileDescriptor,
+	startLine int,
+	startColumn int,
+	endLine int,
+	endColumn int,
+	newText string,
+) bufanalysis.Edit {
+	path := file.Path()
+	return bufanalysis.NewEdit(
+		newFileInfo(path, f.pathToExternalPath[path]),
+		startLine+1,
+		startColumn+1,
+		endLine+1,
+		endColumn+1,
+		newText,
+	)
+}
+
+// referenceComponentColumns returns the columns of the component at the given index of the full
+// name within a reference to the full name at the location.
+//
+// A reference is either a suffix of the components of the full name, or the full name with a
+// leading dot, and which one it is is inferred from the length of the reference. Returns false
+// if the reference is not one of these, and included is false if the reference does not include
+// the component.
+func referenceComponentColumns(
+	location protoreflect.SourceLocation,
+	fullName protoreflect.FullName,
+	componentIndex int,
+) (startColumn int, endColumn int, included bool, ok bool) {
+	if location.StartLine != location.EndLine {
+		return 0, 0, false, false
+	}
+	width := location.EndColumn - location.StartColumn
+	components := strings.Split(string(fullName), ".")
+	firstIndex := -1
+	startColumn = location.StartColumn
+	if width == len(fullName)+1 {
+		firstIndex = 0
+		startColumn++
+	} else {
+		length := -1
+		for i := len(components) - 1; i >= 0; i-- {
+			length += len(components[i]) + 1
+			if length == width {
+				firstIndex = i
+				break
+			}
+		}
+	}
+	if firstIndex < 0 {
+		return 0, 0, false, false
+	}
+	if componentIndex < firstIndex {
+		return 0, 0, false, true
+	}
+	for _, component := range components[firstIndex:componentIndex] {
+		startColumn += len(component) + 1
+	}
+	return startColumn, startColumn + len(components[componentIndex]), true, true
+}
+
+// forEachDescriptor calls f for each descriptor declared in the file.
+func forEachDescriptor(file protoreflect.FileDescriptor, f func(protoreflect.Descriptor)) {
+	forEachDescriptorInScope(file, f)
+	services := file.Services()
+	for i := range services.Len() {
+		service := services.Get(i)
+		f(service)
+		methods := service.Methods()
+		for j := range methods.Len() {
+			f(methods.Get(j))
+		}
+	}
+}
+
+func forEachDescriptorInScope(
+	scope interface {
+		Messages() protoreflect.MessageDescriptors
+		Enums() protoreflect.EnumDescriptors
+		Extensions() protoreflect.ExtensionDescriptors
+	},
+	f func(protoreflect.Descriptor),
+) {
+	messages := scope.Messages()
+	for i := range messages.Len() {
+		message := messages.Get(i)
+		f(message)
+		fields := message.Fields()
+		for j := range fields.Len() {
+			f(fields.Get(j))
+		}
+		oneofs := message.Oneofs()
+		for j := range oneofs.Len() {
+			f(oneofs.Get(j))
+		}
+		forEachDescriptorInScope(message, f)
+	}
+	enums := scope.Enums()
+	for i := range enums.Len() {
+		enum := enums.Get(i)
+		f(enum)
+		values := enum.Values()
+		for j := range values.Len() {
+			f(values.Get(j))
+		}
+	}
+	extensions := scope.Extensions()
+	for i := range extensions.Len() {
+		f(extensions.Get(i))
+	}
+}
+
+// forEachField calls f for each field and extension declared in the file, along with its source path.
+func forEachField(file protoreflect.FileDescriptor, f func(protoreflect.FieldDescriptor, protoreflect.SourcePath)) {
+	extensions := file.Extensions()
+	for i := range extensions.Len() {
+		f(extensions.Get(i), protoreflect.SourcePath{extensionsTypeTag, int32(i)})
+	}
+	messages := file.Messages()
+	for i := range messages.Len() {
+		forEachFieldInMessage(messages.Get(i), protoreflect.SourcePath{messagesTypeTag, int32(i)}, f)
+	}
+}
+
+func forEachFieldInMessage(
+	message protoreflect.MessageDescriptor,
+	sourcePath protoreflect.SourcePath,
+	f func(protoreflect.FieldDescriptor, protoreflect.SourcePath),
+) {
+	fields := message.Fields()
+	for i := range fields.Len() {
+		f(fields.Get(i), appendSourcePath(sourcePath, messageFieldsTypeTag, int32(i)))
+	}
+	extensions := message.Extensions()
+	for i := range extensions.Len() {
+		f(extensions.Get(i), appendSourcePath(sourcePath, nestedExtensionsTypeTag, int32(i)))
+	}
+	messages := message.Messages()
+	for i := range messages.Len() {
+		forEachFieldInMessage(messages.Get(i), appendSourcePath(sourcePath, nestedMessagesTypeTag, int32(i)), f)
+	}
+}
+
+// forEachTypeReference calls f for each reference to a message or an enum in the file, along with
+// the source path of the reference. References to map entries are skipped, as they are implicit.
+func forEachTypeReference(
+	file protoreflect.FileDescriptor,
+	f func(target protoreflect.Descriptor, fromGroup bool, sourcePath protoreflect.SourcePath),
+) {
+	forEachField(file, func(field protoreflect.FieldDescriptor, sourcePath protoreflect.SourcePath) {
+		if field.IsExtension() {
+			f(field.ContainingMessage(), false, appendSourcePath(sourcePath, extendeeTypeTag))
+		}
+		if message := field.Message(); message != nil && !message.IsMapEntry() {
+			f(message, field.Kind() == protoreflect.GroupKind, appendSourcePath(sourcePath, typeNameTypeTag))
+		} else if enum := field.Enum(); enum != nil {
+			f(enum, false, appendSourcePath(sourcePath, typeNameTypeTag))
+		}
+	})
+	services := file.Services()
+	for i := range services.Len() {
+		methods := services.Get(i).Methods()
+		for j := range methods.Len() {
+			method := methods.Get(j)
+			sourcePath := protoreflect.SourcePath{servicesTypeTag, int32(i), methodsTypeTag, int32(j)}
+			f(method.Input(), false, appendSourcePath(sourcePath, inputTypeTypeTag))
+			f(method.Output(), false, appendSourcePath(sourcePath, outputTypeTypeTag))
+		}
+	}
+}
+
+// packageScopedDescriptors returns the descriptors of the file that are scoped to its package,
+// which are its top-level descriptors along with the values of its top-level enums.
+func packageScopedDescriptors(file protoreflect.FileDescriptor) []protoreflect.Descriptor {
+	var descriptors []protoreflect.Descriptor
+	messages := file.Messages()
+	for i := range messages.Len() {
+		descriptors = append(descriptors, messages.Get(i))
+	}
+	enums := file.Enums()
+	for i := range enums.Len() {
+		enum := enums.Get(i)
+		descriptors = append(descriptors, enum)
+		values := enum.Values()
+		for j := range values.Len() {
+			descriptors = append(descriptors, values.Get(j))
+		}
+	}
+	services := file.Services()
+	for i := range services.Len() {
+		descriptors = append(descriptors, services.Get(i))
+	}
+	return descriptors
+}
+
+// containsExtensions returns true if any extensions are declared within the scope.
+func containsExtensions(
+	scope interface {
+		Messages() protoreflect.MessageDescriptors
+		Extensions() protoreflect.ExtensionDescriptors
+	},
+) bool {
+	if scope.Extensions().Len() > 0 {
+		return true
+	}
+	messages := scope.Messages()
+	for i := range messages.Len() {
+		if containsExtensions(messages.Get(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sourceLocationForPath(file protoreflect.FileDescriptor, sourcePath protoreflect.SourcePath) (protoreflect.SourceLocation, bool) {
+	location := file.SourceLocations().ByPath(sourcePath)
+	if location.Path == nil {
+		return protoreflect.SourceLocation{}, false
+	}
+	return location, true
+}
+
+func appendSourcePath(sourcePath protoreflect.SourcePath, elements ...int32) protoreflect.SourcePath {
+	return slices.Concat(sourcePath, elements)
+}
@@ -41,6 +41,34 @@ import (
 //      or
 //    buf lint --error-format=json | jq -r '"bufanalysistesting.NewFileAnnotation(t, \"\(.path)\", \(.start_line|tostring), \(.start_column|tostring), \(.end_line|tostring), \(.end_column|tostring), \"\(.type)\"),"'
 
+func TestRunAIP(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"aip",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 20, 3, 20, 74, "AIP_RESOURCE_ANNOTATION"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 20, 35, 20, 72, "AIP_RESOURCE_ANNOTATION"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 3, 21, 8, "AIP_RESOURCE_NAME_FIELD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 24, 1, 33, 2, "AIP_RESOURCE_NAME_FIELD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 26, 5, 26, 19, "AIP_RESOURCE_ANNOTATION"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 30, 5, 30, 43, "AIP_FIELD_BEHAVIOR_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 42, 5, 42, 45, "AIP_FIELD_BEHAVIOR_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 53, 16, 53, 33, "AIP_STANDARD_METHOD_REQUEST_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 53, 44, 53, 60, "AIP_STANDARD_METHOD_RESPONSE_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 57, 48, 57, 67, "AIP_STANDARD_METHOD_RESPONSE_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 90, 1, 92, 2, "AIP_STANDARD_METHOD_REQUEST_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 98, 1, 101, 2, "AIP_LIST_PAGINATION"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 99, 3, 99, 21, "AIP_FIELD_BEHAVIOR_REQUIRED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 100, 3, 100, 9, "AIP_LIST_PAGINATION"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 103, 1, 105, 2, "AIP_LIST_PAGINATION"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 107, 1, 110, 2, "AIP_STANDARD_METHOD_REQUEST_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 108, 3, 108, 21, "AIP_FIELD_BEHAVIOR_REQUIRED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 112, 1, 114, 2, "AIP_UPDATE_MASK"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 113, 3, 113, 19, "AIP_FIELD_BEHAVIOR_REQUIRED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 117, 3, 117, 19, "AIP_FIELD_BEHAVIOR_REQUIRED"),
+	)
+}
+
 func TestRunComments(t *testing.T) {
 	t.Parallel()
 	testLint(
@@ -33,10 +33,9 @@ import (
 )
 
 const (
-	fieldLowerSnakeCaseRuleID = "FIELD_LOWER_SNAKE_CASE"
-	timestampSuffixRuleID     = "TIMESTAMP_SUFFIX"
-	timestampSuffixOptionKey  = "timestamp_suffix"
-	defaultTimestampSuffix    = "_time"
+	timestampSuffixRuleID    = "TIMESTAMP_SUFFIX"
+	timestampSuffixOptionKey = "timestamp_suffix"
+	defaultTimestampSuffix   = "_time"
 )
 
 var (
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/standard/xslices"
@@ -40,6 +41,7 @@ func rulesConfigForCheckConfig(
 		checkConfig.ExceptIDsAndCategories(),
 		checkConfig.IgnorePaths(),
 		checkConfig.IgnoreIDOrCategoryToPaths(),
+		checkConfig.IDOrCategoryToSeverity(),
 		allRules,
 		allCategories,
 		ruleType,
@@ -75,6 +77,12 @@ type rulesConfig struct {
 	// Will only contain non-deprecated RuleIDs.
 	// This will only contain RuleIDs of the given RuleType.
 	IgnoreRuleIDToRootPaths map[string]map[string]struct{}
+	// RuleIDToSeverity contains the severities of the RuleIDs that do not have
+	// bufanalysis.SeverityError.
+	//
+	// Will only contain non-deprecated RuleIDs.
+	// This will only contain RuleIDs of the given RuleType.
+	RuleIDToSeverity map[string]bufanalysis.Severity
 	// ReferencedDeprecatedRuleIDToReplacementIDs contains a map from a Rule ID
 	// that was used in the configuration, to a map of the IDs that
 	// replace this Rule ID.
@@ -117,6 +125,8 @@ func newRulesConfig(
 	ignoreRootPaths []string,
 	// May contain deprecated IDs.
 	ignoreRuleIDOrCategoryIDToRootPaths map[string][]string,
+	// May contain deprecated IDs.
+	ruleIDOrCategoryIDToSeverity map[string]bufanalysis.Severity,
 	// Rules and Categories are guaranteed to be unique by ID at this point,
 	// including across each other.
 	allRules []Rule,
@@ -135,6 +145,7 @@ func newRulesConfig(
 			RuleIDs:                 make([]string, 0),
 			IgnoreRootPaths:         make(map[string]struct{}),
 			IgnoreRuleIDToRootPaths: make(map[string]map[string]struct{}),
+			RuleIDToSeverity:        make(map[string]bufanalysis.Severity),
 			ReferencedDeprecatedRuleIDToReplacementIDs:     make(map[string]map[string]struct{}),
 			ReferencedDeprecatedCategoryIDToReplacementIDs: make(map[string]map[string]struct{}),
 			UnusedPluginNameToRuleIDs:                      make(map[string][]string),
@@ -173,6 +184,7 @@ func newRulesConfig(
 		useRuleIDsAndCategoryIDs,
 		exceptRuleIDsAndCategoryIDs,
 		xslices.MapKeysToSlice(ignoreRuleIDOrCategoryIDToRootPathMap),
+		xslices.MapKeysToSlice(ruleIDOrCategoryIDToSeverity),
 	} {
 		for _, id := range ids {
 			replacementRuleIDs, ok := deprecatedRuleIDToReplacementRuleIDs[id]
@@ -240,6 +252,17 @@ func newRulesConfig(
 	if err != nil {
 		return nil, err
 	}
+	// Severities are transformed and undeprecated at once, as a severity for a rule
+	// takes precedence over a severity for a category.
+	ruleIDToSeverity, err := transformRuleOrCategoryIDToSeverityToUndeprecatedRuleIDs(
+		ruleIDOrCategoryIDToSeverity,
+		ruleIDToCategoryIDs,
+		categoryIDToRuleIDs,
+		deprecatedRuleIDToReplacementRuleIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	// Replace deprecated rules.
 	useRuleIDs = transformRuleIDsToUndeprecated(
@@ -336,6 +359,7 @@ func newRulesConfig(
 		RuleIDs:                 xslices.Map(resultRules, Rule.ID),
 		IgnoreRootPaths:         xslices.ToStructMap(ignoreRootPaths),
 		IgnoreRuleIDToRootPaths: ignoreRuleIDToRootPathMap,
+		RuleIDToSeverity:        ruleIDToSeverity,
 		ReferencedDeprecatedRuleIDToReplacementIDs:     referencedDeprecatedRuleIDToReplacementIDs,
 		ReferencedDeprecatedCategoryIDToReplacementIDs: referencedDeprecatedCategoryIDToReplacementIDs,
 		UnusedPluginNameToRuleIDs:                      unusedPluginNameToRuleIDs,
@@ -540,6 +564,54 @@ func transformRuleOrCategoryIDToIgnoreRootPathsToRuleIDs(
 	return ruleIDToIgnoreRootPaths, nil
 }
 
+// transformRuleOrCategoryIDToSeverityToUndeprecatedRuleIDs transforms the severities of rules
+// and categories to severities of undeprecated rules.
+//
+// A severity for a rule takes precedence over a severity for a category. If a rule is in multiple
+// categories with a severity, the most severe one is used.
+func transformRuleOrCategoryIDToSeverityToUndeprecatedRuleIDs(
+	ruleOrCategoryIDToSeverity map[string]bufanalysis.Severity,
+	ruleIDToCategoryIDs map[string][]string,
+	categoryIDToRuleIDs map[string][]string,
+	deprecatedRuleIDToReplacementIDs map[string][]string,
+) (map[string]bufanalysis.Severity, error) {
+	ruleIDToSeverity := make(map[string]bufanalysis.Severity)
+	// Sorted so that errors are deterministic.
+	ruleOrCategoryIDs := xslices.MapKeysToSortedSlice(ruleOrCategoryIDToSeverity)
+	var ruleIDs []string
+	for _, ruleOrCategoryID := range ruleOrCategoryIDs {
+		if ruleOrCategoryID == "" {
+			continue
+		}
+		if _, ok := ruleIDToCategoryIDs[ruleOrCategoryID]; ok {
+			// Handled below, once all categories are handled.
+			ruleIDs = append(ruleIDs, ruleOrCategoryID)
+		} else if categoryRuleIDs, ok := categoryIDToRuleIDs[ruleOrCategoryID]; ok {
+			severity := ruleOrCategoryIDToSeverity[ruleOrCategoryID]
+			for _, ruleID := range transformRuleIDsToUndeprecated(categoryRuleIDs, deprecatedRuleIDToReplacementIDs) {
+				// A lower Severity is more severe.
+				if existingSeverity, ok := ruleIDToSeverity[ruleID]; !ok || severity < existingSeverity {
+					ruleIDToSeverity[ruleID] = severity
+				}
+			}
+		} else {
+			return nil, fmt.Errorf("%q is not a known rule or category ID", ruleOrCategoryID)
+		}
+	}
+	for _, ruleID := range ruleIDs {
+		severity := ruleOrCategoryIDToSeverity[ruleID]
+		for _, undeprecatedRuleID := range transformRuleIDsToUndeprecated([]string{ruleID}, deprecatedRuleIDToReplacementIDs) {
+			ruleIDToSeverity[undeprecatedRuleID] = severity
+		}
+	}
+	for ruleID, severity := range ruleIDToSeverity {
+		if severity == bufanalysis.SeverityError {
+			delete(ruleIDToSeverity, ruleID)
+		}
+	}
+	return ruleIDToSeverity, nil
+}
+
 func transformRuleIDsToUndeprecated(
 	ruleIDs []string,
 	deprecatedRuleIDToReplacementIDs map[string][]string,
@@ -18,6 +18,32 @@ import (
 	descriptorv1 "buf.build/gen/go/bufbuild/bufplugin/protocolbuffers/go/buf/plugin/descriptor/v1"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/pkg/standard/xslices"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	nameTypeTag             = int32(1)
+	packageTypeTag          = int32(2)
+	dependenciesTypeTag     = int32(3)
+	messagesTypeTag         = int32(4)
+	enumsTypeTag            = int32(5)
+	servicesTypeTag         = int32(6)
+	extensionsTypeTag       = int32(7)
+	fileOptionsTypeTag      = int32(8)
+	syntaxTypeTag           = int32(12)
+	editionTypeTag          = int32(14)
+	messageFieldsTypeTag    = int32(2)
+	nestedMessagesTypeTag   = int32(3)
+	nestedEnumsTypeTag      = int32(4)
+	nestedExtensionsTypeTag = int32(6)
+	oneofsTypeTag           = int32(8)
+	enumValuesTypeTag       = int32(2)
+	methodsTypeTag          = int32(2)
+	extendeeTypeTag         = int32(2)
+	typeNameTypeTag         = int32(6)
+	defaultValueTypeTag     = int32(7)
+	inputTypeTypeTag        = int32(2)
+	outputTypeTypeTag       = int32(3)
 )
 
 func imageToProtoFileDescriptors(image bufimage.Image) []*descriptorv1.FileDescriptor {
@@ -50,3 +76,61 @@ func imageToPathToExternalPath(image bufimage.Image) map[string]string {
 	}
 	return pathToExternalPath
 }
+
+// descriptorForSourcePath returns the innermost descriptor that contains the source path.
+func descriptorForSourcePath(file protoreflect.FileDescriptor, sourcePath protoreflect.SourcePath) protoreflect.Descriptor {
+	var descriptor protoreflect.Descriptor = file
+	for i := 0; i+1 < len(sourcePath); i += 2 {
+		typeTag, index := sourcePath[i], int(sourcePath[i+1])
+		var child protoreflect.Descriptor
+		switch parent := descriptor.(type) {
+		case protoreflect.FileDescriptor:
+			switch typeTag {
+			case messagesTypeTag:
+				child = getDescriptor(parent.Messages(), index)
+			case enumsTypeTag:
+				child = getDescriptor(parent.Enums(), index)
+			case servicesTypeTag:
+				child = getDescriptor(parent.Services(), index)
+			case extensionsTypeTag:
+				child = getDescriptor(parent.Extensions(), index)
+			}
+		case protoreflect.MessageDescriptor:
+			switch typeTag {
+			case messageFieldsTypeTag:
+				child = getDescriptor(parent.Fields(), index)
+			case nestedMessagesTypeTag:
+				child = getDescriptor(parent.Messages(), index)
+			case nestedEnumsTypeTag:
+				child = getDescriptor(parent.Enums(), index)
+			case nestedExtensionsTypeTag:
+				child = getDescriptor(parent.Extensions(), index)
+			case oneofsTypeTag:
+				child = getDescriptor(parent.Oneofs(), index)
+			}
+		case protoreflect.EnumDescriptor:
+			if typeTag == enumValuesTypeTag {
+				child = getDescriptor(parent.Values(), index)
+			}
+		case protoreflect.ServiceDescriptor:
+			if typeTag == methodsTypeTag {
+				child = getDescriptor(parent.Methods(), index)
+			}
+		}
+		if child == nil {
+			break
+		}
+		descriptor = child
+	}
+	return descriptor
+}
+
+func getDescriptor[T protoreflect.Descriptor](list interface {
+	Len() int
+	Get(int) T
+}, index int) protoreflect.Descriptor {
+	if index < 0 || index >= list.Len() {
+		return nil
+	}
+	return list.Get(index)
+}
@@ -26,6 +26,7 @@ import (
 	"slices"
 	"sort"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/pkg/encoding"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
@@ -1028,12 +1029,17 @@ func getLintConfigForExternalLintV1Beta1V1(
 				ignoreOnly[idOrCategory] = relPaths
 			}
 		}
+		idOrCategoryToSeverity, err := getIDOrCategoryToSeverityForExternalSeverity("lint.severity", externalLint.Severity)
+		if err != nil {
+			return nil, err
+		}
 		checkConfig, err = newEnabledCheckConfig(
 			fileVersion,
 			externalLint.Use,
 			externalLint.Except,
 			ignore,
 			ignoreOnly,
+			idOrCategoryToSeverity,
 			externalLint.DisableBuiltin,
 		)
 		if err != nil {
@@ -1079,12 +1085,17 @@ func getLintConfigForExternalLintV2(
 				ignoreOnly[idOrCategory] = relPaths
 			}
 		}
+		idOrCategoryToSeverity, err := getIDOrCategoryToSeverityForExternalSeverity("lint.severity", externalLint.Severity)
+		if err != nil {
+			return nil, err
+		}
 		checkConfig, err = newEnabledCheckConfig(
 			fileVersion,
 			externalLint.Use,
 			externalLint.Except,
 			ignore,
 			ignoreOnly,
+			idOrCategoryToSeverity,
 			externalLint.DisableBuiltin,
 		)
 		if err != nil {
@@ -1130,12 +1141,17 @@ func getBreakingConfigForExternalBreaking(
 				ignoreOnly[idOrCategory] = relPaths
 			}
 		}
+		idOrCategoryToSeverity, err := getIDOrCategoryToSeverityForExternalSeverity("breaking.severity", externalBreaking.Severity)
+		if err != nil {
+			return nil, err
+		}
 		checkConfig, err = newEnabledCheckConfig(
 			fileVersion,
 			externalBreaking.Use,
 			externalBreaking.Except,
 			ignore,
 			ignoreOnly,
+			idOrCategoryToSeverity,
 			externalBreaking.DisableBuiltin,
 		)
 		if err != nil {
@@ -1148,6 +1164,34 @@ func getBreakingConfigForExternalBreaking(
 	), nil
 }
 
+// getIDOrCategoryToSeverityForExternalSeverity parses the severities of the lint or breaking
+// rules and categories.
+func getIDOrCategoryToSeverityForExternalSeverity(
+	fieldName string,
+	externalSeverity map[string]string,
+) (map[string]bufanalysis.Severity, error) {
+	idOrCategoryToSeverity := make(map[string]bufanalysis.Severity, len(externalSeverity))
+	for idOrCategory, severityString := range externalSeverity {
+		severity, err := bufanalysis.ParseSeverity(severityString)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid severity for %q: %w", fieldName, idOrCategory, err)
+		}
+		idOrCategoryToSeverity[idOrCategory] = severity
+	}
+	return idOrCategoryToSeverity, nil
+}
+
+func getExternalSeverityForIDOrCategoryToSeverity(idOrCategoryToSeverity map[string]bufanalysis.Severity) map[string]string {
+	if len(idOrCategoryToSeverity) == 0 {
+		return nil
+	}
+	externalSeverity := make(map[string]string, len(idOrCategoryToSeverity))
+	for idOrCategory, severity := range idOrCategoryToSeverity {
+		externalSeverity[idOrCategory] = severity.String()
+	}
+	return externalSeverity
+}
+
 // isLintOrBreakingDisabledBasedOnIgnores returns true if lint or breaking should be entirely disabled
 // based on an ignore path equaling moduleDirPath.
 //
@@ -1234,6 +1278,7 @@ func getExternalLintV1Beta1V1ForLintConfig(lintConfig LintConfig, moduleDirPath
 	externalLint.RPCAllowGoogleProtobufEmptyResponses = lintConfig.RPCAllowGoogleProtobufEmptyResponses()
 	externalLint.ServiceSuffix = lintConfig.ServiceSuffix()
 	externalLint.AllowCommentIgnores = lintConfig.AllowCommentIgnores()
+	externalLint.Severity = getExternalSeverityForIDOrCategoryToSeverity(lintConfig.IDOrCategoryToSeverity())
 	externalLint.DisableBuiltin = lintConfig.DisableBuiltin()
 	return externalLint
 }
@@ -1257,6 +1302,7 @@ func getExternalLintV2ForLintConfig(lintConfig LintConfig, moduleDirPath string)
 	externalLint.RPCAllowGoogleProtobufEmptyResponses = lintConfig.RPCAllowGoogleProtobufEmptyResponses()
 	externalLint.ServiceSuffix = lintConfig.ServiceSuffix()
 	externalLint.DisallowCommentIgnores = !lintConfig.AllowCommentIgnores()
+	externalLint.Severity = getExternalSeverityForIDOrCategoryToSeverity(lintConfig.IDOrCategoryToSeverity())
 	externalLint.DisableBuiltin = lintConfig.DisableBuiltin()
 	return externalLint
 }
@@ -1275,6 +1321,7 @@ func getExternalBreakingForBreakingConfig(breakingConfig BreakingConfig, moduleD
 		externalBreaking.IgnoreOnly[idOrCategory] = xslices.Map(importPaths, joinDirPath)
 	}
 	externalBreaking.IgnoreUnstablePackages = breakingConfig.IgnoreUnstablePackages()
+	externalBreaking.Severity = getExternalSeverityForIDOrCategoryToSeverity(breakingConfig.IDOrCategoryToSeverity())
 	externalBreaking.DisableBuiltin = breakingConfig.DisableBuiltin()
 	return externalBreaking
 }
@@ -1343,8 +1390,10 @@ type externalBufYAMLFileLintV1Beta1V1 struct {
 	RPCAllowGoogleProtobufEmptyRequests  bool                `json:"rpc_allow_google_protobuf_empty_requests,omitempty" yaml:"rpc_allow_google_protobuf_empty_requests,omitempty"`
 	RPCAllowGoogleProtobufEmptyResponses bool                `json:"rpc_allow_google_protobuf_empty_responses,omitempty" yaml:"rpc_allow_google_protobuf_empty_responses,omitempty"`
 	ServiceSuffix                        string              `json:"service_suffix,omitempty" yaml:"service_suffix,omitempty"`
-	AllowCommentIgnores                  bool                `json:"allow_comment_ignores,omitempty" yaml:"allow_comment_ignores,omitempty"`
-	DisableBuiltin                       bool                `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
+	// Severity are the ID/category to severity.
+	Severity            map[string]string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	AllowCommentIgnores bool              `json:"allow_comment_ignores,omitempty" yaml:"allow_comment_ignores,omitempty"`
+	DisableBuiltin      bool              `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
 }
 
 // Suppressing unused warning. Keeping this function around for now.
@@ -1360,6 +1409,7 @@ func (el externalBufYAMLFileLintV1Beta1V1) isEmpty() bool {
 		!el.RPCAllowGoogleProtobufEmptyRequests &&
 		!el.RPCAllowGoogleProtobufEmptyResponses &&
 		el.ServiceSuffix == "" &&
+		len(el.Severity) == 0 &&
 		!el.AllowCommentIgnores &&
 		!el.DisableBuiltin
 }
@@ -1380,8 +1430,10 @@ type externalBufYAMLFileLintV2 struct {
 	RPCAllowGoogleProtobufEmptyRequests  bool                `json:"rpc_allow_google_protobuf_empty_requests,omitempty" yaml:"rpc_allow_google_protobuf_empty_requests,omitempty"`
 	RPCAllowGoogleProtobufEmptyResponses bool                `json:"rpc_allow_google_protobuf_empty_responses,omitempty" yaml:"rpc_allow_google_protobuf_empty_responses,omitempty"`
 	ServiceSuffix                        string              `json:"service_suffix,omitempty" yaml:"service_suffix,omitempty"`
-	DisallowCommentIgnores               bool                `json:"disallow_comment_ignores,omitempty" yaml:"disallow_comment_ignores,omitempty"`
-	DisableBuiltin                       bool                `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
+	// Severity are the ID/category to severity.
+	Severity               map[string]string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	DisallowCommentIgnores bool              `json:"disallow_comment_ignores,omitempty" yaml:"disallow_comment_ignores,omitempty"`
+	DisableBuiltin         bool              `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
 }
 
 func (el externalBufYAMLFileLintV2) isEmpty() bool {
@@ -1394,6 +1446,7 @@ func (el externalBufYAMLFileLintV2) isEmpty() bool {
 		!el.RPCAllowGoogleProtobufEmptyRequests &&
 		!el.RPCAllowGoogleProtobufEmptyResponses &&
 		el.ServiceSuffix == "" &&
+		len(el.Severity) == 0 &&
 		!el.DisallowCommentIgnores &&
 		!el.DisableBuiltin
 }
@@ -1411,7 +1464,9 @@ type externalBufYAMLFileBreakingV1Beta1V1V2 struct {
 	/// IgnoreOnly are the ID/category to paths to ignore.
 	IgnoreOnly             map[string][]string `json:"ignore_only,omitempty" yaml:"ignore_only,omitempty"`
 	IgnoreUnstablePackages bool                `json:"ignore_unstable_packages,omitempty" yaml:"ignore_unstable_packages,omitempty"`
-	DisableBuiltin         bool                `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
+	// Severity are the ID/category to severity.
+	Severity       map[string]string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	DisableBuiltin bool              `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
 }
 
 func (eb externalBufYAMLFileBreakingV1Beta1V1V2) isEmpty() bool {
@@ -1420,6 +1475,7 @@ func (eb externalBufYAMLFileBreakingV1Beta1V1V2) isEmpty() bool {
 		len(eb.Ignore) == 0 &&
 		len(eb.IgnoreOnly) == 0 &&
 		!eb.IgnoreUnstablePackages &&
+		len(eb.Severity) == 0 &&
 		!eb.DisableBuiltin
 }
 
@@ -503,6 +503,37 @@ policies:
     ignore_only:
       ENUM_PASCAL_CASE:
         - foo/foo.proto
+`,
+	)
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+lint:
+  use:
+    - STANDARD
+  severity:
+    COMMENTS: info
+    ENUM_ZERO_VALUE_SUFFIX: warning
+breaking:
+  use:
+    - FILE
+  severity:
+    FIELD_SAME_JSON_NAME: Warning
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - STANDARD
+  severity:
+    COMMENTS: info
+    ENUM_ZERO_VALUE_SUFFIX: warning
+breaking:
+  use:
+    - FILE
+  severity:
+    FIELD_SAME_JSON_NAME: warning
 `,
 	)
 }
@@ -617,6 +648,28 @@ modules:
 	require.True(t, moduleConfig1.BreakingConfig().Disabled())
 }
 
+func TestBufYAMLFileInvalidSeverity(t *testing.T) {
+	t.Parallel()
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  severity:
+    COMMENTS: fatal
+`,
+		`lint.severity: invalid severity for "COMMENTS": unknown severity: "fatal", must be one of error, warning, info`,
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v1
+breaking:
+  severity:
+    FILE: notice
+`,
+		`breaking.severity: invalid severity for "FILE": unknown severity: "notice", must be one of error, warning, info`,
+	)
+}
+
 func TestBufYAMLInvalidIncludes(t *testing.T) {
 	t.Parallel()
 	testReadBufYAMLFileFail(
@@ -15,8 +15,10 @@
 package bufconfig
 
 import (
+	"maps"
 	"slices"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/pkg/standard/xslices"
 )
 
@@ -27,6 +29,7 @@ var (
 		nil,
 		nil,
 		nil,
+		nil,
 		false,
 	)
 	defaultCheckConfigV2 = newEnabledCheckConfigNoValidate(
@@ -35,6 +38,7 @@ var (
 		nil,
 		nil,
 		nil,
+		nil,
 		false,
 	)
 )
@@ -98,6 +102,11 @@ type CheckConfig interface {
 	// Paths are relative to roots.
 	// Paths are sorted.
 	IgnoreIDOrCategoryToPaths() map[string][]string
+	// IDOrCategoryToSeverity returns the configured severities of IDs and categories.
+	//
+	// IDs and categories that are not in the map have bufanalysis.SeverityError.
+	// A severity for an ID takes precedence over a severity for a category that the ID is in.
+	IDOrCategoryToSeverity() map[string]bufanalysis.Severity
 	// DisableBuiltin says to disable the Rules and Categories builtin to the Buf CLI and only
 	// use plugins.
 	//
@@ -114,6 +123,7 @@ func NewEnabledCheckConfig(
 	except []string,
 	ignore []string,
 	ignoreOnly map[string][]string,
+	idOrCategoryToSeverity map[string]bufanalysis.Severity,
 	disableBuiltin bool,
 ) (CheckConfig, error) {
 	return newEnabledCheckConfig(
@@ -122,6 +132,7 @@ func NewEnabledCheckConfig(
 		except,
 		ignore,
 		ignoreOnly,
+		idOrCategoryToSeverity,
 		disableBuiltin,
 	)
 }
@@ -138,6 +149,7 @@ func NewEnabledCheckConfigForUseIDsAndCategories(
 		nil,
 		nil,
 		nil,
+		nil,
 		disableBuiltin,
 	)
 }
@@ -145,13 +157,14 @@ func NewEnabledCheckConfigForUseIDsAndCategories(
 // *** PRIVATE ***
 
 type checkConfig struct {
-	fileVersion    FileVersion
-	disabled       bool
-	use            []string
-	except         []string
-	ignore         []string
-	ignoreOnly     map[string][]string
-	disableBuiltin bool
+	fileVersion            FileVersion
+	disabled               bool
+	use                    []string
+	except                 []string
+	ignore                 []string
+	ignoreOnly             map[string][]string
+	idOrCategoryToSeverity map[string]bufanalysis.Severity
+	disableBuiltin         bool
 }
 
 func newEnabledCheckConfig(
@@ -160,6 +173,7 @@ func newEnabledCheckConfig(
 	except []string,
 	ignore []string,
 	ignoreOnly map[string][]string,
+	idOrCategoryToSeverity map[string]bufanalysis.Severity,
 	disableBuiltin bool,
 ) (*checkConfig, error) {
 	use = xslices.ToUniqueSorted(use)
@@ -179,8 +193,9 @@ func newEnabledCheckConfig(
 		newIgnoreOnly[k] = v
 	}
 	ignoreOnly = newIgnoreOnly
+	idOrCategoryToSeverity = maps.Clone(idOrCategoryToSeverity)
 
-	return newEnabledCheckConfigNoValidate(fileVersion, use, except, ignore, ignoreOnly, disableBuiltin), nil
+	return newEnabledCheckConfigNoValidate(fileVersion, use, except, ignore, ignoreOnly, idOrCategoryToSeverity, disableBuiltin), nil
 }
 
 func newEnabledCheckConfigNoValidate(
@@ -189,16 +204,18 @@ func newEnabledCheckConfigNoValidate(
 	except []string,
 	ignore []string,
 	ignoreOnly map[string][]string,
+	idOrCategoryToSeverity map[string]bufanalysis.Severity,
 	disableBuiltin bool,
 ) *checkConfig {
 	return &checkConfig{
-		fileVersion:    fileVersion,
-		disabled:       false,
-		use:            use,
-		except:         except,
-		ignore:         ignore,
-		ignoreOnly:     ignoreOnly,
-		disableBuiltin: disableBuiltin,
+		fileVersion:            fileVersion,
+		disabled:               false,
+		use:                    use,
+		except:                 except,
+		ignore:                 ignore,
+		ignoreOnly:             ignoreOnly,
+		idOrCategoryToSeverity: idOrCategoryToSeverity,
+		disableBuiltin:         disableBuiltin,
 	}
 }
 
@@ -233,6 +250,10 @@ func (c *checkConfig) IgnoreIDOrCategoryToPaths() map[string][]string {
 	return copyStringToStringSliceMap(c.ignoreOnly)
 }
 
+func (c *checkConfig) IDOrCategoryToSeverity() map[string]bufanalysis.Severity {
+	return maps.Clone(c.idOrCategoryToSeverity)
+}
+
 func (c *checkConfig) DisableBuiltin() bool {
 	return c.disableBuiltin
 }
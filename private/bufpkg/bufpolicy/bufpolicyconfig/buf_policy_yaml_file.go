@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"slices"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/pkg/encoding"
@@ -295,6 +296,8 @@ type externalBufPolicyYAMLFileLintV2 struct {
 	RPCAllowGoogleProtobufEmptyRequests  bool   `json:"rpc_allow_google_protobuf_empty_requests,omitempty" yaml:"rpc_allow_google_protobuf_empty_requests,omitempty"`
 	RPCAllowGoogleProtobufEmptyResponses bool   `json:"rpc_allow_google_protobuf_empty_responses,omitempty" yaml:"rpc_allow_google_protobuf_empty_responses,omitempty"`
 	ServiceSuffix                        string `json:"service_suffix,omitempty" yaml:"service_suffix,omitempty"`
+	// Severity are the ID/category to severity.
+	Severity map[string]string `json:"severity,omitempty" yaml:"severity,omitempty"`
 }
 
 func (el externalBufPolicyYAMLFileLintV2) isEmpty() bool {
@@ -304,7 +307,8 @@ func (el externalBufPolicyYAMLFileLintV2) isEmpty() bool {
 		!el.RPCAllowSameRequestResponse &&
 		!el.RPCAllowGoogleProtobufEmptyRequests &&
 		!el.RPCAllowGoogleProtobufEmptyResponses &&
-		el.ServiceSuffix == ""
+		el.ServiceSuffix == "" &&
+		len(el.Severity) == 0
 }
 
 // externalBufPolicyYAMLFileBreakingV2 represents breaking configuration within a v2 buf.policy.yaml file.
@@ -314,12 +318,15 @@ type externalBufPolicyYAMLFileBreakingV2 struct {
 	Use                    []string `json:"use,omitempty" yaml:"use,omitempty"`
 	Except                 []string `json:"except,omitempty" yaml:"except,omitempty"`
 	IgnoreUnstablePackages bool     `json:"ignore_unstable_packages,omitempty" yaml:"ignore_unstable_packages,omitempty"`
+	// Severity are the ID/category to severity.
+	Severity map[string]string `json:"severity,omitempty" yaml:"severity,omitempty"`
 }
 
 func (eb externalBufPolicyYAMLFileBreakingV2) isEmpty() bool {
 	return len(eb.Use) == 0 &&
 		len(eb.Except) == 0 &&
-		!eb.IgnoreUnstablePackages
+		!eb.IgnoreUnstablePackages &&
+		len(eb.Severity) == 0
 }
 
 // externalBufPolicyYAMLFilePluginV2 represents a single plugin config in a v2 buf.yaml file.
@@ -329,12 +336,17 @@ type externalBufPolicyYAMLFilePluginV2 struct {
 }
 
 func getLintConfigForExternalLintV2(externalLint externalBufPolicyYAMLFileLintV2) (bufconfig.LintConfig, error) {
+	idOrCategoryToSeverity, err := getIDOrCategoryToSeverityForExternalSeverity("lint.severity", externalLint.Severity)
+	if err != nil {
+		return nil, err
+	}
 	checkConfig, err := bufconfig.NewEnabledCheckConfig(
 		bufconfig.FileVersionV2,
 		externalLint.Use,
 		externalLint.Except,
 		nil,
 		nil,
+		idOrCategoryToSeverity,
 		false,
 	)
 	if err != nil {
@@ -361,16 +373,22 @@ func getExternalLintForLintConfig(lintConfig bufconfig.LintConfig) externalBufPo
 		RPCAllowGoogleProtobufEmptyRequests:  lintConfig.RPCAllowGoogleProtobufEmptyRequests(),
 		RPCAllowGoogleProtobufEmptyResponses: lintConfig.RPCAllowGoogleProtobufEmptyResponses(),
 		ServiceSuffix:                        lintConfig.ServiceSuffix(),
+		Severity:                             getExternalSeverityForIDOrCategoryToSeverity(lintConfig.IDOrCategoryToSeverity()),
 	}
 }
 
 func getBreakingConfigForExternalBreaking(externalBreaking externalBufPolicyYAMLFileBreakingV2) (bufconfig.BreakingConfig, error) {
+	idOrCategoryToSeverity, err := getIDOrCategoryToSeverityForExternalSeverity("breaking.severity", externalBreaking.Severity)
+	if err != nil {
+		return nil, err
+	}
 	checkConfig, err := bufconfig.NewEnabledCheckConfig(
 		bufconfig.FileVersionV2,
 		externalBreaking.Use,
 		externalBreaking.Except,
 		nil,
 		nil,
+		idOrCategoryToSeverity,
 		false,
 	)
 	if err != nil {
@@ -388,7 +406,34 @@ func getExternalBreakingForBreakingConfig(breakingConfig bufconfig.BreakingConfi
 		Use:                    breakingConfig.UseIDsAndCategories(),
 		Except:                 breakingConfig.ExceptIDsAndCategories(),
 		IgnoreUnstablePackages: breakingConfig.IgnoreUnstablePackages(),
+		Severity:               getExternalSeverityForIDOrCategoryToSeverity(breakingConfig.IDOrCategoryToSeverity()),
+	}
+}
+
+func getIDOrCategoryToSeverityForExternalSeverity(
+	fieldName string,
+	externalSeverity map[string]string,
+) (map[string]bufanalysis.Severity, error) {
+	idOrCategoryToSeverity := make(map[string]bufanalysis.Severity, len(externalSeverity))
+	for idOrCategory, severityString := range externalSeverity {
+		severity, err := bufanalysis.ParseSeverity(severityString)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid severity for %q: %w", fieldName, idOrCategory, err)
+		}
+		idOrCategoryToSeverity[idOrCategory] = severity
+	}
+	return idOrCategoryToSeverity, nil
+}
+
+func getExternalSeverityForIDOrCategoryToSeverity(idOrCategoryToSeverity map[string]bufanalysis.Severity) map[string]string {
+	if len(idOrCategoryToSeverity) == 0 {
+		return nil
+	}
+	externalSeverity := make(map[string]string, len(idOrCategoryToSeverity))
+	for idOrCategory, severity := range idOrCategoryToSeverity {
+		externalSeverity[idOrCategory] = severity.String()
 	}
+	return externalSeverity
 }
 
 func newPluginConfigForExternalPluginV2(externalConfig externalBufPolicyYAMLFilePluginV2) (bufconfig.PluginConfig, error) {
@@ -84,6 +84,36 @@ plugins:
     options:
       timestamp_suffix: _time
   - plugin: buf.build/bufbuild/buf-lint
+`,
+	)
+
+	testReadWriteBufPolicyYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+breaking:
+  use:
+    - FILE
+  severity:
+    FILE_NO_DELETE: warning
+lint:
+  use:
+    - STANDARD
+  severity:
+    COMMENTS: info
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - STANDARD
+  severity:
+    COMMENTS: info
+breaking:
+  use:
+    - FILE
+  severity:
+    FILE_NO_DELETE: warning
 `,
 	)
 }
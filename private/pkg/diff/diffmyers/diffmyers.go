@@ -144,7 +144,8 @@ func Print(from, to [][]byte, edits []Edit) ([]byte, error) {
 	var buffer bytes.Buffer
 	buffer.Grow(bufferSize)
 	for _, line := range out {
-		if line.hunk && len(line.line) > 0 {
+		if line.hunk {
+			// Hunks without edits have no header.
 			buffer.Write(line.line)
 			continue
 		}
@@ -65,6 +65,27 @@ func TestDiff(t *testing.T) {
 		})
 		testPrint(t, from, to, edits, "insert")
 	})
+	t.Run("change-after-context", func(t *testing.T) {
+		t.Parallel()
+		const from = "a\nb\nc\nd\ne\n"
+		const to = "a\nb\nc\nd\nf\n"
+		edits := diffmyers.Diff(
+			splitLines(from),
+			splitLines(to),
+		)
+		assert.Equal(t, edits, []diffmyers.Edit{
+			{
+				Kind:         diffmyers.EditKindDelete,
+				FromPosition: 4,
+			},
+			{
+				Kind:         diffmyers.EditKindInsert,
+				FromPosition: 5,
+				ToPosition:   4,
+			},
+		})
+		testPrint(t, from, to, edits, "change-after-context")
+	})
 	t.Run("delete-one", func(t *testing.T) {
 		t.Parallel()
 		const from = "Hello, world!\nGoodbye, world!\n"